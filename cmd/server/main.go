@@ -39,8 +39,14 @@ func main() {
 	svc := service.New(repo, cfg)
 	h := handler.New(svc)
 
+	// 启动后台任务
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	svc.StartClassificationScheduler(bgCtx)
+	svc.StartReportScheduler(bgCtx)
+
 	// 设置路由
-	r := router.Setup(h, web.StaticFS)
+	r := router.Setup(h, svc, web.StaticFS)
 
 	// 创建 HTTP 服务器
 	srv := &http.Server{
@@ -66,6 +72,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("正在关闭服务器...")
+	stopBackground()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
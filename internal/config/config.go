@@ -17,6 +17,31 @@ type Config struct {
 	DBPath         string
 	AdminUsername  string
 	AdminPassword  string
+
+	IdempotencyTTLHours int // 幂等键保留时长 (小时)
+
+	AdjustApprovalQty   int     // 调整数量超过该值需审批 (0 表示不限制)
+	AdjustApprovalValue float64 // 调整金额超过该值需审批 (0 表示不限制)
+
+	ReorderLookbackDays  int     // 计算日均消耗的历史天数
+	ReorderLeadTimeDays  int     // 供应商未设置提前期时的默认提前期 (天)
+	ReorderReviewDays    int     // 未设置最高库存时的补货覆盖天数
+	ReorderServiceLevelZ float64 // 安全库存服务水平系数 (1.65 ≈ 95%)
+
+	ClassifyPeriodDays    int // ABC/XYZ 分类统计天数
+	ClassifyIntervalHours int // ABC/XYZ 分类定时重算间隔 (小时), 0 表示不自动重算
+
+	DashboardCacheTTLSeconds int // 仪表盘聚合缓存有效期 (秒), 0 表示不缓存
+
+	CompanyName     string // 报表与单据页眉的公司名称
+	PDFFontPath     string // PDF 中文字体 (TrueType .ttf), 为空时查找常见系统路径
+	ReportOutputDir string // 定时报表按目录投递时的输出目录
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
 }
 
 // Global 全局配置实例
@@ -35,6 +60,31 @@ func Load() *Config {
 		DBPath:         getEnv("DB_PATH", "./data/cargo.db"),
 		AdminUsername:  getEnv("ADMIN_USERNAME", "admin"),
 		AdminPassword:  getEnv("ADMIN_PASSWORD", "admin123"),
+
+		IdempotencyTTLHours: getEnvInt("IDEMPOTENCY_TTL_HOURS", 24),
+
+		AdjustApprovalQty:   getEnvInt("ADJUST_APPROVAL_QTY", 0),
+		AdjustApprovalValue: getEnvFloat("ADJUST_APPROVAL_VALUE", 0),
+
+		ReorderLookbackDays:  getEnvInt("REORDER_LOOKBACK_DAYS", 90),
+		ReorderLeadTimeDays:  getEnvInt("REORDER_LEAD_TIME_DAYS", 7),
+		ReorderReviewDays:    getEnvInt("REORDER_REVIEW_DAYS", 14),
+		ReorderServiceLevelZ: getEnvFloat("REORDER_SERVICE_LEVEL_Z", 1.65),
+
+		ClassifyPeriodDays:    getEnvInt("CLASSIFY_PERIOD_DAYS", 365),
+		ClassifyIntervalHours: getEnvInt("CLASSIFY_INTERVAL_HOURS", 24),
+
+		DashboardCacheTTLSeconds: getEnvInt("DASHBOARD_CACHE_TTL_SECONDS", 60),
+
+		CompanyName:     getEnv("COMPANY_NAME", "Go-Cargo 仓储"),
+		PDFFontPath:     getEnv("PDF_FONT_PATH", ""),
+		ReportOutputDir: getEnv("REPORT_OUTPUT_DIR", "./data/reports"),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnvInt("SMTP_PORT", 25),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", ""),
 	}
 
 	Global = cfg
@@ -58,3 +108,13 @@ func getEnvInt(key string, defaultVal int) int {
 	}
 	return defaultVal
 }
+
+// getEnvFloat 获取浮点类型的环境变量
+func getEnvFloat(key string, defaultVal float64) float64 {
+	if val := os.Getenv(key); val != "" {
+		if floatVal, err := strconv.ParseFloat(val, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultVal
+}
@@ -71,6 +71,29 @@ func autoMigrate(db *gorm.DB) error {
 		&models.Supplier{},
 		&models.Product{},
 		&models.InventoryRecord{},
+		&models.ProductIdentifier{},
+		&models.ProductUnit{},
+		&models.ProductAttribute{},
+		&models.ProductVariantValue{},
+		&models.KitComponent{},
+		&models.KitAssembly{},
+		&models.Location{},
+		&models.BinStock{},
+		&models.PickList{},
+		&models.PickListLine{},
+		&models.PickListOrder{},
+		&models.SupplierReturn{},
+		&models.SupplierReturnItem{},
+		&models.IdempotencyKey{},
+		&models.StocktakeSession{},
+		&models.StocktakeItem{},
+		&models.StocktakeCount{},
+		&models.ReasonCode{},
+		&models.StockAdjustment{},
+		&models.PurchaseOrder{},
+		&models.PurchaseOrderItem{},
+		&models.ReportJob{},
+		&models.ReportJobRun{},
 	)
 }
 
@@ -123,6 +146,21 @@ func seedData(db *gorm.DB, cfg *config.Config) {
 		log.Println("[DB] 示例供应商数据已创建")
 	}
 
+	// 创建默认调整原因代码
+	var reasonCount int64
+	db.Model(&models.ReasonCode{}).Count(&reasonCount)
+	if reasonCount == 0 {
+		reasons := []models.ReasonCode{
+			{Code: "damage", Name: "损坏", Description: "运输或仓储过程中损坏", SortOrder: 1, Status: 1},
+			{Code: "theft", Name: "丢失/被盗", Description: "无法找回的丢失或被盗", SortOrder: 2, Status: 1},
+			{Code: models.ReasonCountCorrection, Name: "盘点更正", Description: "盘点差异更正", SortOrder: 3, Status: 1},
+			{Code: "expired", Name: "过期", Description: "超过保质期报废", SortOrder: 4, Status: 1},
+			{Code: "sample", Name: "样品", Description: "领用为样品", SortOrder: 5, Status: 1},
+		}
+		db.Create(&reasons)
+		log.Println("[DB] 默认调整原因代码已创建")
+	}
+
 	// 创建示例商品
 	var prodCount int64
 	db.Model(&models.Product{}).Count(&prodCount)
@@ -0,0 +1,218 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/qr"
+)
+
+// Symbology 条码类型
+type Symbology string
+
+const (
+	Code128 Symbology = "code128"
+	EAN13   Symbology = "ean13"
+	QR      Symbology = "qr"
+)
+
+// Valid 是否为支持的条码类型
+func (s Symbology) Valid() bool {
+	return s == Code128 || s == EAN13 || s == QR
+}
+
+// barcodeModuleMin 一维条码每个模块的最小宽度 (mm), 低于该值扫码枪难以识别
+const barcodeModuleMin = 0.25
+
+// EncodeBarcode 按类型编码条码; EAN-13 接受 12 位 (自动补校验位) 或 13 位数字
+func EncodeBarcode(sym Symbology, content string) (barcode.Barcode, error) {
+	if content == "" {
+		return nil, fmt.Errorf("条码内容不能为空")
+	}
+	var bc barcode.Barcode
+	var err error
+	switch sym {
+	case Code128:
+		bc, err = code128.Encode(content)
+	case EAN13:
+		if (len(content) != 12 && len(content) != 13) || strings.Trim(content, "0123456789") != "" {
+			return nil, fmt.Errorf("EAN-13 条码须为 12 或 13 位数字: %s", content)
+		}
+		bc, err = ean.Encode(content)
+	case QR:
+		bc, err = qr.Encode(content, qr.M, qr.Auto)
+	default:
+		return nil, fmt.Errorf("不支持的条码类型: %s", sym)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("生成条码失败: %w", err)
+	}
+	return bc, nil
+}
+
+// is2D 是否为二维码
+func is2D(bc barcode.Barcode) bool {
+	return bc.Metadata().Dimensions == 2
+}
+
+// isDark 模块是否为深色
+func isDark(bc barcode.Barcode, x, y int) bool {
+	r, _, _, _ := bc.At(x, y).RGBA()
+	return r < 0x8000
+}
+
+// barcodeQuiet 条码四周空白的模块数
+const barcodeQuiet = 4
+
+// barcodeRun 条码中的一个深色矩形 (单位: 像素)
+type barcodeRun struct{ x, y, w, h int }
+
+// rasterize 按 scale 像素/模块计算深色矩形, 返回矩形与整体宽高 (含四周空白)
+// height 为一维条码高度 (像素), 二维码忽略该值
+func rasterize(bc barcode.Barcode, scale, height int) ([]barcodeRun, int, int) {
+	if scale <= 0 {
+		scale = 2
+	}
+	modulesX, modulesY := bc.Bounds().Dx(), bc.Bounds().Dy()
+	barHeight := height
+	switch {
+	case is2D(bc):
+		barHeight = modulesY * scale
+	case barHeight <= 0:
+		barHeight = 80
+	}
+	pad := barcodeQuiet * scale
+
+	var runs []barcodeRun
+	if is2D(bc) {
+		for y := 0; y < modulesY; y++ {
+			forEachRun(modulesX, func(x int) bool { return isDark(bc, x, y) }, func(start, n int) {
+				runs = append(runs, barcodeRun{pad + start*scale, pad + y*scale, n * scale, scale})
+			})
+		}
+	} else {
+		forEachRun(modulesX, func(x int) bool { return isDark(bc, x, 0) }, func(start, n int) {
+			runs = append(runs, barcodeRun{pad + start*scale, pad, n * scale, barHeight})
+		})
+	}
+	return runs, modulesX*scale + 2*pad, barHeight + 2*pad
+}
+
+// BarcodePNG 渲染 PNG; scale 为每个模块的像素数, height 为一维条码高度 (像素)
+func BarcodePNG(bc barcode.Barcode, scale, height int) ([]byte, error) {
+	runs, width, total := rasterize(bc, scale, height)
+	img := image.NewGray(image.Rect(0, 0, width, total))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	for _, r := range runs {
+		draw.Draw(img, image.Rect(r.x, r.y, r.x+r.w, r.y+r.h), image.Black, image.Point{}, draw.Src)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// BarcodeSVG 渲染 SVG, 参数含义同 BarcodePNG
+func BarcodeSVG(bc barcode.Barcode, scale, height int) []byte {
+	runs, width, total := rasterize(bc, scale, height)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		width, total, width, total)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><g fill="#000">`, width, total)
+	for _, r := range runs {
+		fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d"/>`, r.x, r.y, r.w, r.h)
+	}
+	buf.WriteString("</g></svg>")
+	return buf.Bytes()
+}
+
+// forEachRun 遍历连续的深色模块段
+func forEachRun(n int, dark func(int) bool, fn func(start, length int)) {
+	start := -1
+	for i := 0; i <= n; i++ {
+		d := i < n && dark(i)
+		switch {
+		case d && start < 0:
+			start = i
+		case !d && start >= 0:
+			fn(start, i-start)
+			start = -1
+		}
+	}
+}
+
+// Barcode 在 (x, y) 处绘制宽 w、高 h 的条码 (矢量绘制), 条码下方附文字
+// 二维码按 w、h 中较小者绘制为正方形并居中
+func (d *Document) Barcode(sym Symbology, content string, x, y, w, h float64) error {
+	bc, err := EncodeBarcode(sym, content)
+	if err != nil {
+		return err
+	}
+	const textHeight = 3
+	if is2D(bc) {
+		size := h - textHeight
+		if w < size {
+			size = w
+		}
+		d.drawModules(bc, x+(w-size)/2, y, size)
+	} else {
+		d.drawBars(bc, x, y, w, h-textHeight)
+	}
+	d.SetFontStyle("", 7)
+	d.SetXY(x, y+h-textHeight)
+	d.CellFormat(w, textHeight, d.Fit(content, w), "", 0, "C", false, 0, "")
+	return nil
+}
+
+// Code128 绘制 Code128 条码
+func (d *Document) Code128(content string, x, y, w, h float64) error {
+	return d.Barcode(Code128, content, x, y, w, h)
+}
+
+// Code128Width 条码在最小模块宽度下所需的宽度 (mm)
+func Code128Width(content string) float64 {
+	bc, err := code128.Encode(content)
+	if err != nil {
+		return 0
+	}
+	return float64(bc.Bounds().Dx()) * barcodeModuleMin
+}
+
+// drawBars 绘制一维条码, 相邻的深色模块合并为一个矩形
+func (d *Document) drawBars(bc barcode.Barcode, x, y, w, h float64) {
+	modules := bc.Bounds().Dx()
+	if modules == 0 {
+		return
+	}
+	module := w / float64(modules)
+	offset := x + (w-module*float64(modules))/2
+
+	d.SetFillColor(0, 0, 0)
+	forEachRun(modules, func(i int) bool { return isDark(bc, i, 0) }, func(start, n int) {
+		d.Rect(offset+float64(start)*module, y, float64(n)*module, h, "F")
+	})
+}
+
+// drawModules 绘制二维码, 边长 size
+func (d *Document) drawModules(bc barcode.Barcode, x, y, size float64) {
+	modulesX, modulesY := bc.Bounds().Dx(), bc.Bounds().Dy()
+	if modulesX == 0 || modulesY == 0 {
+		return
+	}
+	module := size / float64(modulesX)
+
+	d.SetFillColor(0, 0, 0)
+	for row := 0; row < modulesY; row++ {
+		forEachRun(modulesX, func(i int) bool { return isDark(bc, i, row) }, func(start, n int) {
+			d.Rect(x+float64(start)*module, y+float64(row)*module, float64(n)*module, module, "F")
+		})
+	}
+}
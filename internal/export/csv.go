@@ -0,0 +1,26 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+)
+
+// renderCSV 渲染 CSV, 带 UTF-8 BOM 以便 Excel 正确识别中文
+func renderCSV(t *Table) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff")
+	w := csv.NewWriter(&buf)
+	if err := w.Write(t.Headers); err != nil {
+		return nil, err
+	}
+	if err := w.WriteAll(t.Rows); err != nil {
+		return nil, err
+	}
+	if len(t.Footer) > 0 {
+		if err := w.Write(t.Footer); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
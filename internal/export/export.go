@@ -0,0 +1,81 @@
+// Package export 提供表格报表的 CSV / XLSX / PDF 渲染
+package export
+
+import "fmt"
+
+// Format 导出格式
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+	PDF  Format = "pdf"
+)
+
+// Valid 是否为支持的格式
+func (f Format) Valid() bool {
+	return f == CSV || f == XLSX || f == PDF
+}
+
+// ContentType 返回 MIME 类型
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case PDF:
+		return "application/pdf"
+	}
+	return "application/octet-stream"
+}
+
+// Table 通用表格报表
+type Table struct {
+	Title    string
+	Subtitle string // 统计范围等说明
+	Headers  []string
+	Rows     [][]string
+	Footer   []string // 合计行, 可为空
+	Numeric  []bool   // 各列是否为数值 (右对齐 / XLSX 写入数字)
+	Barcodes []bool   // 各列是否在 PDF 中绘制为 Code128 条码
+}
+
+// Renderer 报表渲染器
+type Renderer struct {
+	Company string // 页眉公司名称
+	Fonts   *FontLoader
+}
+
+// Render 按格式渲染表格
+func (r *Renderer) Render(t *Table, format Format) ([]byte, error) {
+	switch format {
+	case CSV:
+		return renderCSV(t)
+	case XLSX:
+		return renderXLSX(t)
+	case PDF:
+		return r.renderTablePDF(t)
+	}
+	return nil, fmt.Errorf("不支持的导出格式: %s", format)
+}
+
+// isNumeric 判断列是否为数值列
+func (t *Table) isNumeric(col int) bool {
+	return col < len(t.Numeric) && t.Numeric[col]
+}
+
+// isBarcode 判断列是否绘制为条码
+func (t *Table) isBarcode(col int) bool {
+	return col < len(t.Barcodes) && t.Barcodes[col]
+}
+
+// hasBarcode 是否有条码列
+func (t *Table) hasBarcode() bool {
+	for i := range t.Barcodes {
+		if t.Barcodes[i] {
+			return true
+		}
+	}
+	return false
+}
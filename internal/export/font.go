@@ -0,0 +1,68 @@
+package export
+
+import (
+	"os"
+	"sync"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// defaultFontPaths 未配置字体时依次查找的 TrueType 字体 (需包含中文字形, 不支持 .ttc/.otf)
+var defaultFontPaths = []string{
+	"./data/fonts/cjk.ttf",
+	"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
+	"/usr/share/fonts/truetype/noto/NotoSansSC-Regular.ttf",
+	"/usr/share/fonts/truetype/arphic-gkai00mp/gkai00mp.ttf",
+	"/Library/Fonts/Arial Unicode.ttf",
+	"/System/Library/Fonts/Supplemental/Arial Unicode.ttf",
+	`C:\Windows\Fonts\simhei.ttf`,
+	"/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf", // 无中文字形, 仅保证西文可用
+}
+
+// fontFamily PDF 中注册的字体名称
+const fontFamily = "cargo"
+
+// FontLoader 延迟加载 PDF 字体, 只读取一次
+type FontLoader struct {
+	path string
+	once sync.Once
+	data []byte
+}
+
+// NewFontLoader 创建字体加载器, path 为空时查找默认路径
+func NewFontLoader(path string) *FontLoader {
+	return &FontLoader{path: path}
+}
+
+// load 读取字体文件, 均不可用时返回 nil
+func (l *FontLoader) load() []byte {
+	l.once.Do(func() {
+		paths := defaultFontPaths
+		if l.path != "" {
+			paths = append([]string{l.path}, paths...)
+		}
+		for _, p := range paths {
+			if data, err := os.ReadFile(p); err == nil {
+				l.data = data
+				return
+			}
+		}
+	})
+	return l.data
+}
+
+// Setup 在文档中注册字体, 返回字体名称与文本转换函数
+// 无可用 TrueType 字体时退回内置 Helvetica, 非西文字符无法显示
+func (l *FontLoader) Setup(pdf *fpdf.Fpdf) (string, func(string) string) {
+	if l != nil {
+		if data := l.load(); data != nil {
+			pdf.AddUTF8FontFromBytes(fontFamily, "", data)
+			pdf.AddUTF8FontFromBytes(fontFamily, "B", data)
+			if pdf.Ok() {
+				return fontFamily, func(s string) string { return s }
+			}
+			pdf.ClearError()
+		}
+	}
+	return "Helvetica", pdf.UnicodeTranslatorFromDescriptor("")
+}
@@ -0,0 +1,156 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Label 标签内容
+type Label struct {
+	Title     string // 第一行, 如商品名称或库位编码
+	Subtitle  string // 第二行, 如 SKU 与售价
+	Code      string // 条码内容
+	Symbology Symbology
+}
+
+// LabelLayout A4 标签纸版式, 尺寸单位为 mm
+type LabelLayout struct {
+	Name       string  `json:"name"`
+	Columns    int     `json:"columns"`
+	Rows       int     `json:"rows"`
+	Width      float64 `json:"width"`
+	Height     float64 `json:"height"`
+	MarginLeft float64 `json:"margin_left"`
+	MarginTop  float64 `json:"margin_top"`
+	GapX       float64 `json:"gap_x"`
+	GapY       float64 `json:"gap_y"`
+}
+
+// labelLayouts 常用 A4 不干胶标签纸
+var labelLayouts = map[string]LabelLayout{
+	"a4-2x4":  {Name: "a4-2x4", Columns: 2, Rows: 4, Width: 99.1, Height: 67.7, MarginLeft: 4.65, MarginTop: 13.1, GapX: 2.5},
+	"a4-2x7":  {Name: "a4-2x7", Columns: 2, Rows: 7, Width: 99.1, Height: 38.1, MarginLeft: 4.65, MarginTop: 15.15, GapX: 2.5},
+	"a4-3x8":  {Name: "a4-3x8", Columns: 3, Rows: 8, Width: 70, Height: 37, MarginTop: 0.5},
+	"a4-4x10": {Name: "a4-4x10", Columns: 4, Rows: 10, Width: 48.5, Height: 25.4, MarginLeft: 8, MarginTop: 21.5},
+}
+
+// DefaultLabelLayout 默认标签版式
+const DefaultLabelLayout = "a4-3x8"
+
+// LabelLayouts 返回全部内置版式 (按名称排序)
+func LabelLayouts() []LabelLayout {
+	result := make([]LabelLayout, 0, len(labelLayouts))
+	for _, l := range labelLayouts {
+		result = append(result, l)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// ResolveLabelLayout 按名称取内置版式; columns、rows 均大于 0 时按 10mm 页边距、2mm 间距自定义等分
+func ResolveLabelLayout(name string, columns, rows int) (LabelLayout, error) {
+	if columns > 0 || rows > 0 {
+		if columns <= 0 || rows <= 0 || columns > 10 || rows > 20 {
+			return LabelLayout{}, fmt.Errorf("自定义版式需 1~10 列、1~20 行")
+		}
+		const margin, gap = 10.0, 2.0
+		return LabelLayout{
+			Name:       fmt.Sprintf("custom-%dx%d", columns, rows),
+			Columns:    columns,
+			Rows:       rows,
+			Width:      (210 - 2*margin - float64(columns-1)*gap) / float64(columns),
+			Height:     (297 - 2*margin - float64(rows-1)*gap) / float64(rows),
+			MarginLeft: margin,
+			MarginTop:  margin,
+			GapX:       gap,
+			GapY:       gap,
+		}, nil
+	}
+	if name == "" {
+		name = DefaultLabelLayout
+	}
+	layout, ok := labelLayouts[name]
+	if !ok {
+		return LabelLayout{}, fmt.Errorf("未知的标签版式: %s", name)
+	}
+	return layout, nil
+}
+
+// LabelSheetPDF 按版式将标签依次排满 A4 页; skip 为首页跳过的已用标签数, border 为 true 时绘制裁切框
+func (r *Renderer) LabelSheetPDF(labels []Label, layout LabelLayout, skip int, border bool) ([]byte, error) {
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("没有可打印的标签")
+	}
+	perPage := layout.Columns * layout.Rows
+	if skip < 0 || skip >= perPage {
+		skip = 0
+	}
+
+	d := r.newDocument("标签", false)
+	d.SetMargins(0, 0, 0)
+	d.SetAutoPageBreak(false, 0)
+	for i, label := range labels {
+		slot := (i + skip) % perPage
+		if i == 0 || slot == 0 {
+			d.AddPage()
+		}
+		col, row := slot%layout.Columns, slot/layout.Columns
+		x := layout.MarginLeft + float64(col)*(layout.Width+layout.GapX)
+		y := layout.MarginTop + float64(row)*(layout.Height+layout.GapY)
+		if border {
+			d.SetDrawColor(200, 200, 200)
+			d.Rect(x, y, layout.Width, layout.Height, "D")
+			d.SetDrawColor(0, 0, 0)
+		}
+		if err := d.drawLabel(label, x, y, layout.Width, layout.Height); err != nil {
+			return nil, err
+		}
+	}
+	return d.Bytes()
+}
+
+// drawLabel 绘制单个标签: 一维码为上文下码, 二维码为左码右文
+func (d *Document) drawLabel(label Label, x, y, w, h float64) error {
+	const pad = 2.5
+	x, y, w, h = x+pad, y+pad, w-2*pad, h-2*pad
+	titleSize := 10.0
+	if h < 25 {
+		titleSize = 8
+	}
+	lineHeight := titleSize * 0.45
+
+	if label.Symbology == QR {
+		size := h
+		if size > w/2 {
+			size = w / 2
+		}
+		if err := d.Barcode(QR, label.Code, x, y, size, size); err != nil {
+			return err
+		}
+		x, w = x+size+2, w-size-2
+		d.SetXY(x, y)
+		d.SetFontStyle("B", titleSize)
+		d.MultiCell(w, lineHeight, d.Fit(label.Title, w*2), "", "L", false)
+		d.SetX(x)
+		d.SetFontStyle("", titleSize-2)
+		d.CellFormat(w, lineHeight, d.Fit(label.Subtitle, w), "", 0, "L", false, 0, "")
+		return nil
+	}
+
+	d.SetXY(x, y)
+	d.SetFontStyle("B", titleSize)
+	d.CellFormat(w, lineHeight, d.Fit(label.Title, w), "", 1, "L", false, 0, "")
+	used := lineHeight
+	if label.Subtitle != "" {
+		d.SetX(x)
+		d.SetFontStyle("", titleSize-2)
+		d.CellFormat(w, lineHeight, d.Fit(label.Subtitle, w), "", 1, "L", false, 0, "")
+		used += lineHeight
+	}
+	used += 1
+	barHeight := h - used
+	if barHeight > 25 {
+		barHeight = 25
+	}
+	return d.Barcode(label.Symbology, label.Code, x, y+used, w, barHeight)
+}
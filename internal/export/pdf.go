@@ -0,0 +1,281 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Document PDF 文档: A4, 带公司页眉与页码页脚
+type Document struct {
+	*fpdf.Fpdf
+	font string
+	tr   func(string) string
+}
+
+// newDocument 创建无页眉页脚的 A4 文档
+func (r *Renderer) newDocument(title string, landscape bool) *Document {
+	orientation := "P"
+	if landscape {
+		orientation = "L"
+	}
+	pdf := fpdf.New(orientation, "mm", "A4", "")
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("go-cargo", true)
+
+	d := &Document{Fpdf: pdf}
+	d.font, d.tr = r.Fonts.Setup(pdf)
+	return d
+}
+
+// NewDocument 创建 PDF 文档并添加首页
+func (r *Renderer) NewDocument(title string, landscape bool) *Document {
+	d := r.newDocument(title, landscape)
+	pdf := d.Fpdf
+	pdf.SetMargins(12, 12, 12)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("{nb}")
+	generated := time.Now().Format("2006-01-02 15:04")
+
+	pdf.SetHeaderFunc(func() {
+		width, _ := pdf.GetPageSize()
+		left, _, right, _ := pdf.GetMargins()
+		d.SetFontStyle("B", 10)
+		pdf.CellFormat(0, 5, d.Text(r.Company), "", 0, "L", false, 0, "")
+		d.SetFontStyle("", 8)
+		pdf.CellFormat(0, 5, d.Text("打印时间: "+generated), "", 1, "R", false, 0, "")
+		d.SetFontStyle("B", 14)
+		pdf.CellFormat(0, 8, d.Text(title), "", 1, "C", false, 0, "")
+		y := pdf.GetY() + 1
+		pdf.Line(left, y, width-right, y)
+		pdf.SetY(y + 3)
+		d.SetFontStyle("", 9)
+	})
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		d.SetFontStyle("", 8)
+		pdf.CellFormat(0, 5, d.Text(fmt.Sprintf("第 %d / {nb} 页", pdf.PageNo())), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+	return d
+}
+
+// Text 转换为当前字体可用的文本
+func (d *Document) Text(s string) string {
+	return d.tr(s)
+}
+
+// SetFontStyle 设置字体样式 ("" 或 "B") 与字号
+func (d *Document) SetFontStyle(style string, size float64) {
+	d.SetFont(d.font, style, size)
+}
+
+// ContentWidth 页面可用宽度
+func (d *Document) ContentWidth() float64 {
+	width, _ := d.GetPageSize()
+	left, _, right, _ := d.GetMargins()
+	return width - left - right
+}
+
+// EnsureSpace 当前页剩余高度不足 h 时换页, 返回是否换页
+func (d *Document) EnsureSpace(h float64) bool {
+	_, height := d.GetPageSize()
+	_, _, _, bottom := d.GetMargins()
+	if d.GetY()+h > height-bottom {
+		d.AddPage()
+		return true
+	}
+	return false
+}
+
+// Fit 截断文本使其宽度不超过 w
+func (d *Document) Fit(s string, w float64) string {
+	s = d.tr(s)
+	if d.GetStringWidth(s) <= w {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && d.GetStringWidth(string(runes)+"…") > w {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// Table 绘制表格, 换页时重复表头; widths 为空时按内容自动计算
+func (d *Document) Table(t *Table, widths []float64) {
+	if len(widths) != len(t.Headers) {
+		widths = d.columnWidths(t)
+	}
+	rowHeight := 7.0
+	if t.hasBarcode() {
+		rowHeight = 13
+	}
+
+	header := func() {
+		d.SetFontStyle("B", 9)
+		d.SetFillColor(235, 235, 235)
+		for i, h := range t.Headers {
+			d.CellFormat(widths[i], rowHeight, d.Fit(h, widths[i]-2), "1", 0, "C", true, 0, "")
+		}
+		d.Ln(-1)
+		d.SetFontStyle("", 9)
+	}
+	row := func(values []string, style string, barcodes bool) {
+		if d.EnsureSpace(rowHeight) {
+			header()
+		}
+		y := d.GetY()
+		for i := range t.Headers {
+			var v string
+			if i < len(values) {
+				v = values[i]
+			}
+			x := d.GetX()
+			if barcodes && t.isBarcode(i) && v != "" {
+				d.CellFormat(widths[i], rowHeight, "", "1", 0, "", false, 0, "")
+				if err := d.Code128(v, x+1, y+1, widths[i]-2, rowHeight-2); err != nil {
+					d.SetXY(x, y)
+					d.SetFontStyle(style, 9)
+					d.CellFormat(widths[i], rowHeight, d.Fit(v, widths[i]-2), "1", 0, "L", false, 0, "")
+				}
+				d.SetXY(x+widths[i], y)
+				continue
+			}
+			align := "L"
+			if t.isNumeric(i) {
+				align = "R"
+			}
+			d.SetFontStyle(style, 9)
+			d.CellFormat(widths[i], rowHeight, d.Fit(v, widths[i]-2), "1", 0, align, false, 0, "")
+		}
+		d.Ln(-1)
+	}
+
+	header()
+	for _, r := range t.Rows {
+		row(r, "", true)
+	}
+	if len(t.Footer) > 0 {
+		row(t.Footer, "B", false)
+	}
+}
+
+// InfoGrid 按 cols 列绘制 "标签: 值" 信息区
+func (d *Document) InfoGrid(pairs [][2]string, cols int) {
+	if cols <= 0 {
+		cols = 2
+	}
+	width := d.ContentWidth() / float64(cols)
+	for i, p := range pairs {
+		d.SetFontStyle("B", 9)
+		label := d.Text(p[0] + ": ")
+		lw := d.GetStringWidth(label) + 1
+		d.CellFormat(lw, 6, label, "", 0, "L", false, 0, "")
+		d.SetFontStyle("", 9)
+		ln := 0
+		if (i+1)%cols == 0 || i == len(pairs)-1 {
+			ln = 1
+		}
+		d.CellFormat(width-lw, 6, d.Fit(p[1], width-lw-1), "", ln, "L", false, 0, "")
+	}
+	d.Ln(2)
+}
+
+// Signatures 在页面下方绘制签字栏, labels 平均分布于一行
+func (d *Document) Signatures(labels ...string) {
+	if len(labels) == 0 {
+		return
+	}
+	const height = 16
+	d.EnsureSpace(height + 4)
+	d.Ln(8)
+	left, _, _, _ := d.GetMargins()
+	width := d.ContentWidth() / float64(len(labels))
+	y := d.GetY()
+	d.SetFontStyle("", 9)
+	for i, label := range labels {
+		x := left + float64(i)*width
+		d.SetXY(x, y)
+		text := d.Text(label + ": ")
+		lw := d.GetStringWidth(text) + 1
+		d.CellFormat(lw, 6, text, "", 0, "L", false, 0, "")
+		d.Line(x+lw, y+5, x+width-6, y+5)
+		d.SetXY(x, y+8)
+		d.CellFormat(width, 6, d.Text("日期:"), "", 0, "L", false, 0, "")
+		d.Line(x+lw, y+13, x+width-6, y+13)
+	}
+	d.SetXY(left, y+height)
+}
+
+// columnWidths 按表头与内容宽度分配列宽, 总宽等于页面可用宽度
+func (d *Document) columnWidths(t *Table) []float64 {
+	d.SetFontStyle("", 9)
+	widths := make([]float64, len(t.Headers))
+	measure := func(values []string) {
+		for i := range widths {
+			if i < len(values) {
+				if w := d.GetStringWidth(d.tr(values[i])) + 4; w > widths[i] {
+					widths[i] = w
+				}
+			}
+		}
+	}
+	measure(t.Headers)
+	for i, r := range t.Rows {
+		if i >= 200 { // 抽样前 200 行即可
+			break
+		}
+		measure(r)
+	}
+	measure(t.Footer)
+
+	// 条码列按最长内容预留最小模块宽度
+	for i := range widths {
+		if !t.isBarcode(i) {
+			continue
+		}
+		for _, r := range t.Rows {
+			if i < len(r) {
+				if w := Code128Width(r[i]) + 4; w > widths[i] {
+					widths[i] = w
+				}
+			}
+		}
+	}
+
+	var total float64
+	for i := range widths {
+		if widths[i] > 70 && !t.isBarcode(i) {
+			widths[i] = 70
+		}
+		total += widths[i]
+	}
+	scale := d.ContentWidth() / total
+	for i := range widths {
+		widths[i] *= scale
+	}
+	return widths
+}
+
+// Bytes 输出 PDF 内容
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderTablePDF 渲染表格 PDF, 超过 6 列时横向排版
+func (r *Renderer) renderTablePDF(t *Table) ([]byte, error) {
+	d := r.NewDocument(t.Title, len(t.Headers) > 6)
+	if t.Subtitle != "" {
+		d.SetFontStyle("", 9)
+		d.CellFormat(0, 6, d.Text(t.Subtitle), "", 1, "L", false, 0, "")
+		d.Ln(2)
+	}
+	d.Table(t, nil)
+	return d.Bytes()
+}
@@ -0,0 +1,119 @@
+package export
+
+import (
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// renderXLSX 渲染 XLSX: 首行标题, 次行说明, 之后为表头与数据
+func renderXLSX(t *Table) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := "Sheet1"
+	if t.Title != "" {
+		sheet = sheetName(t.Title)
+		if err := f.SetSheetName("Sheet1", sheet); err != nil {
+			return nil, err
+		}
+	}
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	row := 1
+	// typed 为 true 时数值列写入数字
+	setRow := func(values []string, style int, typed bool) error {
+		for col, v := range values {
+			cell, err := excelize.CoordinatesToCellName(col+1, row)
+			if err != nil {
+				return err
+			}
+			var value interface{} = v
+			if typed && t.isNumeric(col) {
+				if n, err := strconv.ParseFloat(v, 64); err == nil {
+					value = n
+				}
+			}
+			if err := f.SetCellValue(sheet, cell, value); err != nil {
+				return err
+			}
+			if style != 0 {
+				if err := f.SetCellStyle(sheet, cell, cell, style); err != nil {
+					return err
+				}
+			}
+		}
+		row++
+		return nil
+	}
+
+	if t.Title != "" {
+		if err := setRow([]string{t.Title}, bold, false); err != nil {
+			return nil, err
+		}
+	}
+	if t.Subtitle != "" {
+		if err := setRow([]string{t.Subtitle}, 0, false); err != nil {
+			return nil, err
+		}
+	}
+	if row > 1 {
+		row++ // 空一行
+	}
+	headerRow := row
+	if err := setRow(t.Headers, bold, false); err != nil {
+		return nil, err
+	}
+	for _, r := range t.Rows {
+		if err := setRow(r, 0, true); err != nil {
+			return nil, err
+		}
+	}
+	if len(t.Footer) > 0 {
+		if err := setRow(t.Footer, bold, true); err != nil {
+			return nil, err
+		}
+	}
+
+	// 冻结表头
+	if err := f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      headerRow,
+		TopLeftCell: "A" + strconv.Itoa(headerRow+1),
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return nil, err
+	}
+	for col := range t.Headers {
+		name, _ := excelize.ColumnNumberToName(col + 1)
+		_ = f.SetColWidth(sheet, name, name, 16)
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sheetName 工作表名称最长 31 个字符且不能包含特殊字符
+func sheetName(title string) string {
+	var out []rune
+	for _, r := range title {
+		switch r {
+		case ':', '\\', '/', '?', '*', '[', ']':
+			continue
+		}
+		out = append(out, r)
+		if len(out) == 31 {
+			break
+		}
+	}
+	if len(out) == 0 {
+		return "Sheet1"
+	}
+	return string(out)
+}
@@ -0,0 +1,105 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ZPLOptions 热敏标签尺寸
+type ZPLOptions struct {
+	WidthMM  float64 // 标签宽度, 默认 60
+	HeightMM float64 // 标签高度, 默认 40
+	DPI      int     // 打印机分辨率, 默认 203 (8 点/mm)
+}
+
+// LabelsZPL 生成 ZPL II 指令, 每个标签一个 ^XA...^XZ 块
+// 输出只取决于输入, 可直接与预期文件比对; 中文需打印机已加载支持 UTF-8 的字体
+func LabelsZPL(labels []Label, opt ZPLOptions) ([]byte, error) {
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("没有可打印的标签")
+	}
+	if opt.WidthMM <= 0 {
+		opt.WidthMM = 60
+	}
+	if opt.HeightMM <= 0 {
+		opt.HeightMM = 40
+	}
+	if opt.DPI <= 0 {
+		opt.DPI = 203
+	}
+	dotsPerMM := float64(opt.DPI) / 25.4
+	width := int(opt.WidthMM*dotsPerMM + 0.5)
+	height := int(opt.HeightMM*dotsPerMM + 0.5)
+	pad := int(2*dotsPerMM + 0.5)
+	titleH := height / 8
+	subH := titleH * 3 / 4
+	// 字符宽度按字高的 3/5 估算, 过小的标签放不下任何文字
+	if subH*3/5 < 1 {
+		return nil, fmt.Errorf("标签尺寸过小: %.0fx%.0fmm", opt.WidthMM, opt.HeightMM)
+	}
+	chars := func(h int) int { return (width - 2*pad) / (h * 3 / 5) }
+
+	var buf bytes.Buffer
+	for _, label := range labels {
+		if _, err := EncodeBarcode(label.Symbology, label.Code); err != nil {
+			return nil, err
+		}
+
+		buf.WriteString("^XA\n^CI28\n")
+		fmt.Fprintf(&buf, "^PW%d\n^LL%d\n", width, height)
+
+		textX := pad
+		if label.Symbology == QR {
+			// 左侧二维码, 右侧文字
+			mag := (height - 2*pad) / 30
+			if mag < 2 {
+				mag = 2
+			}
+			if mag > 10 {
+				mag = 10
+			}
+			fmt.Fprintf(&buf, "^FO%d,%d^BQN,2,%d^FH_^FDMA,%s^FS\n", pad, pad, mag, zplEscape(label.Code))
+			textX = width / 2
+			maxChars := (width - textX - pad) / (titleH * 3 / 5)
+			fmt.Fprintf(&buf, "^FO%d,%d^A0N,%d,%d^FB%d,3,0,L^FH_^FD%s^FS\n",
+				textX, pad, titleH, titleH, width-textX-pad, zplEscape(truncateRunes(label.Title, maxChars*3)))
+			fmt.Fprintf(&buf, "^FO%d,%d^A0N,%d,%d^FH_^FD%s^FS\n",
+				textX, height-pad-subH, subH, subH, zplEscape(truncateRunes(label.Subtitle, maxChars)))
+			buf.WriteString("^XZ\n")
+			continue
+		}
+
+		y := pad
+		fmt.Fprintf(&buf, "^FO%d,%d^A0N,%d,%d^FH_^FD%s^FS\n", textX, y, titleH, titleH, zplEscape(truncateRunes(label.Title, chars(titleH))))
+		y += titleH + pad/2
+		if label.Subtitle != "" {
+			fmt.Fprintf(&buf, "^FO%d,%d^A0N,%d,%d^FH_^FD%s^FS\n", textX, y, subH, subH, zplEscape(truncateRunes(label.Subtitle, chars(subH))))
+			y += subH + pad/2
+		}
+		barHeight := height - y - pad - subH // 留出条码下方的可读文字
+		switch label.Symbology {
+		case EAN13:
+			// ^BE 只需前 12 位, 校验位由打印机计算
+			fmt.Fprintf(&buf, "^FO%d,%d^BY2^BEN,%d,Y,N^FD%s^FS\n", textX, y, barHeight, label.Code[:12])
+		default:
+			fmt.Fprintf(&buf, "^FO%d,%d^BY2^BCN,%d,Y,N,N^FH_^FD%s^FS\n", textX, y, barHeight, zplEscape(label.Code))
+		}
+		buf.WriteString("^XZ\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// zplEscape 配合 ^FH_ 转义字段中的控制字符
+func zplEscape(s string) string {
+	return strings.NewReplacer("_", "_5F", "^", "_5E", "~", "_7E").Replace(s)
+}
+
+// truncateRunes 截断为最多 n 个字符
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if n <= 0 || len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
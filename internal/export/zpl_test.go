@@ -0,0 +1,91 @@
+package export
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "用当前输出覆盖 testdata 中的预期文件")
+
+func TestLabelsZPLGolden(t *testing.T) {
+	cases := []struct {
+		name   string
+		labels []Label
+		opt    ZPLOptions
+	}{
+		{
+			name: "code128",
+			labels: []Label{
+				{Title: "无线鼠标", Subtitle: "P001  ¥99.00", Code: "P001", Symbology: Code128},
+				{Title: "A-01-02", Code: "A-01-02", Symbology: Code128},
+			},
+		},
+		{
+			name: "ean13",
+			labels: []Label{
+				{Title: "机械键盘 87 键", Subtitle: "P002  ¥299.00", Code: "6901234567892", Symbology: EAN13},
+			},
+		},
+		{
+			name: "qr",
+			labels: []Label{
+				{Title: "USB-C 扩展坞 七合一", Subtitle: "P008", Code: "P008", Symbology: QR},
+			},
+		},
+		{
+			name: "custom-size",
+			labels: []Label{
+				{Title: "特殊字符 ^~_", Subtitle: "SKU_01", Code: "SKU_01^", Symbology: Code128},
+			},
+			opt: ZPLOptions{WidthMM: 100, HeightMM: 50, DPI: 300},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := LabelsZPL(tc.labels, tc.opt)
+			if err != nil {
+				t.Fatalf("生成 ZPL 失败: %v", err)
+			}
+			golden := filepath.Join("testdata", "zpl", tc.name+".zpl")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("读取预期文件失败 (可用 -update 生成): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("输出与 %s 不一致:\n--- 实际 ---\n%s\n--- 预期 ---\n%s", golden, got, want)
+			}
+		})
+	}
+}
+
+func TestLabelsZPLErrors(t *testing.T) {
+	valid := []Label{{Title: "A", Code: "A", Symbology: Code128}}
+	cases := []struct {
+		name   string
+		labels []Label
+		opt    ZPLOptions
+	}{
+		{"empty", nil, ZPLOptions{}},
+		{"too-small", valid, ZPLOptions{HeightMM: 1}},
+		{"invalid-ean13", []Label{{Title: "A", Code: "123", Symbology: EAN13}}, ZPLOptions{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := LabelsZPL(tc.labels, tc.opt); err == nil {
+				t.Error("期望返回错误")
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+// Package gs1 解析 GS1-128 条码中的应用标识符 (AI), 如 GTIN、批号、有效期
+package gs1
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GroupSeparator 变长字段结束符 (扫描枪将 FNC1 输出为 ASCII 29)
+const GroupSeparator = "\x1d"
+
+// symbologyPrefixes 扫描枪附加的 GS1 码制标识 (GS1-128 / DataBar / DataMatrix / QR)
+var symbologyPrefixes = []string{"]C1", "]e0", "]d2", "]Q3"}
+
+// Element 单个应用标识符及其值
+type Element struct {
+	AI    string `json:"ai"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Data 解析结果, 常用字段单独列出, 日期格式为 YYYY-MM-DD
+type Data struct {
+	GTIN           string    `json:"gtin,omitempty"`            // (01) 贸易项目代码
+	Lot            string    `json:"lot,omitempty"`             // (10) 批号
+	ProductionDate string    `json:"production_date,omitempty"` // (11) 生产日期
+	BestBefore     string    `json:"best_before,omitempty"`     // (15) 保质期
+	Expiry         string    `json:"expiry,omitempty"`          // (17) 有效期
+	Serial         string    `json:"serial,omitempty"`          // (21) 序列号
+	Count          int       `json:"count,omitempty"`           // (30)/(37) 数量
+	Elements       []Element `json:"elements"`
+}
+
+// definition 应用标识符定义: length 为定长字段长度, 为 0 时按 max 截取变长字段
+type definition struct {
+	name   string
+	length int
+	max    int
+	kind   fieldKind
+}
+
+type fieldKind int
+
+const (
+	alphanumeric fieldKind = iota
+	numeric
+	date
+	measure
+)
+
+var definitions = map[string]definition{
+	"00":  {"SSCC", 18, 0, numeric},
+	"01":  {"GTIN", 14, 0, numeric},
+	"02":  {"CONTENT", 14, 0, numeric},
+	"10":  {"BATCH/LOT", 0, 20, alphanumeric},
+	"11":  {"PROD DATE", 6, 0, date},
+	"12":  {"DUE DATE", 6, 0, date},
+	"13":  {"PACK DATE", 6, 0, date},
+	"15":  {"BEST BEFORE", 6, 0, date},
+	"16":  {"SELL BY", 6, 0, date},
+	"17":  {"USE BY", 6, 0, date},
+	"20":  {"VARIANT", 2, 0, numeric},
+	"21":  {"SERIAL", 0, 20, alphanumeric},
+	"22":  {"CPV", 0, 20, alphanumeric},
+	"30":  {"VAR. COUNT", 0, 8, numeric},
+	"37":  {"COUNT", 0, 8, numeric},
+	"240": {"ADDITIONAL ID", 0, 30, alphanumeric},
+	"241": {"CUST. PART No.", 0, 30, alphanumeric},
+	"250": {"SECONDARY SERIAL", 0, 30, alphanumeric},
+	"251": {"REF. TO SOURCE", 0, 30, alphanumeric},
+	"400": {"ORDER NUMBER", 0, 30, alphanumeric},
+	"410": {"SHIP TO LOC", 13, 0, numeric},
+	"414": {"LOC No.", 13, 0, numeric},
+}
+
+// measureNames 4 位计量类标识符 (前 3 位, 第 4 位为小数位数), 值为 6 位定长数字
+var measureNames = map[string]string{
+	"310": "NET WEIGHT (kg)",
+	"311": "LENGTH (m)",
+	"312": "WIDTH (m)",
+	"313": "HEIGHT (m)",
+	"314": "AREA (m2)",
+	"315": "NET VOLUME (l)",
+	"316": "NET VOLUME (m3)",
+	"330": "GROSS WEIGHT (kg)",
+}
+
+var bracketed = regexp.MustCompile(`^\((\d{2,4})\)([^()]*)`)
+
+// Parse 解析扫描内容; 第二个返回值表示内容是否为 GS1 格式
+// 支持带码制标识或 FNC1 分隔符的原始扫描结果, 以及 "(01)...(17)..." 人工可读格式;
+// 无任何标识的纯数字内容仅在以 (01) + 校验正确的 GTIN 开头时视为 GS1
+func Parse(code string) (*Data, bool, error) {
+	s := code
+	explicit := false
+	for _, prefix := range symbologyPrefixes {
+		if strings.HasPrefix(s, prefix) {
+			s = s[len(prefix):]
+			explicit = true
+			break
+		}
+	}
+	if strings.HasPrefix(s, "(") {
+		data, err := parseBracketed(s)
+		return data, true, err
+	}
+	if strings.HasPrefix(s, GroupSeparator) {
+		s = s[1:]
+		explicit = true
+	}
+	if strings.Contains(s, GroupSeparator) {
+		explicit = true
+	}
+	if !explicit && !looksLikeGS1(s) {
+		return nil, false, nil
+	}
+	data, err := parseRaw(s)
+	return data, true, err
+}
+
+// looksLikeGS1 判断无标识的内容是否以 (01) 开头
+func looksLikeGS1(s string) bool {
+	return len(s) >= 16 && s[:2] == "01" && isDigits(s[2:16]) && ValidCheckDigit(s[2:16])
+}
+
+// parseRaw 解析连续编码的内容, 变长字段以 GroupSeparator 结束
+func parseRaw(s string) (*Data, error) {
+	data := &Data{}
+	for i := 0; i < len(s); {
+		ai, def, ok := lookup(s[i:])
+		if !ok {
+			return nil, fmt.Errorf("无法识别的应用标识符: %s", truncate(s[i:], 4))
+		}
+		i += len(ai)
+
+		var value string
+		if def.length > 0 {
+			if i+def.length > len(s) {
+				return nil, fmt.Errorf("(%s) 长度不足 %d 位", ai, def.length)
+			}
+			value = s[i : i+def.length]
+			i += def.length
+			// 部分编码器在定长字段后也输出分隔符
+			if strings.HasPrefix(s[i:], GroupSeparator) {
+				i++
+			}
+		} else {
+			end := strings.Index(s[i:], GroupSeparator)
+			if end < 0 {
+				value = s[i:]
+				i = len(s)
+			} else {
+				value = s[i : i+end]
+				i += end + 1
+			}
+		}
+		if err := data.add(ai, def, value); err != nil {
+			return nil, err
+		}
+	}
+	if len(data.Elements) == 0 {
+		return nil, fmt.Errorf("未包含任何应用标识符")
+	}
+	return data, nil
+}
+
+// parseBracketed 解析 "(01)...(10)..." 格式
+func parseBracketed(s string) (*Data, error) {
+	data := &Data{}
+	for s != "" {
+		m := bracketed.FindStringSubmatch(s)
+		if m == nil {
+			return nil, fmt.Errorf("格式错误: %s", truncate(s, 10))
+		}
+		ai, value := m[1], m[2]
+		matched, def, ok := lookup(ai)
+		if !ok || matched != ai {
+			return nil, fmt.Errorf("无法识别的应用标识符: %s", ai)
+		}
+		if def.length > 0 && len(value) != def.length {
+			return nil, fmt.Errorf("(%s) 须为 %d 位", ai, def.length)
+		}
+		if err := data.add(ai, def, value); err != nil {
+			return nil, err
+		}
+		s = s[len(m[0]):]
+	}
+	return data, nil
+}
+
+// lookup 按 2、3、4 位前缀查找应用标识符定义
+func lookup(s string) (string, definition, bool) {
+	for _, n := range []int{2, 3} {
+		if len(s) >= n {
+			if def, ok := definitions[s[:n]]; ok {
+				return s[:n], def, true
+			}
+		}
+	}
+	if len(s) >= 4 && isDigits(s[:4]) {
+		if name, ok := measureNames[s[:3]]; ok {
+			return s[:4], definition{name, 6, 0, measure}, true
+		}
+	}
+	return "", definition{}, false
+}
+
+// add 校验字段值并写入结果
+func (d *Data) add(ai string, def definition, value string) error {
+	if def.length == 0 && (value == "" || len(value) > def.max) {
+		return fmt.Errorf("(%s) 长度须为 1-%d 位", ai, def.max)
+	}
+	switch def.kind {
+	case numeric, measure:
+		if !isDigits(value) {
+			return fmt.Errorf("(%s) 须为数字: %s", ai, value)
+		}
+	case date:
+		v, err := parseDate(value)
+		if err != nil {
+			return fmt.Errorf("(%s) 日期无效: %s", ai, value)
+		}
+		value = v
+	}
+	if def.kind == measure {
+		decimals := int(ai[3] - '0')
+		value = insertDecimal(value, decimals)
+	}
+
+	switch ai {
+	case "01":
+		if !ValidCheckDigit(value) {
+			return fmt.Errorf("(01) GTIN 校验位错误: %s", value)
+		}
+		d.GTIN = value
+	case "10":
+		d.Lot = value
+	case "11":
+		d.ProductionDate = value
+	case "15":
+		d.BestBefore = value
+	case "17":
+		d.Expiry = value
+	case "21":
+		d.Serial = value
+	case "30", "37":
+		d.Count, _ = strconv.Atoi(value)
+	}
+	d.Elements = append(d.Elements, Element{AI: ai, Name: def.name, Value: value})
+	return nil
+}
+
+// parseDate 解析 YYMMDD; 日为 00 时表示当月最后一天
+func parseDate(v string) (string, error) {
+	if len(v) != 6 || !isDigits(v) {
+		return "", fmt.Errorf("invalid date")
+	}
+	year, _ := strconv.Atoi(v[0:2])
+	month, _ := strconv.Atoi(v[2:4])
+	day, _ := strconv.Atoi(v[4:6])
+	if month < 1 || month > 12 {
+		return "", fmt.Errorf("invalid month")
+	}
+	first := time.Date(2000+year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+	if day == 0 {
+		day = last.Day()
+	}
+	if day > last.Day() {
+		return "", fmt.Errorf("invalid day")
+	}
+	return first.AddDate(0, 0, day-1).Format("2006-01-02"), nil
+}
+
+// insertDecimal 按小数位数插入小数点, 如 ("001250", 3) => "1.250"
+func insertDecimal(v string, decimals int) string {
+	if decimals == 0 {
+		return strings.TrimLeft(v[:len(v)-1], "0") + v[len(v)-1:]
+	}
+	intPart := strings.TrimLeft(v[:len(v)-decimals], "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	return intPart + "." + v[len(v)-decimals:]
+}
+
+// ValidCheckDigit 校验 GTIN-8/12/13/14 的校验位
+func ValidCheckDigit(code string) bool {
+	if len(code) < 8 || len(code) > 14 || !isDigits(code) {
+		return false
+	}
+	sum := 0
+	for i := len(code) - 2; i >= 0; i-- {
+		d := int(code[i] - '0')
+		if (len(code)-2-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10-sum%10)%10 == int(code[len(code)-1]-'0')
+}
+
+// GTINVariants 返回同一 GTIN 的各种长度表示 (GTIN-8/12/13/14, 前补 0),
+// 用于匹配以 UPC-A、EAN-13 或 GTIN-14 形式登记的条码; 非合法 GTIN 时返回 nil
+func GTINVariants(code string) []string {
+	if !ValidCheckDigit(code) {
+		return nil
+	}
+	digits := strings.TrimLeft(code, "0")
+	var variants []string
+	for _, n := range []int{8, 12, 13, 14} {
+		if len(digits) <= n {
+			variants = append(variants, strings.Repeat("0", n-len(digits))+digits)
+		}
+	}
+	return variants
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n] + "..."
+	}
+	return s
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListReasonCodes 获取原因代码列表
+func (h *Handler) ListReasonCodes(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+	query.GetOffset()
+
+	reasons, total, err := h.svc.ListReasonCodes(&query)
+	if err != nil {
+		Error(c, 500, "获取原因代码列表失败")
+		return
+	}
+	Paginated(c, reasons, total, query.Page, query.PageSize)
+}
+
+// GetAllReasonCodes 获取所有启用原因代码 (下拉选择用)
+func (h *Handler) GetAllReasonCodes(c *gin.Context) {
+	reasons, err := h.svc.GetAllReasonCodes()
+	if err != nil {
+		Error(c, 500, "获取原因代码失败")
+		return
+	}
+	Success(c, reasons)
+}
+
+// CreateReasonCode 创建原因代码
+func (h *Handler) CreateReasonCode(c *gin.Context) {
+	var req models.ReasonCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	reason, err := h.svc.CreateReasonCode(&req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Created(c, reason)
+}
+
+// UpdateReasonCode 更新原因代码
+func (h *Handler) UpdateReasonCode(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的原因代码ID")
+		return
+	}
+
+	var req models.ReasonCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	reason, err := h.svc.UpdateReasonCode(uint(id), &req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, reason)
+}
+
+// DeleteReasonCode 删除原因代码
+func (h *Handler) DeleteReasonCode(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的原因代码ID")
+		return
+	}
+
+	if err := h.svc.DeleteReasonCode(uint(id)); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, nil)
+}
+
+// ListStockAdjustments 获取调整审批单列表
+func (h *Handler) ListStockAdjustments(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+	query.GetOffset()
+
+	adjs, total, err := h.svc.ListStockAdjustments(&query, c.Query("status"))
+	if err != nil {
+		Error(c, 500, "获取调整单列表失败")
+		return
+	}
+	Paginated(c, adjs, total, query.Page, query.PageSize)
+}
+
+// ApproveStockAdjustment 批准调整单
+func (h *Handler) ApproveStockAdjustment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的调整单ID")
+		return
+	}
+
+	var req models.AdjustmentReviewRequest
+	_ = c.ShouldBindJSON(&req) // 请求体可选
+
+	adj, err := h.svc.ApproveStockAdjustment(uint(id), &req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, adj)
+}
+
+// RejectStockAdjustment 驳回调整单
+func (h *Handler) RejectStockAdjustment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的调整单ID")
+		return
+	}
+
+	var req models.AdjustmentReviewRequest
+	_ = c.ShouldBindJSON(&req) // 请求体可选
+
+	adj, err := h.svc.RejectStockAdjustment(uint(id), &req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, adj)
+}
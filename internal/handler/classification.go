@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnalyzeClassification 获取 ABC/XYZ 分类分析 (不写入商品)
+func (h *Handler) AnalyzeClassification(c *gin.Context) {
+	var query models.ClassificationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+
+	report, err := h.svc.AnalyzeClassification(query.Days)
+	if err != nil {
+		Error(c, 500, err.Error())
+		return
+	}
+	Success(c, report)
+}
+
+// ApplyClassification 立即重算 ABC/XYZ 分类并写入商品
+func (h *Handler) ApplyClassification(c *gin.Context) {
+	var req models.ClassificationQuery
+	_ = c.ShouldBindJSON(&req) // 请求体可选
+
+	report, err := h.svc.ApplyClassification(req.Days)
+	if err != nil {
+		Error(c, 500, err.Error())
+		return
+	}
+	Success(c, report)
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"go-cargo/internal/models"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -16,9 +18,19 @@ func (h *Handler) GetDashboardStats(c *gin.Context) {
 
 // GetChartData 获取图表数据
 func (h *Handler) GetChartData(c *gin.Context) {
-	data, err := h.svc.GetChartData()
+	var query models.ChartQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+	if err := h.svc.ValidateChartQuery(&query); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+
+	data, err := h.svc.GetChartData(&query)
 	if err != nil {
-		Error(c, 500, "获取图表数据失败")
+		Error(c, 500, err.Error())
 		return
 	}
 	Success(c, data)
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/url"
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sendPDF 以内联方式返回 PDF
+func sendPDF(c *gin.Context, data []byte, filename string) {
+	c.Header("Content-Disposition", "inline; filename*=UTF-8''"+url.PathEscape(filename))
+	c.Data(200, "application/pdf", data)
+}
+
+// GetRecordSlipPDF 打印单条库存记录的出入库单
+func (h *Handler) GetRecordSlipPDF(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的记录ID")
+		return
+	}
+
+	data, filename, err := h.svc.RecordSlipPDF(uint(id))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	sendPDF(c, data, filename)
+}
+
+// GetReferenceSlipPDF 打印同一关联单号下的全部库存记录
+func (h *Handler) GetReferenceSlipPDF(c *gin.Context) {
+	data, filename, err := h.svc.ReferenceSlipPDF(c.Param("reference_no"))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	sendPDF(c, data, filename)
+}
+
+// GetStocktakeSheetPDF 打印盘点表 (blind=true 时不打印账面数量)
+func (h *Handler) GetStocktakeSheetPDF(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的盘点单ID")
+		return
+	}
+
+	data, filename, err := h.svc.StocktakeSheetPDF(uint(id), c.Query("blind") == "true")
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	sendPDF(c, data, filename)
+}
+
+// GetPickListPDF 打印拣货单
+func (h *Handler) GetPickListPDF(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的拣货单ID")
+		return
+	}
+
+	data, filename, err := h.svc.PickListPDF(uint(id))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	sendPDF(c, data, filename)
+}
+
+// GetProductListPDF 打印商品清单, 筛选参数与商品列表相同
+func (h *Handler) GetProductListPDF(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+
+	data, filename, err := h.svc.ProductListPDF(&query, productFilterFromQuery(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	sendPDF(c, data, filename)
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProductForecast 获取商品需求预测
+func (h *Handler) GetProductForecast(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	var query models.ForecastQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+
+	forecast, err := h.svc.GetProductForecast(uint(id), &query)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, forecast)
+}
+
+// GetStockoutProjections 获取全部商品的预计断货日期
+func (h *Handler) GetStockoutProjections(c *gin.Context) {
+	var query models.ForecastQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+
+	forecasts, err := h.svc.GetStockoutProjections(&query)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, forecasts)
+}
@@ -39,6 +39,15 @@ func Created(c *gin.Context, data interface{}) {
 	})
 }
 
+// Accepted 已受理响应 (如待审批)
+func Accepted(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusAccepted, models.Response{
+		Code:    202,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // Error 错误响应
 func Error(c *gin.Context, code int, message string) {
 	c.JSON(code, models.Response{
@@ -47,6 +56,15 @@ func Error(c *gin.Context, code int, message string) {
 	})
 }
 
+// ErrorWithData 错误响应 (附带数据, 如批量操作的逐行结果)
+func ErrorWithData(c *gin.Context, code int, message string, data interface{}) {
+	c.JSON(code, models.Response{
+		Code:    code,
+		Message: message,
+		Data:    data,
+	})
+}
+
 // BadRequest 400 请求错误
 func BadRequest(c *gin.Context, message string) {
 	Error(c, http.StatusBadRequest, message)
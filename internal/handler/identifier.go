@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListProductIdentifiers 获取商品的附加标识
+func (h *Handler) ListProductIdentifiers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	identifiers, err := h.svc.ListProductIdentifiers(uint(id))
+	if err != nil {
+		Error(c, 404, err.Error())
+		return
+	}
+	Success(c, identifiers)
+}
+
+// CreateProductIdentifier 为商品添加标识
+func (h *Handler) CreateProductIdentifier(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	var req models.ProductIdentifierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	identifier, err := h.svc.CreateProductIdentifier(uint(id), &req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Created(c, identifier)
+}
+
+// UpdateProductIdentifier 更新商品标识
+func (h *Handler) UpdateProductIdentifier(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+	identifierID, err := strconv.ParseUint(c.Param("identifier_id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的标识ID")
+		return
+	}
+
+	var req models.ProductIdentifierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	identifier, err := h.svc.UpdateProductIdentifier(uint(id), uint(identifierID), &req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, identifier)
+}
+
+// DeleteProductIdentifier 删除商品标识
+func (h *Handler) DeleteProductIdentifier(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+	identifierID, err := strconv.ParseUint(c.Param("identifier_id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的标识ID")
+		return
+	}
+
+	if err := h.svc.DeleteProductIdentifier(uint(id), uint(identifierID)); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, nil)
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"net/http"
 	"strconv"
 
 	"go-cargo/internal/models"
@@ -19,10 +20,17 @@ func (h *Handler) StockIn(c *gin.Context) {
 	userID := GetCurrentUserID(c)
 	username := GetCurrentUsername(c)
 
-	if err := h.svc.StockIn(&req, userID, username); err != nil {
+	if _, err := h.svc.StockIn(&req, userID, username); err != nil {
 		BadRequest(c, err.Error())
 		return
 	}
+	// 未指定货位时附带上架建议, 据此移库上架
+	if req.LocationID == nil {
+		if suggestions, err := h.svc.SuggestPutaway(req.ProductID, req.Quantity, req.Unit, 0); err == nil && len(suggestions) > 0 {
+			Success(c, gin.H{"message": "入库成功", "putaway": suggestions})
+			return
+		}
+	}
 	Success(c, gin.H{"message": "入库成功"})
 }
 
@@ -37,7 +45,7 @@ func (h *Handler) StockOut(c *gin.Context) {
 	userID := GetCurrentUserID(c)
 	username := GetCurrentUsername(c)
 
-	if err := h.svc.StockOut(&req, userID, username); err != nil {
+	if _, err := h.svc.StockOut(&req, userID, username); err != nil {
 		BadRequest(c, err.Error())
 		return
 	}
@@ -55,13 +63,67 @@ func (h *Handler) StockAdjust(c *gin.Context) {
 	userID := GetCurrentUserID(c)
 	username := GetCurrentUsername(c)
 
-	if err := h.svc.StockAdjust(&req, userID, username); err != nil {
+	pending, err := h.svc.StockAdjust(&req, userID, username)
+	if err != nil {
 		BadRequest(c, err.Error())
 		return
 	}
+	if pending != nil {
+		Accepted(c, "调整量超出审批阈值，已提交审批", pending)
+		return
+	}
 	Success(c, gin.H{"message": "库存调整成功"})
 }
 
+// BatchStockIn 批量入库
+func (h *Handler) BatchStockIn(c *gin.Context) {
+	var req models.BatchStockInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.svc.BatchStockIn(&req, GetCurrentUserID(c), GetCurrentUsername(c))
+	respondBatch(c, result, err)
+}
+
+// BatchStockOut 批量出库
+func (h *Handler) BatchStockOut(c *gin.Context) {
+	var req models.BatchStockOutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.svc.BatchStockOut(&req, GetCurrentUserID(c), GetCurrentUsername(c))
+	respondBatch(c, result, err)
+}
+
+// BatchStockAdjust 批量库存调整
+func (h *Handler) BatchStockAdjust(c *gin.Context) {
+	var req models.BatchStockAdjustRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.svc.BatchStockAdjust(&req, GetCurrentUserID(c), GetCurrentUsername(c))
+	respondBatch(c, result, err)
+}
+
+// respondBatch 输出批量操作结果, 失败时同样返回逐行结果
+func respondBatch(c *gin.Context, result *models.BatchStockResult, err error) {
+	if err != nil {
+		if result != nil {
+			ErrorWithData(c, http.StatusBadRequest, err.Error(), result)
+			return
+		}
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, result)
+}
+
 // ListInventoryRecords 查询库存操作记录
 func (h *Handler) ListInventoryRecords(c *gin.Context) {
 	var query models.PaginationQuery
@@ -83,10 +145,38 @@ func (h *Handler) ListInventoryRecords(c *gin.Context) {
 	startDate := c.Query("start_date")
 	endDate := c.Query("end_date")
 
-	records, total, err := h.svc.ListInventoryRecords(&query, productID, recordType, startDate, endDate)
+	excludeVoided := c.Query("exclude_voided") == "true"
+
+	records, total, err := h.svc.ListInventoryRecords(&query, productID, recordType, startDate, endDate, excludeVoided)
 	if err != nil {
 		Error(c, 500, "获取库存记录失败")
 		return
 	}
 	Paginated(c, records, total, query.Page, query.PageSize)
 }
+
+// VoidInventoryRecord 冲销库存记录
+func (h *Handler) VoidInventoryRecord(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的记录ID")
+		return
+	}
+
+	var req models.VoidRecordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请填写冲销原因")
+		return
+	}
+
+	reversal, pending, err := h.svc.VoidInventoryRecord(uint(id), &req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	if pending != nil {
+		Accepted(c, "冲销数量超出审批阈值，已提交审批", pending)
+		return
+	}
+	Success(c, reversal)
+}
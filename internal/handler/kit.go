@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetKitBOM 获取组合商品的物料清单
+func (h *Handler) GetKitBOM(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	bom, err := h.svc.GetKitBOM(uint(id))
+	if err != nil {
+		Error(c, 404, err.Error())
+		return
+	}
+	Success(c, bom)
+}
+
+// SetKitBOM 设置组合商品的物料清单
+func (h *Handler) SetKitBOM(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	var req models.KitBOMRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	bom, err := h.svc.SetKitBOM(uint(id), &req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, bom)
+}
+
+// DeleteKitBOM 删除物料清单
+func (h *Handler) DeleteKitBOM(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	if err := h.svc.DeleteKitBOM(uint(id)); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, nil)
+}
+
+// AssembleKit 组装组合商品
+func (h *Handler) AssembleKit(c *gin.Context) {
+	h.kitAssembly(c, h.svc.AssembleKit)
+}
+
+// DisassembleKit 拆卸组合商品
+func (h *Handler) DisassembleKit(c *gin.Context) {
+	h.kitAssembly(c, h.svc.DisassembleKit)
+}
+
+// kitAssembly 组装与拆卸共用的请求解析
+func (h *Handler) kitAssembly(c *gin.Context, run func(uint, *models.KitAssemblyRequest, uint, string) (*models.KitAssembly, error)) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	var req models.KitAssemblyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	assembly, err := run(uint(id), &req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Created(c, assembly)
+}
+
+// ListKitAssemblies 获取组装单列表
+func (h *Handler) ListKitAssemblies(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+	query.GetOffset()
+
+	var kitID *uint
+	if kid := c.Query("kit_id"); kid != "" {
+		if id, err := strconv.ParseUint(kid, 10, 32); err == nil {
+			uid := uint(id)
+			kitID = &uid
+		}
+	}
+
+	assemblies, total, err := h.svc.ListKitAssemblies(&query, kitID, c.Query("type"))
+	if err != nil {
+		Error(c, 500, "获取组装单列表失败")
+		return
+	}
+	Paginated(c, assemblies, total, query.Page, query.PageSize)
+}
+
+// GetKitAssembly 获取组装单详情
+func (h *Handler) GetKitAssembly(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的组装单ID")
+		return
+	}
+
+	assembly, err := h.svc.GetKitAssembly(uint(id))
+	if err != nil {
+		Error(c, 404, "组装单不存在")
+		return
+	}
+	Success(c, assembly)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/url"
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProductBarcode 生成商品条码图片 (PNG / SVG)
+func (h *Handler) GetProductBarcode(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	var query models.BarcodeQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+
+	data, contentType, err := h.svc.ProductBarcodeImage(uint(id), &query)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	c.Data(200, contentType, data)
+}
+
+// GetLabelLayouts 获取内置标签纸版式
+func (h *Handler) GetLabelLayouts(c *gin.Context) {
+	Success(c, h.svc.LabelLayouts())
+}
+
+// GetProductLabels 打印商品标签 (A4 标签纸 PDF 或 ZPL)
+func (h *Handler) GetProductLabels(c *gin.Context) {
+	var query models.LabelQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+
+	data, filename, err := h.svc.ProductLabels(&query)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	sendLabels(c, data, filename, query.Format)
+}
+
+// GetLocationLabels 打印库位标签 (A4 标签纸 PDF 或 ZPL)
+func (h *Handler) GetLocationLabels(c *gin.Context) {
+	var query models.LabelQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+
+	data, filename, err := h.svc.LocationLabels(&query)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	sendLabels(c, data, filename, query.Format)
+}
+
+// sendLabels 返回标签文件: PDF 内联显示, ZPL 作为附件下载
+func sendLabels(c *gin.Context, data []byte, filename, format string) {
+	if format == "zpl" {
+		c.Header("Content-Disposition", "attachment; filename*=UTF-8''"+url.PathEscape(filename))
+		c.Data(200, "application/zpl; charset=utf-8", data)
+		return
+	}
+	sendPDF(c, data, filename)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProductLedger 获取商品库存台账 (期初结存、每笔变动与滚动结存)
+func (h *Handler) GetProductLedger(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	var query models.LedgerQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+
+	ledger, err := h.svc.GetProductLedger(uint(id), &query)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, ledger)
+}
+
+// GetStockAsOf 获取指定日期日终的库存报表
+func (h *Handler) GetStockAsOf(c *gin.Context) {
+	report, err := h.svc.GetStockAsOf(c.Query("date"))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, report)
+}
+
+// CheckLedgerConsistency 检查库存记录链的一致性
+func (h *Handler) CheckLedgerConsistency(c *gin.Context) {
+	result, err := h.svc.CheckLedgerConsistency()
+	if err != nil {
+		Error(c, 500, err.Error())
+		return
+	}
+	Success(c, result)
+}
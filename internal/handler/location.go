@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListLocations 获取库位列表
+func (h *Handler) ListLocations(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+	query.GetOffset()
+
+	var filter models.LocationQuery
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+
+	locations, total, err := h.svc.ListLocations(&query, &filter)
+	if err != nil {
+		Error(c, 500, "获取库位列表失败")
+		return
+	}
+	Paginated(c, locations, total, query.Page, query.PageSize)
+}
+
+// GetLocation 获取库位详情
+func (h *Handler) GetLocation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的库位ID")
+		return
+	}
+
+	location, err := h.svc.GetLocation(uint(id))
+	if err != nil {
+		Error(c, 404, "库位不存在")
+		return
+	}
+	Success(c, location)
+}
+
+// CreateLocation 创建库位
+func (h *Handler) CreateLocation(c *gin.Context) {
+	var req models.LocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	location, err := h.svc.CreateLocation(&req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Created(c, location)
+}
+
+// UpdateLocation 更新库位
+func (h *Handler) UpdateLocation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的库位ID")
+		return
+	}
+
+	var req models.LocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	location, err := h.svc.UpdateLocation(uint(id), &req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, location)
+}
+
+// DeleteLocation 删除库位
+func (h *Handler) DeleteLocation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的库位ID")
+		return
+	}
+
+	if err := h.svc.DeleteLocation(uint(id)); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, nil)
+}
+
+// GetLocationStock 获取库位及其下级货位中的库存
+func (h *Handler) GetLocationStock(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的库位ID")
+		return
+	}
+
+	stocks, err := h.svc.GetLocationStock(uint(id))
+	if err != nil {
+		Error(c, 404, err.Error())
+		return
+	}
+	Success(c, stocks)
+}
+
+// GetPutawaySuggestions 获取上架建议货位
+func (h *Handler) GetPutawaySuggestions(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Query("product_id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+	quantity, err := strconv.ParseFloat(c.Query("quantity"), 64)
+	if err != nil || quantity <= 0 {
+		BadRequest(c, "quantity 必须大于 0")
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	suggestions, err := h.svc.SuggestPutaway(uint(productID), quantity, c.Query("unit"), limit)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, suggestions)
+}
+
+// GetProductBins 获取商品的货位库存分布
+func (h *Handler) GetProductBins(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	bins, err := h.svc.GetProductBins(uint(id))
+	if err != nil {
+		Error(c, 404, err.Error())
+		return
+	}
+	Success(c, bins)
+}
+
+// MoveStock 货位间移库
+func (h *Handler) MoveStock(c *gin.Context) {
+	var req models.StockMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	record, err := h.svc.MoveStock(&req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Created(c, record)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePickList 由出库批次生成拣货单
+func (h *Handler) CreatePickList(c *gin.Context) {
+	var req models.PickListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	list, err := h.svc.CreatePickList(&req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Created(c, list)
+}
+
+// ListPickLists 获取拣货单列表
+func (h *Handler) ListPickLists(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+	query.GetOffset()
+
+	lists, total, err := h.svc.ListPickLists(&query, c.Query("status"))
+	if err != nil {
+		Error(c, 500, "获取拣货单列表失败")
+		return
+	}
+	Paginated(c, lists, total, query.Page, query.PageSize)
+}
+
+// GetPickList 获取拣货单详情
+func (h *Handler) GetPickList(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的拣货单ID")
+		return
+	}
+
+	list, err := h.svc.GetPickList(uint(id))
+	if err != nil {
+		Error(c, 404, "拣货单不存在")
+		return
+	}
+	Success(c, list)
+}
+
+// ConfirmPickList 拣货确认并出库
+func (h *Handler) ConfirmPickList(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的拣货单ID")
+		return
+	}
+
+	var req models.PickConfirmRequest
+	_ = c.ShouldBindJSON(&req) // 请求体可选
+
+	list, err := h.svc.ConfirmPickList(uint(id), &req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, list)
+}
+
+// CancelPickList 取消拣货单
+func (h *Handler) CancelPickList(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的拣货单ID")
+		return
+	}
+
+	if err := h.svc.CancelPickList(uint(id)); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, nil)
+}
@@ -17,27 +17,42 @@ func (h *Handler) ListProducts(c *gin.Context) {
 	}
 	query.GetOffset() // 初始化默认值
 
-	var categoryID, supplierID *uint
+	filter := productFilterFromQuery(c)
+	products, total, err := h.svc.ListProducts(&query, filter)
+	if err != nil {
+		Error(c, 500, "获取商品列表失败")
+		return
+	}
+
+	Paginated(c, products, total, query.Page, query.PageSize)
+}
+
+// productFilterFromQuery 从查询参数解析商品筛选条件
+func productFilterFromQuery(c *gin.Context) *models.ProductFilter {
+	filter := &models.ProductFilter{
+		ABCClass: c.Query("abc_class"),
+		XYZClass: c.Query("xyz_class"),
+		View:     c.Query("view"),
+	}
 	if cid := c.Query("category_id"); cid != "" {
 		if id, err := strconv.ParseUint(cid, 10, 32); err == nil {
 			uid := uint(id)
-			categoryID = &uid
+			filter.CategoryID = &uid
 		}
 	}
 	if sid := c.Query("supplier_id"); sid != "" {
 		if id, err := strconv.ParseUint(sid, 10, 32); err == nil {
 			uid := uint(id)
-			supplierID = &uid
+			filter.SupplierID = &uid
 		}
 	}
-
-	products, total, err := h.svc.ListProducts(&query, categoryID, supplierID)
-	if err != nil {
-		Error(c, 500, "获取商品列表失败")
-		return
+	if pid := c.Query("parent_id"); pid != "" {
+		if id, err := strconv.ParseUint(pid, 10, 32); err == nil {
+			uid := uint(id)
+			filter.ParentID = &uid
+		}
 	}
-
-	Paginated(c, products, total, query.Page, query.PageSize)
+	return filter
 }
 
 // GetProduct 获取商品详情
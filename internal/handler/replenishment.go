@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetReorderSuggestions 获取补货建议 (按供应商分组)
+func (h *Handler) GetReorderSuggestions(c *gin.Context) {
+	var query models.ReorderQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+
+	groups, err := h.svc.GetReorderSuggestions(query.Days)
+	if err != nil {
+		Error(c, 500, err.Error())
+		return
+	}
+	Success(c, groups)
+}
+
+// CreatePurchaseOrdersFromSuggestions 由补货建议生成草稿采购单
+func (h *Handler) CreatePurchaseOrdersFromSuggestions(c *gin.Context) {
+	var req models.CreatePurchaseOrdersRequest
+	_ = c.ShouldBindJSON(&req) // 请求体可选
+
+	orders, err := h.svc.CreatePurchaseOrdersFromSuggestions(&req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Created(c, orders)
+}
+
+// ListPurchaseOrders 获取采购单列表
+func (h *Handler) ListPurchaseOrders(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+	query.GetOffset()
+
+	var supplierID *uint
+	if sid := c.Query("supplier_id"); sid != "" {
+		if id, err := strconv.ParseUint(sid, 10, 32); err == nil {
+			uid := uint(id)
+			supplierID = &uid
+		}
+	}
+
+	orders, total, err := h.svc.ListPurchaseOrders(&query, supplierID, c.Query("status"))
+	if err != nil {
+		Error(c, 500, "获取采购单列表失败")
+		return
+	}
+	Paginated(c, orders, total, query.Page, query.PageSize)
+}
+
+// GetPurchaseOrder 获取采购单详情
+func (h *Handler) GetPurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的采购单ID")
+		return
+	}
+
+	order, err := h.svc.GetPurchaseOrder(uint(id))
+	if err != nil {
+		Error(c, 404, "采购单不存在")
+		return
+	}
+	Success(c, order)
+}
+
+// CancelPurchaseOrder 取消草稿采购单
+func (h *Handler) CancelPurchaseOrder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的采购单ID")
+		return
+	}
+
+	if err := h.svc.CancelPurchaseOrder(uint(id)); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, nil)
+}
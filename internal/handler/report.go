@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStockAgingReport 获取库龄与呆滞库存报表
+func (h *Handler) GetStockAgingReport(c *gin.Context) {
+	var query models.AgingQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+
+	report, err := h.svc.GetStockAgingReport(query.DeadDays)
+	if err != nil {
+		Error(c, 500, err.Error())
+		return
+	}
+	Success(c, report)
+}
+
+// GetTurnoverReport 获取周转率、库存天数与售罄率报表
+func (h *Handler) GetTurnoverReport(c *gin.Context) {
+	var query models.TurnoverQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+
+	report, err := h.svc.GetTurnoverReport(&query)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, report)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListReportJobs 获取定时报表任务列表
+func (h *Handler) ListReportJobs(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+	query.GetOffset()
+
+	jobs, total, err := h.svc.ListReportJobs(&query)
+	if err != nil {
+		Error(c, 500, "获取报表任务列表失败")
+		return
+	}
+	Paginated(c, jobs, total, query.Page, query.PageSize)
+}
+
+// GetReportJob 获取报表任务详情
+func (h *Handler) GetReportJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的任务ID")
+		return
+	}
+
+	job, err := h.svc.GetReportJob(uint(id))
+	if err != nil {
+		Error(c, 404, err.Error())
+		return
+	}
+	Success(c, job)
+}
+
+// CreateReportJob 创建报表任务
+func (h *Handler) CreateReportJob(c *gin.Context) {
+	var req models.ReportJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	job, err := h.svc.CreateReportJob(&req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Created(c, job)
+}
+
+// UpdateReportJob 更新报表任务
+func (h *Handler) UpdateReportJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的任务ID")
+		return
+	}
+
+	var req models.ReportJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	job, err := h.svc.UpdateReportJob(uint(id), &req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, job)
+}
+
+// DeleteReportJob 删除报表任务
+func (h *Handler) DeleteReportJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的任务ID")
+		return
+	}
+
+	if err := h.svc.DeleteReportJob(uint(id)); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, nil)
+}
+
+// RunReportJob 立即执行报表任务
+func (h *Handler) RunReportJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的任务ID")
+		return
+	}
+
+	run, err := h.svc.RunReportJob(uint(id))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	if run.Status != models.ReportRunSuccess {
+		ErrorWithData(c, 502, "报表执行失败: "+run.Error, run)
+		return
+	}
+	Success(c, run)
+}
+
+// ListReportJobRuns 获取报表任务执行记录
+func (h *Handler) ListReportJobRuns(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的任务ID")
+		return
+	}
+
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+	query.GetOffset()
+
+	runs, total, err := h.svc.ListReportJobRuns(uint(id), &query)
+	if err != nil {
+		Error(c, 500, "获取执行记录失败")
+		return
+	}
+	Paginated(c, runs, total, query.Page, query.PageSize)
+}
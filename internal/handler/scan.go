@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"strings"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Scan 按扫码内容精确查找商品 (条码、SKU、GS1-128)
+func (h *Handler) Scan(c *gin.Context) {
+	code := strings.TrimPrefix(c.Param("code"), "/")
+
+	result, err := h.svc.ResolveScan(code)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	if len(result.Matches) > 1 {
+		ErrorWithData(c, 409, "条码对应多个商品", result)
+		return
+	}
+	if result.Product == nil {
+		ErrorWithData(c, 404, "未找到条码对应的商品", result)
+		return
+	}
+	Success(c, result)
+}
+
+// ScanStockIn 扫码入库
+func (h *Handler) ScanStockIn(c *gin.Context) {
+	var req models.ScanStockInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.svc.ScanStockIn(&req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, result)
+}
+
+// ScanStockOut 扫码出库
+func (h *Handler) ScanStockOut(c *gin.Context) {
+	var req models.ScanStockOutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	result, err := h.svc.ScanStockOut(&req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, result)
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateStocktake 创建盘点单
+func (h *Handler) CreateStocktake(c *gin.Context) {
+	var req models.StocktakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	session, err := h.svc.CreateStocktake(&req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Created(c, session)
+}
+
+// ListStocktakes 获取盘点单列表
+func (h *Handler) ListStocktakes(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+	query.GetOffset()
+
+	sessions, total, err := h.svc.ListStocktakes(&query, c.Query("status"))
+	if err != nil {
+		Error(c, 500, "获取盘点单列表失败")
+		return
+	}
+	Paginated(c, sessions, total, query.Page, query.PageSize)
+}
+
+// GetStocktake 获取盘点单详情
+func (h *Handler) GetStocktake(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的盘点单ID")
+		return
+	}
+
+	session, err := h.svc.GetStocktake(uint(id))
+	if err != nil {
+		Error(c, 404, "盘点单不存在")
+		return
+	}
+	Success(c, session)
+}
+
+// SubmitStocktakeCounts 提交盘点计数
+func (h *Handler) SubmitStocktakeCounts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的盘点单ID")
+		return
+	}
+
+	var req models.StocktakeCountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	if err := h.svc.SubmitStocktakeCounts(uint(id), &req, GetCurrentUserID(c), GetCurrentUsername(c)); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, gin.H{"message": "计数已保存"})
+}
+
+// ListStocktakeCounts 获取盘点计数明细
+func (h *Handler) ListStocktakeCounts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的盘点单ID")
+		return
+	}
+
+	counts, err := h.svc.ListStocktakeCounts(uint(id))
+	if err != nil {
+		Error(c, 500, "获取计数明细失败")
+		return
+	}
+	Success(c, counts)
+}
+
+// GetStocktakeVariances 获取盘点差异报告
+func (h *Handler) GetStocktakeVariances(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的盘点单ID")
+		return
+	}
+
+	report, err := h.svc.GetStocktakeVariances(uint(id))
+	if err != nil {
+		Error(c, 404, err.Error())
+		return
+	}
+	Success(c, report)
+}
+
+// ApproveStocktake 审核盘点单并过账差异
+func (h *Handler) ApproveStocktake(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的盘点单ID")
+		return
+	}
+
+	var req models.StocktakeApproveRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	session, err := h.svc.ApproveStocktake(uint(id), &req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, session)
+}
+
+// CancelStocktake 取消盘点单
+func (h *Handler) CancelStocktake(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的盘点单ID")
+		return
+	}
+
+	if err := h.svc.CancelStocktake(uint(id)); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, nil)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSupplierReturn 创建退供应商单
+func (h *Handler) CreateSupplierReturn(c *gin.Context) {
+	var req models.SupplierReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	ret, err := h.svc.CreateSupplierReturn(&req, GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Created(c, ret)
+}
+
+// ListSupplierReturns 获取退供应商单列表
+func (h *Handler) ListSupplierReturns(c *gin.Context) {
+	var query models.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+	query.GetOffset()
+
+	var supplierID *uint
+	if sid := c.Query("supplier_id"); sid != "" {
+		if id, err := strconv.ParseUint(sid, 10, 32); err == nil {
+			uid := uint(id)
+			supplierID = &uid
+		}
+	}
+
+	returns, total, err := h.svc.ListSupplierReturns(&query, supplierID, c.Query("status"))
+	if err != nil {
+		Error(c, 500, "获取退供应商单列表失败")
+		return
+	}
+	Paginated(c, returns, total, query.Page, query.PageSize)
+}
+
+// GetSupplierReturn 获取退供应商单详情
+func (h *Handler) GetSupplierReturn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的退供应商单ID")
+		return
+	}
+
+	ret, err := h.svc.GetSupplierReturn(uint(id))
+	if err != nil {
+		Error(c, 404, "退供应商单不存在")
+		return
+	}
+	Success(c, ret)
+}
+
+// ShipSupplierReturn 退货出库
+func (h *Handler) ShipSupplierReturn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的退供应商单ID")
+		return
+	}
+
+	ret, err := h.svc.ShipSupplierReturn(uint(id), GetCurrentUserID(c), GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, ret)
+}
+
+// ConfirmSupplierCredit 登记供应商确认的贷项
+func (h *Handler) ConfirmSupplierCredit(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的退供应商单ID")
+		return
+	}
+
+	var req models.SupplierCreditRequest
+	_ = c.ShouldBindJSON(&req) // 请求体可选
+
+	ret, err := h.svc.ConfirmSupplierCredit(uint(id), &req, GetCurrentUsername(c))
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, ret)
+}
+
+// CancelSupplierReturn 取消退供应商单
+func (h *Handler) CancelSupplierReturn(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的退供应商单ID")
+		return
+	}
+
+	if err := h.svc.CancelSupplierReturn(uint(id)); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, nil)
+}
+
+// GetSupplierReturnRateReport 供应商退货率报表
+func (h *Handler) GetSupplierReturnRateReport(c *gin.Context) {
+	var query models.SupplierReturnRateQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		BadRequest(c, "查询参数错误")
+		return
+	}
+
+	report, err := h.svc.GetSupplierReturnRateReport(&query)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, report)
+}
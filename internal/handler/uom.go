@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListProductUnits 获取商品的基本单位与包装单位
+func (h *Handler) ListProductUnits(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	units, err := h.svc.ListProductUnits(uint(id))
+	if err != nil {
+		Error(c, 404, err.Error())
+		return
+	}
+	Success(c, units)
+}
+
+// CreateProductUnit 为商品添加包装单位
+func (h *Handler) CreateProductUnit(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	var req models.ProductUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	unit, err := h.svc.CreateProductUnit(uint(id), &req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Created(c, unit)
+}
+
+// UpdateProductUnit 更新包装单位
+func (h *Handler) UpdateProductUnit(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+	unitID, err := strconv.ParseUint(c.Param("unit_id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的单位ID")
+		return
+	}
+
+	var req models.ProductUnitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	unit, err := h.svc.UpdateProductUnit(uint(id), uint(unitID), &req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, unit)
+}
+
+// DeleteProductUnit 删除包装单位
+func (h *Handler) DeleteProductUnit(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+	unitID, err := strconv.ParseUint(c.Param("unit_id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的单位ID")
+		return
+	}
+
+	if err := h.svc.DeleteProductUnit(uint(id), uint(unitID)); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, nil)
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"strconv"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListProductAttributes 获取款式的规格属性
+func (h *Handler) ListProductAttributes(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	attrs, err := h.svc.ListProductAttributes(uint(id))
+	if err != nil {
+		Error(c, 404, err.Error())
+		return
+	}
+	Success(c, attrs)
+}
+
+// SetProductAttributes 设置款式的规格属性
+func (h *Handler) SetProductAttributes(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	var req models.ProductAttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	attrs, err := h.svc.SetProductAttributes(uint(id), &req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, attrs)
+}
+
+// ListVariants 获取款式下的规格
+func (h *Handler) ListVariants(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	variants, err := h.svc.ListVariants(uint(id))
+	if err != nil {
+		Error(c, 404, err.Error())
+		return
+	}
+	Success(c, variants)
+}
+
+// GenerateVariants 按规格属性组合生成规格
+func (h *Handler) GenerateVariants(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+
+	var req models.GenerateVariantsRequest
+	_ = c.ShouldBindJSON(&req) // 请求体可选
+
+	result, err := h.svc.GenerateVariants(uint(id), &req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Created(c, result)
+}
+
+// UpdateVariant 更新规格的独立设置
+func (h *Handler) UpdateVariant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+	variantID, err := strconv.ParseUint(c.Param("variant_id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的规格ID")
+		return
+	}
+
+	var req models.VariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequest(c, "请求参数错误: "+err.Error())
+		return
+	}
+
+	variant, err := h.svc.UpdateVariant(uint(id), uint(variantID), &req)
+	if err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, variant)
+}
+
+// DeleteVariant 删除规格
+func (h *Handler) DeleteVariant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的商品ID")
+		return
+	}
+	variantID, err := strconv.ParseUint(c.Param("variant_id"), 10, 32)
+	if err != nil {
+		BadRequest(c, "无效的规格ID")
+		return
+	}
+
+	if err := h.svc.DeleteVariant(uint(id), uint(variantID)); err != nil {
+		BadRequest(c, err.Error())
+		return
+	}
+	Success(c, nil)
+}
@@ -0,0 +1,119 @@
+// Package mailer 通过 SMTP 发送带附件的邮件
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config SMTP 配置
+type Config struct {
+	Host     string
+	Port     int
+	Username string // 为空时不认证
+	Password string
+	From     string
+}
+
+// Enabled 是否已配置 SMTP
+func (c *Config) Enabled() bool {
+	return c.Host != "" && c.From != ""
+}
+
+// Attachment 邮件附件
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Message 邮件内容
+type Message struct {
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// Send 发送邮件; 服务器支持 STARTTLS 时自动加密
+func Send(cfg *Config, msg *Message) error {
+	if !cfg.Enabled() {
+		return fmt.Errorf("未配置 SMTP 服务器")
+	}
+	if len(msg.To) == 0 {
+		return fmt.Errorf("收件人不能为空")
+	}
+	data, err := build(cfg.From, msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	return smtp.SendMail(addr, auth, cfg.From, msg.To, data)
+}
+
+// build 组装 multipart/mixed 邮件
+func build(from string, msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	header := []string{
+		"From: " + from,
+		"To: " + strings.Join(msg.To, ", "),
+		"Subject: " + mime.BEncoding.Encode("UTF-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=" + w.Boundary(),
+	}
+	buf.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+
+	body, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	writeBase64(body, []byte(msg.Body))
+
+	for _, a := range msg.Attachments {
+		name := mime.BEncoding.Encode("UTF-8", a.Name)
+		part, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {fmt.Sprintf("%s; name=%q", a.ContentType, name)},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", name)},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		writeBase64(part, a.Data)
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBase64 按每行 76 个字符写入 base64 内容
+func writeBase64(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		w.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	w.Write([]byte(encoded + "\r\n"))
+}
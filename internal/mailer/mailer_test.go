@@ -0,0 +1,175 @@
+package mailer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// received 模拟 SMTP 服务器收到的一封邮件
+type received struct {
+	from string
+	to   []string
+	data string
+}
+
+// startMockSMTP 启动仅支持基本命令的 SMTP 服务器 (不支持 STARTTLS 与认证), 返回地址与收件通道
+func startMockSMTP(t *testing.T) (string, int, <-chan received) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	ch := make(chan received, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+
+		var msg received
+		reply("220 mock ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.TrimRight(line, "\r\n")
+			upper := strings.ToUpper(cmd)
+			switch {
+			case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+				reply("250-mock")
+				reply("250 8BITMIME")
+			case strings.HasPrefix(upper, "MAIL FROM:"):
+				msg.from = mailAddr(cmd[len("MAIL FROM:"):])
+				reply("250 OK")
+			case strings.HasPrefix(upper, "RCPT TO:"):
+				msg.to = append(msg.to, mailAddr(cmd[len("RCPT TO:"):]))
+				reply("250 OK")
+			case upper == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(strings.TrimPrefix(l, "."))
+				}
+				msg.data = data.String()
+				reply("250 OK")
+			case upper == "QUIT":
+				reply("221 Bye")
+				ch <- msg
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, ch
+}
+
+// mailAddr 取出 "<addr> PARAM=..." 中尖括号内的地址
+func mailAddr(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, ">"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimPrefix(s, "<")
+}
+
+func TestSendWithAttachment(t *testing.T) {
+	host, port, ch := startMockSMTP(t)
+	cfg := &Config{Host: host, Port: port, From: "report@example.com"}
+	attachment := []byte(strings.Repeat("SKU,名称,库存\r\n", 20))
+
+	err := Send(cfg, &Message{
+		To:      []string{"a@example.com", "b@example.com"},
+		Subject: "低库存清单",
+		Body:    "请查收附件",
+		Attachments: []Attachment{
+			{Name: "低库存.csv", ContentType: "text/csv", Data: attachment},
+		},
+	})
+	if err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	got := <-ch
+	if got.from != "report@example.com" {
+		t.Errorf("MAIL FROM = %q", got.from)
+	}
+	if strings.Join(got.to, ",") != "a@example.com,b@example.com" {
+		t.Errorf("RCPT TO = %v", got.to)
+	}
+
+	m, err := mail.ReadMessage(strings.NewReader(got.data))
+	if err != nil {
+		t.Fatalf("解析邮件失败: %v", err)
+	}
+	dec := new(mime.WordDecoder)
+	if subject, _ := dec.DecodeHeader(m.Header.Get("Subject")); subject != "低库存清单" {
+		t.Errorf("Subject = %q", subject)
+	}
+	_, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("解析 Content-Type 失败: %v", err)
+	}
+
+	mr := multipart.NewReader(m.Body, params["boundary"])
+	var parts [][]byte
+	var names []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("读取邮件分段失败: %v", err)
+		}
+		raw, _ := io.ReadAll(p)
+		data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(raw), "\r\n", ""))
+		if err != nil {
+			t.Fatalf("base64 解码失败: %v", err)
+		}
+		parts = append(parts, data)
+		name, _ := dec.DecodeHeader(p.FileName())
+		names = append(names, name)
+	}
+	if len(parts) != 2 {
+		t.Fatalf("邮件分段数 = %d, 期望 2", len(parts))
+	}
+	if string(parts[0]) != "请查收附件" {
+		t.Errorf("正文 = %q", parts[0])
+	}
+	if names[1] != "低库存.csv" || !bytes.Equal(parts[1], attachment) {
+		t.Errorf("附件 %q 内容不一致", names[1])
+	}
+}
+
+func TestSendRequiresConfig(t *testing.T) {
+	if err := Send(&Config{}, &Message{To: []string{"a@example.com"}}); err == nil {
+		t.Error("未配置 SMTP 时应返回错误")
+	}
+	if err := Send(&Config{Host: "127.0.0.1", From: "x@example.com"}, &Message{}); err == nil {
+		t.Error("收件人为空时应返回错误")
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"go-cargo/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyHeader 幂等键请求头
+const IdempotencyHeader = "Idempotency-Key"
+
+// IdempotencyStore 幂等键存储
+type IdempotencyStore interface {
+	ReserveIdempotencyKey(userID uint, key, method, path, requestHash string) (*models.IdempotencyKey, bool, error)
+	CompleteIdempotencyKey(rec *models.IdempotencyKey, statusCode int, contentType string, body []byte) error
+	ReleaseIdempotencyKey(rec *models.IdempotencyKey) error
+}
+
+// bodyRecorder 在写出响应的同时缓存响应体
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency 幂等键中间件 (需在 JWTAuth 之后使用)
+// 携带 Idempotency-Key 的 POST 请求: 首次正常处理并记录响应;
+// 相同键相同请求重放原响应; 相同键不同请求返回 422; 首次请求未完成时返回 409
+func Idempotency(store IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > 255 {
+			abortJSON(c, http.StatusBadRequest, "幂等键长度不能超过 255")
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortJSON(c, http.StatusBadRequest, "读取请求体失败")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		path := c.Request.URL.Path
+		sum := sha256.Sum256(append([]byte(c.Request.Method+" "+path+"\n"), body...))
+		hash := hex.EncodeToString(sum[:])
+
+		var userID uint
+		if id, exists := c.Get("user_id"); exists {
+			userID = id.(uint)
+		}
+
+		rec, created, err := store.ReserveIdempotencyKey(userID, key, c.Request.Method, path, hash)
+		if err != nil {
+			abortJSON(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if !created {
+			switch {
+			case rec.RequestHash != hash:
+				abortJSON(c, http.StatusUnprocessableEntity, "幂等键已用于其他请求内容")
+			case rec.Status != models.IdempotencyCompleted:
+				abortJSON(c, http.StatusConflict, "相同幂等键的请求正在处理中")
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(rec.StatusCode, rec.ContentType, rec.ResponseBody)
+				c.Abort()
+			}
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			_ = store.ReleaseIdempotencyKey(rec)
+			return
+		}
+		_ = store.CompleteIdempotencyKey(rec, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+	}
+}
+
+// abortJSON 输出统一格式的错误响应并终止请求
+func abortJSON(c *gin.Context, code int, message string) {
+	c.JSON(code, models.Response{
+		Code:    code,
+		Message: message,
+	})
+	c.Abort()
+}
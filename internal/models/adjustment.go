@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// ---------- 调整原因与审批模型 ----------
+
+// ReasonCode 库存调整原因代码
+type ReasonCode struct {
+	BaseModel
+	Code        string `json:"code" gorm:"uniqueIndex;size:50;not null"`
+	Name        string `json:"name" gorm:"size:100;not null"`
+	Description string `json:"description" gorm:"size:500"`
+	SortOrder   int    `json:"sort_order" gorm:"default:0"`
+	Status      int    `json:"status" gorm:"default:1"` // 1=启用, 0=禁用
+}
+
+// TableName 指定表名
+func (ReasonCode) TableName() string { return "reason_codes" }
+
+// 内置原因代码
+const (
+	ReasonCountCorrection = "count_correction" // 盘点更正
+)
+
+// 调整单状态
+const (
+	AdjustmentPending  = "pending"  // 待审批
+	AdjustmentApproved = "approved" // 已批准并过账
+	AdjustmentRejected = "rejected" // 已驳回
+)
+
+// StockAdjustment 待审批的库存调整 (超出阈值的调整不立即改动库存)
+type StockAdjustment struct {
+	BaseModel
+	ProductID       uint       `json:"product_id" gorm:"index;not null"`
+	ReasonCode      string     `json:"reason_code" gorm:"size:50;index"`
+	BeforeQty       float64    `json:"before_qty" gorm:"type:decimal(14,3)"`   // 申请时库存
+	NewQuantity     float64    `json:"new_quantity" gorm:"type:decimal(14,3)"` // 申请调整到的数量
+	DiffQty         float64    `json:"diff_qty" gorm:"type:decimal(14,3)"`     // 申请时的差异 (审批时叠加到当前库存)
+	DiffValue       float64    `json:"diff_value" gorm:"type:decimal(12,2);default:0"`
+	Notes           string     `json:"notes" gorm:"size:500"`
+	Status          string     `json:"status" gorm:"size:20;not null;index"`
+	RequestedBy     uint       `json:"requested_by"`
+	RequestedByName string     `json:"requested_by_name" gorm:"size:50"`
+	ReviewedBy      uint       `json:"reviewed_by"`
+	ReviewedByName  string     `json:"reviewed_by_name" gorm:"size:50"`
+	ReviewedAt      *time.Time `json:"reviewed_at"`
+	ReviewNotes     string     `json:"review_notes" gorm:"size:500"`
+	RecordID        *uint      `json:"record_id"`      // 批准后生成的调整记录 (冲销申请为冲销记录)
+	VoidRecordID    *uint      `json:"void_record_id"` // 冲销申请: 批准后冲销该库存记录
+
+	// 关联
+	Product *Product `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+}
+
+// TableName 指定表名
+func (StockAdjustment) TableName() string { return "stock_adjustments" }
+
+// SourceAdjustment 库存记录来源: 调整审批单
+const SourceAdjustment = "adjustment"
+
+// ReasonCodeRequest 原因代码请求
+type ReasonCodeRequest struct {
+	Code        string `json:"code" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	SortOrder   int    `json:"sort_order"`
+	Status      int    `json:"status"`
+}
+
+// AdjustmentReviewRequest 审批调整请求
+type AdjustmentReviewRequest struct {
+	Notes string `json:"notes"`
+}
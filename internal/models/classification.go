@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// ---------- ABC/XYZ 分类 ----------
+
+// ClassificationQuery 分类分析参数
+type ClassificationQuery struct {
+	Days int `form:"days" json:"days"` // 统计天数, 默认取配置
+}
+
+// ProductConsumption 商品出库消耗汇总
+type ProductConsumption struct {
+	ProductID uint    `json:"product_id"`
+	Quantity  float64 `json:"quantity"`
+	Value     float64 `json:"value"` // 数量 × 成本
+}
+
+// ProductClassification 单个商品的分类结果
+type ProductClassification struct {
+	ProductID     uint     `json:"product_id"`
+	SKU           string   `json:"sku"`
+	Name          string   `json:"name"`
+	Quantity      float64  `json:"quantity"`
+	Value         float64  `json:"value"`
+	ValueShare    float64  `json:"value_share"`    // 金额占比 (%)
+	CumulativePct float64  `json:"cumulative_pct"` // 累计金额占比 (%)
+	CV            *float64 `json:"cv"`             // 周需求变异系数, 无需求时为空
+	ABCClass      string   `json:"abc_class"`
+	XYZClass      string   `json:"xyz_class"`
+}
+
+// ClassificationReport ABC/XYZ 分类报告
+type ClassificationReport struct {
+	From       string                  `json:"from"`
+	To         string                  `json:"to"`
+	TotalValue float64                 `json:"total_value"`
+	Matrix     map[string]int          `json:"matrix"` // 如 "AX": 3
+	Applied    bool                    `json:"applied"`
+	AppliedAt  *time.Time              `json:"applied_at,omitempty"`
+	Items      []ProductClassification `json:"items"`
+}
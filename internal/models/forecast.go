@@ -0,0 +1,54 @@
+package models
+
+// ---------- 需求预测 ----------
+
+// 预测模型
+const (
+	ForecastAuto          = "auto"           // 按历史拟合误差自动选择
+	ForecastMovingAverage = "moving_average" // 移动平均
+	ForecastExponential   = "exponential"    // 一次指数平滑
+	ForecastHoltWinters   = "holt_winters"   // Holt-Winters 加法季节模型
+)
+
+// ForecastQuery 预测查询参数
+type ForecastQuery struct {
+	Model       string  `form:"model"`       // 预测模型, 默认 auto
+	Granularity string  `form:"granularity"` // day / week, 默认 day
+	History     int     `form:"history"`     // 历史天数, 默认 180, 最多 3650
+	Horizon     int     `form:"horizon"`     // 预测期数, 默认 日 30 / 周 12, 最多 365
+	Window      int     `form:"window"`      // 移动平均窗口, 默认 7 (周粒度为 4)
+	Season      int     `form:"season"`      // 季节周期, 默认 日 7 / 周 52
+	Alpha       float64 `form:"alpha"`       // 水平平滑系数
+	Beta        float64 `form:"beta"`        // 趋势平滑系数
+	Gamma       float64 `form:"gamma"`       // 季节平滑系数
+	Confidence  float64 `form:"confidence"`  // 置信水平: 0.8 / 0.9 / 0.95 / 0.99, 默认 0.95
+}
+
+// PeriodQuantity 单期出库数量
+type PeriodQuantity struct {
+	Period   string  `json:"period"` // 期初日期
+	Quantity float64 `json:"quantity"`
+}
+
+// ForecastPoint 单期预测值及置信区间
+type ForecastPoint struct {
+	Period   string  `json:"period"`
+	Forecast float64 `json:"forecast"`
+	Lower    float64 `json:"lower"`
+	Upper    float64 `json:"upper"`
+}
+
+// ProductForecast 商品需求预测
+type ProductForecast struct {
+	ProductID         uint             `json:"product_id"`
+	SKU               string           `json:"sku"`
+	Name              string           `json:"name"`
+	CurrentStock      float64          `json:"current_stock"`
+	Model             string           `json:"model"`
+	Granularity       string           `json:"granularity"`
+	MAE               float64          `json:"mae"` // 历史一步预测平均绝对误差
+	History           []PeriodQuantity `json:"history,omitempty"`
+	Points            []ForecastPoint  `json:"points"`
+	StockoutDate      *string          `json:"stockout_date"` // 预计断货日期, 预测期内不会断货时为空
+	DaysUntilStockout *float64         `json:"days_until_stockout"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ---------- 幂等键模型 ----------
+
+// 幂等键状态
+const (
+	IdempotencyProcessing = "processing" // 首个请求处理中
+	IdempotencyCompleted  = "completed"  // 已记录响应, 可重放
+)
+
+// IdempotencyKey 幂等键, 保存请求摘要与首次响应, 用于重试时重放
+type IdempotencyKey struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	UserID       uint      `json:"user_id" gorm:"uniqueIndex:idx_idem_user_key;not null"`
+	Key          string    `json:"key" gorm:"uniqueIndex:idx_idem_user_key;size:255;not null"`
+	Method       string    `json:"method" gorm:"size:10"`
+	Path         string    `json:"path" gorm:"size:255"`
+	RequestHash  string    `json:"request_hash" gorm:"size:64;not null"` // SHA-256(方法 + 路径 + 请求体)
+	Status       string    `json:"status" gorm:"size:20;not null"`
+	StatusCode   int       `json:"status_code"`
+	ContentType  string    `json:"content_type" gorm:"size:100"`
+	ResponseBody []byte    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at" gorm:"index"`
+}
+
+// TableName 指定表名
+func (IdempotencyKey) TableName() string { return "idempotency_keys" }
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// ---------- 商品标识 ----------
+
+// 商品标识类型
+const (
+	IdentifierBarcode          = "barcode"           // 备用条码 (如不同供应商的 EAN), 全局唯一
+	IdentifierSupplierPart     = "supplier_part"     // 供应商料号, 同一供应商内唯一
+	IdentifierManufacturerPart = "manufacturer_part" // 制造商型号, 同一商品内不重复
+)
+
+// ProductIdentifier 商品的附加标识 (备用条码、供应商料号等)
+type ProductIdentifier struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ProductID  uint      `json:"product_id" gorm:"index;not null"`
+	Type       string    `json:"type" gorm:"size:30;not null"`
+	Value      string    `json:"value" gorm:"size:100;not null;index"`
+	SupplierID *uint     `json:"supplier_id" gorm:"index"`
+	Notes      string    `json:"notes" gorm:"size:500"`
+	UniqueKey  string    `json:"-" gorm:"uniqueIndex;size:200;not null"` // 按类型的唯一性规则生成
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// 关联
+	Product  *Product  `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+	Supplier *Supplier `json:"supplier,omitempty" gorm:"foreignKey:SupplierID"`
+}
+
+// TableName 指定表名
+func (ProductIdentifier) TableName() string { return "product_identifiers" }
+
+// ProductIdentifierRequest 商品标识请求
+type ProductIdentifierRequest struct {
+	Type       string `json:"type" binding:"required,oneof=barcode supplier_part manufacturer_part"`
+	Value      string `json:"value" binding:"required,max=100"`
+	SupplierID *uint  `json:"supplier_id"` // 供应商料号必填
+	Notes      string `json:"notes"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// ---------- 组合商品 ----------
+
+// 组合商品类型
+const (
+	KitTypeAssembled = "assembled" // 实物组合: 在仓库组装后持有库存
+	KitTypeVirtual   = "virtual"   // 虚拟组合: 不持有库存, 可用量由组件库存推算, 出库时扣减组件
+)
+
+// 组装单类型
+const (
+	KitAssemble    = "assemble"    // 组装: 扣减组件, 增加组合商品
+	KitDisassemble = "disassemble" // 拆卸: 扣减组合商品, 增加组件
+)
+
+// 库存记录来源
+const (
+	SourceKitAssembly = "kit_assembly" // 组装单
+	SourceKit         = "kit"          // 虚拟组合商品出库, SourceID 为组合商品ID
+)
+
+// KitComponent 组合商品的物料清单 (BOM) 行, Quantity 为每件组合商品所需的组件数量
+type KitComponent struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	KitID       uint      `json:"kit_id" gorm:"uniqueIndex:idx_kit_component;not null"`
+	ComponentID uint      `json:"component_id" gorm:"uniqueIndex:idx_kit_component;index;not null"`
+	Quantity    float64   `json:"quantity" gorm:"type:decimal(14,3);not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	Component *Product `json:"component,omitempty" gorm:"foreignKey:ComponentID"`
+}
+
+// TableName 指定表名
+func (KitComponent) TableName() string { return "kit_components" }
+
+// KitAssembly 组装/拆卸单, 组件与组合商品的库存记录通过 SourceType/SourceID 关联到本单
+type KitAssembly struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	AssemblyNo   string    `json:"assembly_no" gorm:"uniqueIndex;size:50;not null"`
+	KitID        uint      `json:"kit_id" gorm:"index;not null"`
+	Type         string    `json:"type" gorm:"size:20;not null;index"`
+	Quantity     float64   `json:"quantity" gorm:"type:decimal(14,3);not null"`
+	Notes        string    `json:"notes" gorm:"size:500"`
+	OperatorID   uint      `json:"operator_id"`
+	OperatorName string    `json:"operator_name" gorm:"size:50"`
+	CreatedAt    time.Time `json:"created_at" gorm:"index"`
+
+	Kit     *Product          `json:"kit,omitempty" gorm:"foreignKey:KitID"`
+	Records []InventoryRecord `json:"records,omitempty" gorm:"-"`
+}
+
+// TableName 指定表名
+func (KitAssembly) TableName() string { return "kit_assemblies" }
+
+// KitComponentRequest BOM 行请求
+type KitComponentRequest struct {
+	ComponentID uint    `json:"component_id" binding:"required"`
+	Quantity    float64 `json:"quantity" binding:"required,gt=0"`
+}
+
+// KitBOMRequest 设置组合商品的物料清单 (整体替换)
+type KitBOMRequest struct {
+	KitType    string                `json:"kit_type" binding:"required,oneof=assembled virtual"`
+	Components []KitComponentRequest `json:"components" binding:"required,min=1,dive"`
+}
+
+// KitBOM 组合商品的物料清单及按组件库存可组装的数量
+type KitBOM struct {
+	KitID      uint           `json:"kit_id"`
+	KitType    string         `json:"kit_type"`
+	Components []KitComponent `json:"components"`
+	Buildable  float64        `json:"buildable"` // 按当前组件库存最多可组装 (或虚拟组合可出库) 的数量
+}
+
+// KitAssemblyRequest 组装/拆卸请求
+type KitAssemblyRequest struct {
+	Quantity float64 `json:"quantity" binding:"required,gt=0"`
+	Notes    string  `json:"notes"`
+}
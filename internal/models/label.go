@@ -0,0 +1,35 @@
+package models
+
+// ---------- 条码与标签 ----------
+
+// BarcodeQuery 条码图片参数
+type BarcodeQuery struct {
+	Symbology string `form:"symbology"` // code128 / ean13 / qr, 默认 code128
+	Format    string `form:"format"`    // png / svg, 默认 png
+	Source    string `form:"source"`    // barcode / sku, 默认商品有条码时取条码, 否则取 SKU
+	Scale     int    `form:"scale"`     // 每个模块的像素数, 默认一维码 2、二维码 4
+	Height    int    `form:"height"`    // 一维条码高度 (像素), 默认 80
+}
+
+// LabelQuery 标签打印参数
+type LabelQuery struct {
+	IDs       string `form:"ids"`       // 商品ID, 逗号分隔
+	Copies    int    `form:"copies"`    // 每个商品的份数, 默认 1
+	Codes     string `form:"codes"`     // 库位编码, 逗号分隔
+	Prefix    string `form:"prefix"`    // 按前缀取商品已使用的库位
+	Symbology string `form:"symbology"` // code128 / ean13 / qr, 默认 code128
+	Source    string `form:"source"`    // 商品标签条码来源: barcode / sku
+	Format    string `form:"format"`    // pdf / zpl, 默认 pdf
+
+	// PDF 标签纸
+	Layout  string `form:"layout"`  // 内置版式名称, 默认 a4-3x8
+	Columns int    `form:"columns"` // 自定义列数 (与 rows 同时指定时生效)
+	Rows    int    `form:"rows"`    // 自定义行数
+	Skip    int    `form:"skip"`    // 首页跳过已使用的标签数
+	Border  bool   `form:"border"`  // 绘制裁切框
+
+	// ZPL 热敏标签
+	WidthMM  float64 `form:"width_mm" binding:"omitempty,min=20,max=120"`  // 20~120, 默认 60
+	HeightMM float64 `form:"height_mm" binding:"omitempty,min=10,max=200"` // 10~200, 默认 40
+	DPI      int     `form:"dpi" binding:"omitempty,min=150,max=600"`      // 150~600, 默认 203
+}
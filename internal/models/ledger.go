@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// ---------- 库存台账 ----------
+
+// LedgerQuery 台账查询参数
+type LedgerQuery struct {
+	From string `form:"from"` // 起始日期 (含), 默认 30 天前
+	To   string `form:"to"`   // 截止日期 (含), 默认今天
+}
+
+// LedgerEntry 台账明细行
+type LedgerEntry struct {
+	RecordID     uint                `json:"record_id"`
+	Type         InventoryRecordType `json:"type"`
+	Quantity     float64             `json:"quantity"`
+	Change       float64             `json:"change"` // 带符号的数量变化
+	BeforeQty    float64             `json:"before_qty"`
+	AfterQty     float64             `json:"after_qty"`
+	Balance      float64             `json:"balance"` // 按期初累加得到的结存
+	ReferenceNo  string              `json:"reference_no,omitempty"`
+	ReasonCode   string              `json:"reason_code,omitempty"`
+	OperatorName string              `json:"operator_name"`
+	Voided       bool                `json:"voided"`
+	Gap          bool                `json:"gap"` // 操作前数量与上一行结存不一致
+	CreatedAt    time.Time           `json:"created_at"`
+}
+
+// ProductLedger 商品库存台账
+type ProductLedger struct {
+	ProductID      uint          `json:"product_id"`
+	SKU            string        `json:"sku"`
+	Name           string        `json:"name"`
+	From           string        `json:"from"`
+	To             string        `json:"to"`
+	OpeningBalance float64       `json:"opening_balance"`
+	TotalIn        float64       `json:"total_in"`
+	TotalOut       float64       `json:"total_out"`
+	ClosingBalance float64       `json:"closing_balance"`
+	Entries        []LedgerEntry `json:"entries"`
+}
+
+// StockAsOfItem 指定日期的商品库存
+type StockAsOfItem struct {
+	ProductID    uint    `json:"product_id"`
+	SKU          string  `json:"sku"`
+	Name         string  `json:"name"`
+	CategoryName string  `json:"category_name"`
+	Quantity     float64 `json:"quantity"`
+	CostPrice    float64 `json:"cost_price"`
+	StockValue   float64 `json:"stock_value"`
+}
+
+// StockAsOfReport 指定日期库存报表
+type StockAsOfReport struct {
+	Date       string          `json:"date"`
+	TotalQty   float64         `json:"total_qty"`
+	TotalValue float64         `json:"total_value"`
+	Items      []StockAsOfItem `json:"items"`
+}
+
+// 台账一致性问题类型
+const (
+	LedgerIssueGap      = "gap"      // 操作前数量与上一条记录的操作后数量不一致
+	LedgerIssueDelta    = "delta"    // 前后数量差与记录类型、数量不符
+	LedgerIssueCurrent  = "current"  // 最后一条记录的操作后数量与当前库存不一致
+	LedgerIssueNoRecord = "norecord" // 无任何记录但当前库存不为零
+)
+
+// LedgerIssue 台账一致性问题
+type LedgerIssue struct {
+	ProductID uint    `json:"product_id"`
+	SKU       string  `json:"sku"`
+	Name      string  `json:"name"`
+	Kind      string  `json:"kind"`
+	RecordID  uint    `json:"record_id,omitempty"`
+	Expected  float64 `json:"expected"`
+	Actual    float64 `json:"actual"`
+	Message   string  `json:"message"`
+}
+
+// LedgerCheckResult 台账一致性检查结果
+type LedgerCheckResult struct {
+	CheckedProducts int           `json:"checked_products"`
+	CheckedRecords  int           `json:"checked_records"`
+	IssueCount      int           `json:"issue_count"`
+	Issues          []LedgerIssue `json:"issues"`
+}
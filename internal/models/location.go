@@ -0,0 +1,108 @@
+package models
+
+import "time"
+
+// ---------- 库位 ----------
+
+// 库位层级 (由上到下)
+const (
+	LocationWarehouse = "warehouse" // 仓库
+	LocationZone      = "zone"      // 库区
+	LocationAisle     = "aisle"     // 巷道
+	LocationRack      = "rack"      // 货架
+	LocationBin       = "bin"       // 货位, 唯一可存放库存的层级
+)
+
+// LocationLevels 库位层级顺序, 子库位为父库位的下一层级
+var LocationLevels = []string{LocationWarehouse, LocationZone, LocationAisle, LocationRack, LocationBin}
+
+// 库位类型
+const (
+	LocationTypePick       = "pick"       // 拣货位
+	LocationTypeBulk       = "bulk"       // 存储位
+	LocationTypeQuarantine = "quarantine" // 隔离位, 不参与自动出库与上架建议
+)
+
+// Location 库位节点; Path 为从仓库起各级编码以 "-" 连接的完整编码, 如 WH1-A-01-03-02
+type Location struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ParentID  *uint     `json:"parent_id" gorm:"index"`
+	Level     string    `json:"level" gorm:"size:20;not null;index"`
+	Code      string    `json:"code" gorm:"size:20;not null"`
+	Path      string    `json:"path" gorm:"uniqueIndex;size:100;not null"`
+	Name      string    `json:"name" gorm:"size:100"`
+	Type      string    `json:"type" gorm:"size:20;not null;default:pick;index"`
+	Capacity  float64   `json:"capacity" gorm:"type:decimal(14,3);default:0"` // 货位容量 (基本单位数量合计), 0 表示不限
+	Status    int       `json:"status" gorm:"default:1"`                      // 1=启用, 0=停用
+	SortOrder int       `json:"sort_order" gorm:"default:0"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// 货位已用量 (不存储在数据库)
+	Used *float64 `json:"used,omitempty" gorm:"-"`
+}
+
+// TableName 指定表名
+func (Location) TableName() string { return "locations" }
+
+// BinStock 货位库存; 商品总库存减去各货位库存之和为未分配库位的库存
+type BinStock struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	LocationID uint      `json:"location_id" gorm:"uniqueIndex:idx_bin_product;not null"`
+	ProductID  uint      `json:"product_id" gorm:"uniqueIndex:idx_bin_product;index;not null"`
+	Quantity   float64   `json:"quantity" gorm:"type:decimal(14,3);not null"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	Location *Location `json:"location,omitempty" gorm:"foreignKey:LocationID"`
+	Product  *Product  `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+}
+
+// TableName 指定表名
+func (BinStock) TableName() string { return "bin_stocks" }
+
+// LocationRequest 创建/更新库位请求; 层级由父库位决定, 无父库位时为仓库
+type LocationRequest struct {
+	ParentID  *uint   `json:"parent_id"`
+	Code      string  `json:"code" binding:"required,max=20"`
+	Name      string  `json:"name"`
+	Type      string  `json:"type" binding:"omitempty,oneof=pick bulk quarantine"`
+	Capacity  float64 `json:"capacity" binding:"min=0"`
+	Status    int     `json:"status"`
+	SortOrder int     `json:"sort_order"`
+}
+
+// LocationQuery 库位列表筛选
+type LocationQuery struct {
+	ParentID *uint  `form:"parent_id"`
+	Level    string `form:"level"`
+	Type     string `form:"type"`
+	Prefix   string `form:"prefix"` // 完整编码前缀
+}
+
+// StockMoveRequest 移库请求; 来源或目标为空时表示未分配库位的库存
+type StockMoveRequest struct {
+	ProductID      uint    `json:"product_id" binding:"required"`
+	FromLocationID *uint   `json:"from_location_id"`
+	ToLocationID   *uint   `json:"to_location_id"`
+	Quantity       float64 `json:"quantity" binding:"required,gt=0"`
+	ReferenceNo    string  `json:"reference_no"`
+	Notes          string  `json:"notes"`
+}
+
+// ProductBinStock 商品的货位库存分布
+type ProductBinStock struct {
+	ProductID    uint       `json:"product_id"`
+	CurrentStock float64    `json:"current_stock"`
+	Unallocated  float64    `json:"unallocated"` // 未分配库位的库存
+	Bins         []BinStock `json:"bins"`
+}
+
+// PutawaySuggestion 上架建议货位
+type PutawaySuggestion struct {
+	LocationID uint     `json:"location_id"`
+	Path       string   `json:"path"`
+	Type       string   `json:"type"`
+	Current    float64  `json:"current"`             // 货位中该商品的现有数量
+	Available  *float64 `json:"available,omitempty"` // 剩余容量, 不限容量时为空
+	Reason     string   `json:"reason"`
+}
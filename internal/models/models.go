@@ -65,6 +65,7 @@ type Supplier struct {
 	Address       string `json:"address" gorm:"size:500"`
 	Status        int    `json:"status" gorm:"default:1"` // 1=启用, 0=禁用
 	Remark        string `json:"remark" gorm:"size:500"`
+	LeadTimeDays  int    `json:"lead_time_days" gorm:"default:0"` // 采购提前期 (天), 0 表示使用系统默认值
 
 	// 关联统计
 	ProductCount int64 `json:"product_count" gorm:"-"`
@@ -83,20 +84,44 @@ type Product struct {
 	Description  string  `json:"description" gorm:"size:1000"`
 	CategoryID   *uint   `json:"category_id" gorm:"index"`
 	SupplierID   *uint   `json:"supplier_id" gorm:"index"`
-	Unit         string  `json:"unit" gorm:"size:20;default:个"` // 计量单位
+	Unit         string  `json:"unit" gorm:"size:20;default:个"`  // 计量单位
+	QtyPrecision int     `json:"qty_precision" gorm:"default:0"` // 数量小数位数 (0-3), 0 表示只允许整数
 	CostPrice    float64 `json:"cost_price" gorm:"type:decimal(12,2);default:0"`
 	SellingPrice float64 `json:"selling_price" gorm:"type:decimal(12,2);default:0"`
-	CurrentStock int     `json:"current_stock" gorm:"default:0"`
-	MinStock     int     `json:"min_stock" gorm:"default:0"` // 最低库存预警
-	MaxStock     int     `json:"max_stock" gorm:"default:0"` // 最高库存上限
+	CurrentStock float64 `json:"current_stock" gorm:"type:decimal(14,3);default:0"`
+	MinStock     float64 `json:"min_stock" gorm:"type:decimal(14,3);default:0"`    // 最低库存预警
+	MaxStock     float64 `json:"max_stock" gorm:"type:decimal(14,3);default:0"`    // 最高库存上限
+	SafetyStock  float64 `json:"safety_stock" gorm:"type:decimal(14,3);default:0"` // 安全库存, 0 表示按需求波动自动计算
 	Barcode      string  `json:"barcode" gorm:"size:100;index"`
 	Location     string  `json:"location" gorm:"size:100"` // 库位
 	ImageURL     string  `json:"image_url" gorm:"size:500"`
 	Status       int     `json:"status" gorm:"default:1"` // 1=启用, 0=禁用
 
+	// ABC/XYZ 分类 (定时重算)
+	ABCClass     string     `json:"abc_class" gorm:"size:1;index"` // A/B/C 按消耗金额
+	XYZClass     string     `json:"xyz_class" gorm:"size:1;index"` // X/Y/Z 按需求波动
+	ClassifiedAt *time.Time `json:"classified_at"`
+
+	// 多规格: 款式 HasVariants 为 true 且不持有库存, 规格通过 ParentID 指向款式
+	ParentID      *uint  `json:"parent_id" gorm:"index"`
+	HasVariants   bool   `json:"has_variants" gorm:"default:false;index"`
+	VariantName   string `json:"variant_name,omitempty" gorm:"size:200"` // 规格名称, 如 "红色 / M"
+	PriceOverride bool   `json:"price_override" gorm:"default:false"`    // 规格是否单独定价 (否则跟随款式)
+
+	// 组合商品: assembled / virtual, 为空时为普通商品
+	KitType string `json:"kit_type,omitempty" gorm:"size:20;default:'';index"`
+
+	// 款式的规格汇总 (不存储在数据库)
+	VariantCount int64   `json:"variant_count,omitempty" gorm:"-"`
+	VariantStock float64 `json:"variant_stock,omitempty" gorm:"-"`
+	// 虚拟组合商品按组件库存推算的可用量 (不存储在数据库)
+	KitAvailable *float64 `json:"kit_available,omitempty" gorm:"-"`
+
 	// 关联
 	Category *Category `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
 	Supplier *Supplier `json:"supplier,omitempty" gorm:"foreignKey:SupplierID"`
+
+	Attributes []ProductVariantValue `json:"attributes,omitempty" gorm:"foreignKey:ProductID"` // 规格属性取值
 }
 
 // TableName 指定表名
@@ -111,23 +136,42 @@ const (
 	StockIn     InventoryRecordType = "stock_in"  // 入库
 	StockOut    InventoryRecordType = "stock_out" // 出库
 	StockAdjust InventoryRecordType = "adjust"    // 调整
+	StockVoid   InventoryRecordType = "reversal"  // 冲销 (作废原记录的反向记录)
+
+	StockAssemble    InventoryRecordType = "assembly"      // 组装 (组件减少, 组合商品增加)
+	StockDisassemble InventoryRecordType = "disassembly"   // 拆卸 (组合商品减少, 组件增加)
+	StockMove        InventoryRecordType = "move"          // 库位间移库 (商品总库存不变)
+	StockReturn      InventoryRecordType = "vendor_return" // 退供应商 (库存减少)
 )
 
 // InventoryRecord 库存操作记录
 type InventoryRecord struct {
-	ID           uint                `json:"id" gorm:"primaryKey"`
-	ProductID    uint                `json:"product_id" gorm:"index;not null"`
-	Type         InventoryRecordType `json:"type" gorm:"size:20;not null;index"`
-	Quantity     int                 `json:"quantity" gorm:"not null"` // 操作数量 (正数)
-	BeforeQty    int                 `json:"before_qty"`               // 操作前数量
-	AfterQty     int                 `json:"after_qty"`                // 操作后数量
-	UnitCost     float64             `json:"unit_cost" gorm:"type:decimal(12,2);default:0"`
-	TotalCost    float64             `json:"total_cost" gorm:"type:decimal(12,2);default:0"`
-	ReferenceNo  string              `json:"reference_no" gorm:"size:100;index"` // 关联单号
-	Notes        string              `json:"notes" gorm:"size:500"`
-	OperatorID   uint                `json:"operator_id" gorm:"index"`
-	OperatorName string              `json:"operator_name" gorm:"size:50"`
-	CreatedAt    time.Time           `json:"created_at" gorm:"index"`
+	ID            uint                `json:"id" gorm:"primaryKey"`
+	ProductID     uint                `json:"product_id" gorm:"index;not null"`
+	Type          InventoryRecordType `json:"type" gorm:"size:20;not null;index"`
+	Quantity      float64             `json:"quantity" gorm:"type:decimal(14,3);not null"`   // 操作数量 (正数, 基本单位)
+	EntryQuantity float64             `json:"entry_quantity" gorm:"type:decimal(14,3)"`      // 录入数量 (按录入单位)
+	EntryUnit     string              `json:"entry_unit,omitempty" gorm:"size:20"`           // 录入单位
+	BeforeQty     float64             `json:"before_qty" gorm:"type:decimal(14,3)"`          // 操作前数量
+	AfterQty      float64             `json:"after_qty" gorm:"type:decimal(14,3)"`           // 操作后数量
+	UnitCost      float64             `json:"unit_cost" gorm:"type:decimal(14,6);default:0"` // 每基本单位成本, 按包装单位折算后保留 6 位小数
+	TotalCost     float64             `json:"total_cost" gorm:"type:decimal(12,2);default:0"`
+	ReferenceNo   string              `json:"reference_no" gorm:"size:100;index"`    // 关联单号
+	LotNo         string              `json:"lot_no,omitempty" gorm:"size:50;index"` // 批号
+	ExpiryDate    *time.Time          `json:"expiry_date,omitempty"`                 // 有效期
+	Notes         string              `json:"notes" gorm:"size:500"`
+	OperatorID    uint                `json:"operator_id" gorm:"index"`
+	OperatorName  string              `json:"operator_name" gorm:"size:50"`
+	ReasonCode    string              `json:"reason_code,omitempty" gorm:"size:50;index"`                   // 调整原因代码
+	SourceType    string              `json:"source_type,omitempty" gorm:"size:30;index:idx_record_source"` // 来源单据类型
+	SourceID      uint                `json:"source_id,omitempty" gorm:"index:idx_record_source"`           // 来源单据ID
+	Voided        bool                `json:"voided" gorm:"default:false;index"`                            // 是否已被冲销
+	VoidedAt      *time.Time          `json:"voided_at,omitempty"`
+	ReversalOfID  *uint               `json:"reversal_of_id,omitempty" gorm:"index"` // 冲销记录指向的原记录
+	ReversedByID  *uint               `json:"reversed_by_id,omitempty"`              // 原记录指向的冲销记录
+	LocationID    *uint               `json:"location_id,omitempty" gorm:"index"`    // 货位 (移库时为来源货位)
+	ToLocationID  *uint               `json:"to_location_id,omitempty"`              // 移库目标货位
+	CreatedAt     time.Time           `json:"created_at" gorm:"index"`
 
 	// 关联
 	Product *Product `json:"product,omitempty" gorm:"foreignKey:ProductID"`
@@ -174,10 +218,12 @@ type ProductRequest struct {
 	CategoryID   *uint   `json:"category_id"`
 	SupplierID   *uint   `json:"supplier_id"`
 	Unit         string  `json:"unit"`
+	QtyPrecision int     `json:"qty_precision" binding:"min=0,max=3"`
 	CostPrice    float64 `json:"cost_price"`
 	SellingPrice float64 `json:"selling_price"`
-	MinStock     int     `json:"min_stock"`
-	MaxStock     int     `json:"max_stock"`
+	MinStock     float64 `json:"min_stock"`
+	MaxStock     float64 `json:"max_stock"`
+	SafetyStock  float64 `json:"safety_stock"`
 	Barcode      string  `json:"barcode"`
 	Location     string  `json:"location"`
 	ImageURL     string  `json:"image_url"`
@@ -202,30 +248,113 @@ type SupplierRequest struct {
 	Address       string `json:"address"`
 	Status        int    `json:"status"`
 	Remark        string `json:"remark"`
+	LeadTimeDays  int    `json:"lead_time_days"`
 }
 
 // StockInRequest 入库请求
 type StockInRequest struct {
 	ProductID   uint    `json:"product_id" binding:"required"`
-	Quantity    int     `json:"quantity" binding:"required,min=1"`
-	UnitCost    float64 `json:"unit_cost"`
+	Quantity    float64 `json:"quantity" binding:"required,gt=0"`
+	Unit        string  `json:"unit"`        // 录入单位, 为空时为基本单位
+	UnitCost    float64 `json:"unit_cost"`   // 每录入单位成本
+	LocationID  *uint   `json:"location_id"` // 上架货位, 为空时计入未分配库位的库存
 	ReferenceNo string  `json:"reference_no"`
+	LotNo       string  `json:"lot_no"`
+	ExpiryDate  string  `json:"expiry_date"` // YYYY-MM-DD
 	Notes       string  `json:"notes"`
 }
 
 // StockOutRequest 出库请求
 type StockOutRequest struct {
-	ProductID   uint   `json:"product_id" binding:"required"`
-	Quantity    int    `json:"quantity" binding:"required,min=1"`
-	ReferenceNo string `json:"reference_no"`
-	Notes       string `json:"notes"`
+	ProductID   uint    `json:"product_id" binding:"required"`
+	Quantity    float64 `json:"quantity" binding:"required,gt=0"`
+	Unit        string  `json:"unit"`        // 录入单位, 为空时为基本单位
+	LocationID  *uint   `json:"location_id"` // 出库货位, 为空时依次扣减未分配、拣货位、存储位库存
+	ReferenceNo string  `json:"reference_no"`
+	LotNo       string  `json:"lot_no"`
+	Notes       string  `json:"notes"`
 }
 
 // StockAdjustRequest 库存调整请求
 type StockAdjustRequest struct {
-	ProductID   uint   `json:"product_id" binding:"required"`
-	NewQuantity int    `json:"new_quantity" binding:"required,min=0"`
-	Notes       string `json:"notes"`
+	ProductID   uint    `json:"product_id" binding:"required"`
+	NewQuantity float64 `json:"new_quantity" binding:"required,min=0"`
+	ReasonCode  string  `json:"reason_code"`
+	Notes       string  `json:"notes"`
+}
+
+// BatchStockInLine 批量入库明细行
+type BatchStockInLine struct {
+	ProductID  uint    `json:"product_id" binding:"required"`
+	Quantity   float64 `json:"quantity" binding:"required,gt=0"`
+	Unit       string  `json:"unit"`
+	UnitCost   float64 `json:"unit_cost"`
+	LocationID *uint   `json:"location_id"`
+	Notes      string  `json:"notes"`
+}
+
+// BatchStockInRequest 批量入库请求 (同一单号下的多行)
+type BatchStockInRequest struct {
+	ReferenceNo string             `json:"reference_no"`
+	Notes       string             `json:"notes"`
+	Lines       []BatchStockInLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+// BatchStockOutLine 批量出库明细行
+type BatchStockOutLine struct {
+	ProductID  uint    `json:"product_id" binding:"required"`
+	Quantity   float64 `json:"quantity" binding:"required,gt=0"`
+	Unit       string  `json:"unit"`
+	LocationID *uint   `json:"location_id"`
+	Notes      string  `json:"notes"`
+}
+
+// BatchStockOutRequest 批量出库请求
+type BatchStockOutRequest struct {
+	ReferenceNo string              `json:"reference_no"`
+	Notes       string              `json:"notes"`
+	Lines       []BatchStockOutLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+// BatchStockAdjustLine 批量调整明细行
+type BatchStockAdjustLine struct {
+	ProductID   uint    `json:"product_id" binding:"required"`
+	NewQuantity float64 `json:"new_quantity" binding:"min=0"`
+	ReasonCode  string  `json:"reason_code"`
+	Notes       string  `json:"notes"`
+}
+
+// BatchStockAdjustRequest 批量调整请求
+type BatchStockAdjustRequest struct {
+	ReferenceNo string                 `json:"reference_no"`
+	Notes       string                 `json:"notes"`
+	Lines       []BatchStockAdjustLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+// BatchLineResult 批量操作单行结果
+type BatchLineResult struct {
+	Line      int     `json:"line"` // 行号 (从 1 开始)
+	ProductID uint    `json:"product_id"`
+	Success   bool    `json:"success"`
+	Error     string  `json:"error,omitempty"`
+	BeforeQty float64 `json:"before_qty"`
+	AfterQty  float64 `json:"after_qty"`
+	RecordID  uint    `json:"record_id,omitempty"`
+
+	Pending      bool `json:"pending,omitempty"`       // 调整量超出审批阈值, 已提交审批 (未改动库存)
+	AdjustmentID uint `json:"adjustment_id,omitempty"` // 待审批调整单ID
+}
+
+// BatchStockResult 批量操作结果
+type BatchStockResult struct {
+	ReferenceNo string            `json:"reference_no"`
+	Applied     bool              `json:"applied"` // 是否已全部入账 (待审批的调整行已提交审批)
+	Lines       []BatchLineResult `json:"lines"`
+}
+
+// VoidRecordRequest 冲销库存记录请求
+type VoidRecordRequest struct {
+	Reason string `json:"reason" binding:"required"`
 }
 
 // ---------- 通用响应结构体 ----------
@@ -254,6 +383,16 @@ type PaginationQuery struct {
 	Status   *int   `form:"status"`
 }
 
+// ProductFilter 商品列表筛选条件
+type ProductFilter struct {
+	CategoryID *uint
+	SupplierID *uint
+	ABCClass   string
+	XYZClass   string
+	View       string // grouped / flat, 为空时列出全部
+	ParentID   *uint  // 仅列出指定款式的规格
+}
+
 // GetOffset 计算偏移量
 func (p *PaginationQuery) GetOffset() int {
 	if p.Page <= 0 {
@@ -275,23 +414,35 @@ type DashboardStats struct {
 	TotalSuppliers  int64   `json:"total_suppliers"`
 	TotalStockValue float64 `json:"total_stock_value"`
 	LowStockCount   int64   `json:"low_stock_count"`
-	TodayStockIn    int     `json:"today_stock_in"`
-	TodayStockOut   int     `json:"today_stock_out"`
+	TodayStockIn    float64 `json:"today_stock_in"`
+	TodayStockOut   float64 `json:"today_stock_out"`
 	TodayRecords    int64   `json:"today_records"`
 }
 
+// ChartQuery 图表查询参数
+type ChartQuery struct {
+	From        string `form:"from"`        // 起始日期 (含), 默认 29 天前
+	To          string `form:"to"`          // 截止日期 (含), 默认今天
+	Granularity string `form:"granularity"` // day / week / month, 默认 day
+	CategoryID  *uint  `form:"category_id"`
+	SupplierID  *uint  `form:"supplier_id"`
+}
+
 // ChartData 图表数据
 type ChartData struct {
-	StockMovement []DailyMovement `json:"stock_movement"`
+	From          string          `json:"from"`
+	To            string          `json:"to"`
+	Granularity   string          `json:"granularity"`
+	StockMovement []DailyMovement `json:"stock_movement"` // 日期为各期起始日 (周从周一开始, 月为 YYYY-MM)
 	TopProducts   []ProductRank   `json:"top_products"`
 	CategoryStats []CategoryStat  `json:"category_stats"`
 }
 
-// DailyMovement 每日出入库数据
+// DailyMovement 每期出入库数据
 type DailyMovement struct {
-	Date     string `json:"date"`
-	StockIn  int    `json:"stock_in"`
-	StockOut int    `json:"stock_out"`
+	Date     string  `json:"date"`
+	StockIn  float64 `json:"stock_in"`
+	StockOut float64 `json:"stock_out"`
 }
 
 // ProductRank 商品排名
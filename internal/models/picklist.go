@@ -0,0 +1,106 @@
+package models
+
+import "time"
+
+// ---------- 拣货单 ----------
+
+// 拣货单状态
+const (
+	PickListOpen      = "open"      // 待拣货
+	PickListConfirmed = "confirmed" // 已确认出库
+	PickListCancelled = "cancelled" // 已取消
+)
+
+// SourcePickList 库存记录来源: 拣货单
+const SourcePickList = "pick_list"
+
+// PickList 拣货单 (波次), 由一个或多个出库批次生成; 明细按商品与货位合并并按库位排序, 确认拣货时才写入出库记录
+type PickList struct {
+	BaseModel
+	PickNo          string     `json:"pick_no" gorm:"uniqueIndex;size:50;not null"`
+	Status          string     `json:"status" gorm:"size:20;not null;index"`
+	OrderCount      int        `json:"order_count"` // 出库批次数
+	Notes           string     `json:"notes" gorm:"size:500"`
+	CreatedBy       uint       `json:"created_by"`
+	CreatedByName   string     `json:"created_by_name" gorm:"size:50"`
+	ConfirmedBy     uint       `json:"confirmed_by"`
+	ConfirmedByName string     `json:"confirmed_by_name" gorm:"size:50"`
+	ConfirmedAt     *time.Time `json:"confirmed_at"`
+
+	// 关联
+	Lines   []PickListLine    `json:"lines,omitempty" gorm:"foreignKey:PickListID"`
+	Orders  []PickListOrder   `json:"orders,omitempty" gorm:"foreignKey:PickListID"`
+	Records []InventoryRecord `json:"records,omitempty" gorm:"-"` // 确认时写入的出库记录
+}
+
+// TableName 指定表名
+func (PickList) TableName() string { return "pick_lists" }
+
+// PickListLine 拣货明细: 同一商品在同一货位的应拣数量合计, Sequence 为拣货路线顺序
+type PickListLine struct {
+	ID           uint     `json:"id" gorm:"primaryKey"`
+	PickListID   uint     `json:"pick_list_id" gorm:"index;not null"`
+	Sequence     int      `json:"sequence"`
+	ProductID    uint     `json:"product_id" gorm:"index;not null"`
+	LocationID   *uint    `json:"location_id" gorm:"index"`                    // 货位, 为空时从未分配库位的库存拣货
+	LocationPath string   `json:"location_path" gorm:"size:100"`               // 货位完整编码, 未分配时为商品库位
+	Quantity     float64  `json:"quantity" gorm:"type:decimal(14,3);not null"` // 应拣数量 (基本单位)
+	PickedQty    *float64 `json:"picked_qty" gorm:"type:decimal(14,3)"`        // 实拣数量 (确认前为空)
+	ShortQty     float64  `json:"short_qty" gorm:"type:decimal(14,3);default:0"`
+
+	// 关联
+	Product *Product `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+}
+
+// TableName 指定表名
+func (PickListLine) TableName() string { return "pick_list_lines" }
+
+// PickListOrder 拣货单包含的出库批次明细, 确认时实拣数量按批次顺序分配, 短拣由靠后的批次承担
+type PickListOrder struct {
+	ID          uint    `json:"id" gorm:"primaryKey"`
+	PickListID  uint    `json:"pick_list_id" gorm:"index;not null"`
+	ReferenceNo string  `json:"reference_no" gorm:"size:100;index"`
+	ProductID   uint    `json:"product_id" gorm:"not null"`
+	Quantity    float64 `json:"quantity" gorm:"type:decimal(14,3);not null"` // 需求数量 (基本单位)
+	PickedQty   float64 `json:"picked_qty" gorm:"type:decimal(14,3);default:0"`
+	ShortQty    float64 `json:"short_qty" gorm:"type:decimal(14,3);default:0"`
+	Notes       string  `json:"notes" gorm:"size:500"`
+
+	// 关联
+	Product *Product `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+}
+
+// TableName 指定表名
+func (PickListOrder) TableName() string { return "pick_list_orders" }
+
+// PickOrderLine 出库批次明细行
+type PickOrderLine struct {
+	ProductID uint    `json:"product_id" binding:"required"`
+	Quantity  float64 `json:"quantity" binding:"required,gt=0"`
+	Unit      string  `json:"unit"` // 录入单位, 为空时为基本单位
+	Notes     string  `json:"notes"`
+}
+
+// PickOrderBatch 一个出库批次 (如一张销售订单)
+type PickOrderBatch struct {
+	ReferenceNo string          `json:"reference_no" binding:"required"`
+	Lines       []PickOrderLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+// PickListRequest 生成拣货单请求
+type PickListRequest struct {
+	Batches []PickOrderBatch `json:"batches" binding:"required,min=1,dive"`
+	Notes   string           `json:"notes"`
+}
+
+// PickConfirmLine 拣货确认行
+type PickConfirmLine struct {
+	LineID    uint    `json:"line_id" binding:"required"`
+	PickedQty float64 `json:"picked_qty" binding:"min=0"`
+}
+
+// PickConfirmRequest 拣货确认请求; 未列出的明细按应拣数量全部拣出
+type PickConfirmRequest struct {
+	Lines []PickConfirmLine `json:"lines" binding:"dive"`
+	Notes string            `json:"notes"`
+}
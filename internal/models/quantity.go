@@ -0,0 +1,52 @@
+package models
+
+import "math"
+
+// ---------- 数量精度 ----------
+
+// MaxQtyPrecision 数量最多保留的小数位数 (与 decimal(14,3) 列一致)
+const MaxQtyPrecision = 3
+
+// RoundQty 按小数位数四舍五入数量, 消除浮点累加误差
+func RoundQty(v float64, precision int) float64 {
+	scale := math.Pow(10, float64(clampPrecision(precision)))
+	return math.Round(v*scale) / scale
+}
+
+// ValidQty 数量的小数位数是否不超过 precision
+func ValidQty(v float64, precision int) bool {
+	return math.Abs(RoundQty(v, precision)-v) < 1e-9
+}
+
+// RoundQty 按商品的数量精度四舍五入
+func (p *Product) RoundQty(v float64) float64 {
+	return RoundQty(v, p.QtyPrecision)
+}
+
+// ValidQty 数量是否符合商品的数量精度
+func (p *Product) ValidQty(v float64) bool {
+	return ValidQty(v, p.QtyPrecision)
+}
+
+// CeilQty 按小数位数向上取整数量
+// 先消除浮点误差, 避免 2.0000001 被取整为 3
+func CeilQty(v float64, precision int) float64 {
+	scale := math.Pow(10, float64(clampPrecision(precision)))
+	return math.Ceil(RoundQty(v*scale, MaxQtyPrecision)) / scale
+}
+
+// FloorQty 按小数位数向下取整数量, 同样先消除浮点误差
+func FloorQty(v float64, precision int) float64 {
+	scale := math.Pow(10, float64(clampPrecision(precision)))
+	return math.Floor(RoundQty(v*scale, MaxQtyPrecision)) / scale
+}
+
+func clampPrecision(precision int) int {
+	if precision < 0 {
+		return 0
+	}
+	if precision > MaxQtyPrecision {
+		return MaxQtyPrecision
+	}
+	return precision
+}
@@ -0,0 +1,92 @@
+package models
+
+// ---------- 补货与采购模型 ----------
+
+// 采购单状态
+const (
+	PurchaseOrderDraft     = "draft"     // 草稿
+	PurchaseOrderCancelled = "cancelled" // 已取消
+)
+
+// PurchaseOrder 采购单
+type PurchaseOrder struct {
+	BaseModel
+	OrderNo       string  `json:"order_no" gorm:"uniqueIndex;size:50;not null"`
+	SupplierID    uint    `json:"supplier_id" gorm:"index;not null"`
+	Status        string  `json:"status" gorm:"size:20;not null;index"`
+	TotalQuantity float64 `json:"total_quantity" gorm:"type:decimal(14,3)"`
+	TotalAmount   float64 `json:"total_amount" gorm:"type:decimal(12,2);default:0"`
+	Notes         string  `json:"notes" gorm:"size:500"`
+	CreatedBy     uint    `json:"created_by"`
+	CreatedByName string  `json:"created_by_name" gorm:"size:50"`
+
+	// 关联
+	Supplier *Supplier           `json:"supplier,omitempty" gorm:"foreignKey:SupplierID"`
+	Items    []PurchaseOrderItem `json:"items,omitempty" gorm:"foreignKey:OrderID"`
+}
+
+// TableName 指定表名
+func (PurchaseOrder) TableName() string { return "purchase_orders" }
+
+// PurchaseOrderItem 采购单明细
+type PurchaseOrderItem struct {
+	ID        uint    `json:"id" gorm:"primaryKey"`
+	OrderID   uint    `json:"order_id" gorm:"index;not null"`
+	ProductID uint    `json:"product_id" gorm:"index;not null"`
+	Quantity  float64 `json:"quantity" gorm:"type:decimal(14,3)"`
+	UnitCost  float64 `json:"unit_cost" gorm:"type:decimal(12,2);default:0"`
+	Amount    float64 `json:"amount" gorm:"type:decimal(12,2);default:0"`
+
+	// 关联
+	Product *Product `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+}
+
+// TableName 指定表名
+func (PurchaseOrderItem) TableName() string { return "purchase_order_items" }
+
+// ReorderSuggestion 单个商品的补货建议
+type ReorderSuggestion struct {
+	ProductID     uint    `json:"product_id"`
+	SKU           string  `json:"sku"`
+	Name          string  `json:"name"`
+	Unit          string  `json:"unit"`
+	CurrentStock  float64 `json:"current_stock"`
+	OnOrder       float64 `json:"on_order"`        // 草稿采购单中已在途数量
+	AvgDailyUsage float64 `json:"avg_daily_usage"` // 日均出库
+	LeadTimeDays  int     `json:"lead_time_days"`
+	SafetyStock   float64 `json:"safety_stock"`
+	ReorderPoint  float64 `json:"reorder_point"`
+	MaxStock      float64 `json:"max_stock"`
+	SuggestedQty  float64 `json:"suggested_qty"`
+	UnitCost      float64 `json:"unit_cost"`
+	Amount        float64 `json:"amount"`
+}
+
+// SupplierReorderGroup 按供应商分组的补货建议
+type SupplierReorderGroup struct {
+	SupplierID   uint                `json:"supplier_id"` // 0 表示未指定供应商
+	SupplierName string              `json:"supplier_name"`
+	LeadTimeDays int                 `json:"lead_time_days"`
+	TotalAmount  float64             `json:"total_amount"`
+	Lines        []ReorderSuggestion `json:"lines"`
+}
+
+// ReorderQuery 补货建议查询参数
+type ReorderQuery struct {
+	Days int `form:"days"` // 历史天数, 默认取配置
+}
+
+// CreatePurchaseOrdersRequest 由补货建议生成草稿采购单请求
+type CreatePurchaseOrdersRequest struct {
+	Days        int    `json:"days"`
+	SupplierIDs []uint `json:"supplier_ids"` // 为空表示全部供应商
+	ProductIDs  []uint `json:"product_ids"`  // 为空表示全部建议商品
+	Notes       string `json:"notes"`
+}
+
+// DailyQuantity 商品单日数量汇总 (用于需求统计)
+type DailyQuantity struct {
+	ProductID uint    `json:"product_id"`
+	Date      string  `json:"date"`
+	Quantity  float64 `json:"quantity"`
+}
@@ -0,0 +1,118 @@
+package models
+
+import "time"
+
+// ---------- 库龄报表 ----------
+
+// 库龄分段
+const (
+	AgingBucket0To30   = "0-30"
+	AgingBucket31To90  = "31-90"
+	AgingBucket91To180 = "91-180"
+	AgingBucketOver180 = "180+"
+)
+
+// AgingBuckets 库龄分段 (按顺序)
+var AgingBuckets = []string{AgingBucket0To30, AgingBucket31To90, AgingBucket91To180, AgingBucketOver180}
+
+// AgingQuery 库龄报表参数
+type AgingQuery struct {
+	DeadDays int `form:"dead_days"` // 超过该天数无出库视为呆滞, 默认 90
+}
+
+// StockAgingItem 单个商品库龄
+type StockAgingItem struct {
+	ProductID    uint       `json:"product_id"`
+	SKU          string     `json:"sku"`
+	Name         string     `json:"name"`
+	CategoryName string     `json:"category_name"`
+	SupplierName string     `json:"supplier_name"`
+	CurrentStock float64    `json:"current_stock"`
+	Value        float64    `json:"value"`
+	LastInAt     *time.Time `json:"last_in_at"`
+	LastOutAt    *time.Time `json:"last_out_at"`
+	AgeDays      int        `json:"age_days"` // 距最近一次出入库的天数
+	Bucket       string     `json:"bucket"`
+	DeadStock    bool       `json:"dead_stock"`
+	IdleDays     int        `json:"idle_days"` // 距最近一次出库的天数 (从未出库时按建档时间)
+}
+
+// AgingBucketSummary 库龄分段汇总
+type AgingBucketSummary struct {
+	Bucket       string  `json:"bucket"`
+	ProductCount int     `json:"product_count"`
+	Quantity     float64 `json:"quantity"`
+	Value        float64 `json:"value"`
+}
+
+// AgingGroup 按分类/供应商汇总的库龄金额
+type AgingGroup struct {
+	ID        uint               `json:"id"` // 0 表示未指定
+	Name      string             `json:"name"`
+	Buckets   map[string]float64 `json:"buckets"` // 分段 -> 金额
+	Value     float64            `json:"value"`
+	DeadValue float64            `json:"dead_value"` // 呆滞库存金额
+}
+
+// StockAgingReport 库龄与呆滞库存报表
+type StockAgingReport struct {
+	DeadDays       int                  `json:"dead_days"`
+	TotalValue     float64              `json:"total_value"`
+	DeadStockValue float64              `json:"dead_stock_value"`
+	Buckets        []AgingBucketSummary `json:"buckets"`
+	ByCategory     []AgingGroup         `json:"by_category"`
+	BySupplier     []AgingGroup         `json:"by_supplier"`
+	DeadStock      []StockAgingItem     `json:"dead_stock"`
+	Items          []StockAgingItem     `json:"items"`
+}
+
+// ProductLastMovement 商品最近出入库时间
+type ProductLastMovement struct {
+	ProductID uint
+	LastIn    *time.Time
+	LastOut   *time.Time
+}
+
+// ---------- 周转报表 ----------
+
+// TurnoverQuery 周转报表参数
+type TurnoverQuery struct {
+	From    string `form:"from"`     // 起始日期 (含), 默认 30 天前
+	To      string `form:"to"`       // 截止日期 (含), 默认今天
+	GroupBy string `form:"group_by"` // product / category / supplier, 默认 product
+}
+
+// ProductPeriodFlow 商品期间出入库汇总
+type ProductPeriodFlow struct {
+	ProductID   uint
+	ReceivedQty float64
+	SoldQty     float64
+	SoldValue   float64 // 出库成本
+}
+
+// TurnoverRow 周转指标
+type TurnoverRow struct {
+	ID                uint    `json:"id"`
+	Name              string  `json:"name"`
+	SKU               string  `json:"sku,omitempty"`
+	OpeningQty        float64 `json:"opening_qty"`
+	ClosingQty        float64 `json:"closing_qty"`
+	ReceivedQty       float64 `json:"received_qty"`
+	SoldQty           float64 `json:"sold_qty"`
+	COGS              float64 `json:"cogs"`                // 期间出库成本
+	AvgInventoryValue float64 `json:"avg_inventory_value"` // (期初 + 期末库存金额) / 2
+	TurnoverRatio     float64 `json:"turnover_ratio"`      // 出库成本 / 平均库存金额
+	DaysOnHand        float64 `json:"days_on_hand"`        // 期间天数 / 周转率, 无出库时为 -1
+	DaysOfSupply      float64 `json:"days_of_supply"`      // 期末数量 / 日均出库量, 无出库时为 -1
+	SellThroughRate   float64 `json:"sell_through_rate"`   // 出库量 / (期初 + 入库量) × 100
+}
+
+// TurnoverReport 周转报表
+type TurnoverReport struct {
+	From    string        `json:"from"`
+	To      string        `json:"to"`
+	Days    int           `json:"days"`
+	GroupBy string        `json:"group_by"`
+	Total   TurnoverRow   `json:"total"`
+	Items   []TurnoverRow `json:"items"`
+}
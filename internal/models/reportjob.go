@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// ---------- 定时报表 ----------
+
+// 报表类型
+const (
+	ReportLowStock        = "low_stock"        // 低库存清单
+	ReportValuation       = "valuation"        // 库存估值
+	ReportMovementSummary = "movement_summary" // 出入库汇总
+)
+
+// 投递方式
+const (
+	DeliveryEmail     = "email"     // SMTP 邮件
+	DeliveryDirectory = "directory" // 写入配置的输出目录
+)
+
+// 执行状态
+const (
+	ReportRunSuccess = "success"
+	ReportRunFailed  = "failed"
+)
+
+// ReportJob 定时报表任务
+type ReportJob struct {
+	BaseModel
+	Name          string     `json:"name" gorm:"size:100;not null"`
+	ReportType    string     `json:"report_type" gorm:"size:30;not null"`
+	Format        string     `json:"format" gorm:"size:10;not null"`    // csv / xlsx / pdf
+	Schedule      string     `json:"schedule" gorm:"size:100;not null"` // 标准 5 段 cron 表达式, 如 "0 8 * * 1"
+	PeriodDays    int        `json:"period_days" gorm:"default:7"`      // 出入库汇总的统计天数
+	Delivery      string     `json:"delivery" gorm:"size:20;not null"`
+	Recipients    string     `json:"recipients" gorm:"size:500"` // 邮件收件人, 逗号分隔
+	Enabled       bool       `json:"enabled" gorm:"default:true;index"`
+	NextRunAt     *time.Time `json:"next_run_at" gorm:"index"`
+	LastRunAt     *time.Time `json:"last_run_at"`
+	LastStatus    string     `json:"last_status" gorm:"size:20"`
+	CreatedBy     uint       `json:"created_by"`
+	CreatedByName string     `json:"created_by_name" gorm:"size:50"`
+}
+
+// TableName 指定表名
+func (ReportJob) TableName() string { return "report_jobs" }
+
+// ReportJobRun 报表任务执行记录
+type ReportJobRun struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	JobID      uint      `json:"job_id" gorm:"index;not null"`
+	Trigger    string    `json:"trigger" gorm:"size:20"` // schedule / manual
+	Status     string    `json:"status" gorm:"size:20;index"`
+	RowCount   int       `json:"row_count"`
+	FileName   string    `json:"file_name" gorm:"size:255"`
+	FileSize   int       `json:"file_size"`
+	Delivery   string    `json:"delivery" gorm:"size:20"`
+	Target     string    `json:"target" gorm:"size:500"` // 收件人或文件路径
+	Error      string    `json:"error,omitempty" gorm:"size:1000"`
+	StartedAt  time.Time `json:"started_at" gorm:"index"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// TableName 指定表名
+func (ReportJobRun) TableName() string { return "report_job_runs" }
+
+// ReportJobRequest 创建/更新报表任务请求
+type ReportJobRequest struct {
+	Name       string `json:"name" binding:"required"`
+	ReportType string `json:"report_type" binding:"required"`
+	Format     string `json:"format" binding:"required"`
+	Schedule   string `json:"schedule" binding:"required"`
+	PeriodDays *int   `json:"period_days"` // 1~366, 为空时新任务默认 7, 更新时保持不变
+	Delivery   string `json:"delivery" binding:"required"`
+	Recipients string `json:"recipients"`
+	Enabled    *bool  `json:"enabled"`
+}
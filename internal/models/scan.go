@@ -0,0 +1,51 @@
+package models
+
+import "go-cargo/internal/gs1"
+
+// ---------- 扫码 ----------
+
+// 扫码匹配方式
+const (
+	ScanMatchBarcode      = "barcode"       // 商品条码完全一致
+	ScanMatchAltBarcode   = "alt_barcode"   // 备用条码完全一致
+	ScanMatchSKU          = "sku"           // SKU 完全一致
+	ScanMatchSupplierPart = "supplier_part" // 供应商料号完全一致
+	ScanMatchGTIN         = "gtin"          // 条码为同一 GTIN 的其他长度表示 (UPC-A / EAN-13 / GTIN-14)
+)
+
+// ScanResult 扫码解析结果
+type ScanResult struct {
+	Code       string             `json:"code"`
+	MatchedBy  string             `json:"matched_by,omitempty"`
+	Product    *Product           `json:"product,omitempty"`
+	Matches    []Product          `json:"matches,omitempty"`    // 多个商品共用同一条码时的候选
+	Identifier *ProductIdentifier `json:"identifier,omitempty"` // 按附加标识匹配时的标识
+	GS1        *gs1.Data          `json:"gs1,omitempty"`        // GS1-128 应用标识符解析结果
+}
+
+// ScanStockInRequest 扫码入库请求
+type ScanStockInRequest struct {
+	Code        string  `json:"code" binding:"required"`
+	Quantity    float64 `json:"quantity" binding:"min=0"` // 为 0 时取 GS1 数量 (30/37), 否则为 1
+	Unit        string  `json:"unit"`
+	UnitCost    float64 `json:"unit_cost"`
+	ReferenceNo string  `json:"reference_no"`
+	Notes       string  `json:"notes"`
+}
+
+// ScanStockOutRequest 扫码出库请求
+type ScanStockOutRequest struct {
+	Code        string  `json:"code" binding:"required"`
+	Quantity    float64 `json:"quantity" binding:"min=0"` // 为 0 时取 GS1 数量 (30/37), 否则为 1
+	Unit        string  `json:"unit"`
+	ReferenceNo string  `json:"reference_no"`
+	Notes       string  `json:"notes"`
+}
+
+// ScanStockResult 扫码出入库结果
+type ScanStockResult struct {
+	Scan      *ScanResult `json:"scan"`
+	Quantity  float64     `json:"quantity"` // 基本单位数量
+	BeforeQty float64     `json:"before_qty"`
+	AfterQty  float64     `json:"after_qty"`
+}
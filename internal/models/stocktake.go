@@ -0,0 +1,121 @@
+package models
+
+import "time"
+
+// ---------- 盘点模型 ----------
+
+// 盘点范围
+const (
+	StocktakeScopeAll      = "all"      // 全部商品
+	StocktakeScopeCategory = "category" // 指定分类
+	StocktakeScopeLocation = "location" // 库位前缀
+)
+
+// 盘点单状态
+const (
+	StocktakeCounting  = "counting"  // 盘点中
+	StocktakeApproved  = "approved"  // 已审核过账
+	StocktakeCancelled = "cancelled" // 已取消
+)
+
+// SourceStocktake 库存记录来源: 盘点单
+const SourceStocktake = "stocktake"
+
+// StocktakeSession 盘点单, 创建时按范围快照账面数量
+type StocktakeSession struct {
+	BaseModel
+	SessionNo      string     `json:"session_no" gorm:"uniqueIndex;size:50;not null"`
+	Name           string     `json:"name" gorm:"size:200"`
+	ScopeType      string     `json:"scope_type" gorm:"size:20;not null"`
+	CategoryID     *uint      `json:"category_id"`
+	LocationPrefix string     `json:"location_prefix" gorm:"size:100"`
+	Freeze         bool       `json:"freeze"` // 盘点期间冻结范围内商品的出入库
+	Status         string     `json:"status" gorm:"size:20;not null;index"`
+	Notes          string     `json:"notes" gorm:"size:500"`
+	CreatedBy      uint       `json:"created_by"`
+	CreatedByName  string     `json:"created_by_name" gorm:"size:50"`
+	ApprovedBy     uint       `json:"approved_by"`
+	ApprovedByName string     `json:"approved_by_name" gorm:"size:50"`
+	ApprovedAt     *time.Time `json:"approved_at"`
+
+	// 关联
+	Items []StocktakeItem `json:"items,omitempty" gorm:"foreignKey:SessionID"`
+}
+
+// TableName 指定表名
+func (StocktakeSession) TableName() string { return "stocktake_sessions" }
+
+// StocktakeItem 盘点明细 (每个商品一行)
+type StocktakeItem struct {
+	ID            uint     `json:"id" gorm:"primaryKey"`
+	SessionID     uint     `json:"session_id" gorm:"uniqueIndex:idx_stocktake_item;not null"`
+	ProductID     uint     `json:"product_id" gorm:"uniqueIndex:idx_stocktake_item;not null"`
+	ExpectedQty   float64  `json:"expected_qty" gorm:"type:decimal(14,3)"`        // 快照账面数量
+	UnitCost      float64  `json:"unit_cost" gorm:"type:decimal(12,2);default:0"` // 快照成本价
+	CountedQty    *float64 `json:"counted_qty" gorm:"type:decimal(14,3)"`         // 实盘数量 (未盘为空)
+	VarianceQty   float64  `json:"variance_qty" gorm:"type:decimal(14,3)"`        // 实盘 - 账面
+	VarianceValue float64  `json:"variance_value" gorm:"type:decimal(12,2);default:0"`
+	RecordID      *uint    `json:"record_id"` // 过账生成的调整记录
+
+	// 关联
+	Product *Product `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+}
+
+// TableName 指定表名
+func (StocktakeItem) TableName() string { return "stocktake_items" }
+
+// StocktakeCount 盘点员提交的计数 (同一盘点员对同一商品的新计数覆盖旧计数, 实盘数量取最新计数)
+type StocktakeCount struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	SessionID   uint      `json:"session_id" gorm:"index;not null"`
+	ItemID      uint      `json:"item_id" gorm:"index;not null"`
+	ProductID   uint      `json:"product_id" gorm:"not null"`
+	CounterID   uint      `json:"counter_id" gorm:"index"`
+	CounterName string    `json:"counter_name" gorm:"size:50"`
+	Quantity    float64   `json:"quantity" gorm:"type:decimal(14,3)"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (StocktakeCount) TableName() string { return "stocktake_counts" }
+
+// StocktakeRequest 创建盘点单请求
+type StocktakeRequest struct {
+	Name           string `json:"name"`
+	ScopeType      string `json:"scope_type" binding:"required,oneof=all category location"`
+	CategoryID     *uint  `json:"category_id"`
+	LocationPrefix string `json:"location_prefix"`
+	Freeze         bool   `json:"freeze"`
+	Notes          string `json:"notes"`
+}
+
+// StocktakeCountLine 计数行
+type StocktakeCountLine struct {
+	ProductID uint    `json:"product_id" binding:"required"`
+	Quantity  float64 `json:"quantity" binding:"min=0"`
+}
+
+// StocktakeCountRequest 提交计数请求
+type StocktakeCountRequest struct {
+	Lines []StocktakeCountLine `json:"lines" binding:"required,min=1,dive"`
+}
+
+// StocktakeApproveRequest 审核盘点单请求
+type StocktakeApproveRequest struct {
+	ZeroUncounted bool   `json:"zero_uncounted"` // 未盘商品按 0 处理, 默认跳过
+	Notes         string `json:"notes"`
+}
+
+// StocktakeVarianceReport 盘点差异报告
+type StocktakeVarianceReport struct {
+	Session       *StocktakeSession `json:"session"`
+	TotalItems    int               `json:"total_items"`
+	CountedItems  int               `json:"counted_items"`
+	VarianceItems int               `json:"variance_items"`
+	GainQty       float64           `json:"gain_qty"`   // 盘盈数量
+	LossQty       float64           `json:"loss_qty"`   // 盘亏数量
+	GainValue     float64           `json:"gain_value"` // 盘盈金额
+	LossValue     float64           `json:"loss_value"` // 盘亏金额
+	NetValue      float64           `json:"net_value"`
+	Items         []StocktakeItem   `json:"items"`
+}
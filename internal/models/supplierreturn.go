@@ -0,0 +1,120 @@
+package models
+
+import "time"
+
+// ---------- 退供应商 ----------
+
+// 退供应商单状态
+const (
+	SupplierReturnDraft     = "draft"     // 草稿, 未扣减库存
+	SupplierReturnShipped   = "shipped"   // 已退货出库, 等待供应商确认贷项
+	SupplierReturnCredited  = "credited"  // 供应商已确认贷项
+	SupplierReturnCancelled = "cancelled" // 已取消
+)
+
+// SourceSupplierReturn 库存记录来源: 退供应商单
+const SourceSupplierReturn = "supplier_return"
+
+// SupplierReturn 退供应商单, 可关联原入库单 (入库记录的关联单号)
+type SupplierReturn struct {
+	BaseModel
+	ReturnNo       string     `json:"return_no" gorm:"uniqueIndex;size:50;not null"`
+	SupplierID     uint       `json:"supplier_id" gorm:"index;not null"`
+	ReceiptNo      string     `json:"receipt_no" gorm:"size:100;index"` // 原入库单号, 为空表示不关联
+	Status         string     `json:"status" gorm:"size:20;not null;index"`
+	TotalQuantity  float64    `json:"total_quantity" gorm:"type:decimal(14,3)"`
+	CreditAmount   float64    `json:"credit_amount" gorm:"type:decimal(12,2);default:0"`   // 应退金额
+	CreditedAmount float64    `json:"credited_amount" gorm:"type:decimal(12,2);default:0"` // 供应商确认的贷项金额
+	CreditNoteNo   string     `json:"credit_note_no" gorm:"size:100"`                      // 供应商贷项通知单号
+	Reason         string     `json:"reason" gorm:"size:200"`
+	Notes          string     `json:"notes" gorm:"size:500"`
+	CreatedBy      uint       `json:"created_by"`
+	CreatedByName  string     `json:"created_by_name" gorm:"size:50"`
+	ShippedByName  string     `json:"shipped_by_name" gorm:"size:50"`
+	ShippedAt      *time.Time `json:"shipped_at" gorm:"index"`
+	CreditedByName string     `json:"credited_by_name" gorm:"size:50"`
+	CreditedAt     *time.Time `json:"credited_at"`
+
+	// 关联
+	Supplier *Supplier            `json:"supplier,omitempty" gorm:"foreignKey:SupplierID"`
+	Items    []SupplierReturnItem `json:"items,omitempty" gorm:"foreignKey:ReturnID"`
+}
+
+// TableName 指定表名
+func (SupplierReturn) TableName() string { return "supplier_returns" }
+
+// SupplierReturnItem 退供应商明细
+type SupplierReturnItem struct {
+	ID            uint    `json:"id" gorm:"primaryKey"`
+	ReturnID      uint    `json:"return_id" gorm:"index;not null"`
+	ProductID     uint    `json:"product_id" gorm:"index;not null"`
+	LocationID    *uint   `json:"location_id"`                                 // 出库货位 (如隔离位), 为空时按默认顺序扣减
+	Quantity      float64 `json:"quantity" gorm:"type:decimal(14,3);not null"` // 基本单位数量
+	EntryQuantity float64 `json:"entry_quantity" gorm:"type:decimal(14,3);default:0"`
+	EntryUnit     string  `json:"entry_unit" gorm:"size:20"`
+	UnitCost      float64 `json:"unit_cost" gorm:"type:decimal(12,2);default:0"` // 每基本单位退货单价
+	CreditAmount  float64 `json:"credit_amount" gorm:"type:decimal(12,2);default:0"`
+	Reason        string  `json:"reason" gorm:"size:200"`
+	RecordID      *uint   `json:"record_id"` // 出库时生成的库存记录
+
+	// 关联
+	Product *Product `json:"product,omitempty" gorm:"foreignKey:ProductID"`
+}
+
+// TableName 指定表名
+func (SupplierReturnItem) TableName() string { return "supplier_return_items" }
+
+// SupplierReturnItemRequest 退供应商明细请求
+type SupplierReturnItemRequest struct {
+	ProductID  uint     `json:"product_id" binding:"required"`
+	Quantity   float64  `json:"quantity" binding:"required,gt=0"`
+	Unit       string   `json:"unit"`      // 录入单位, 为空时为基本单位
+	UnitCost   *float64 `json:"unit_cost"` // 每录入单位退货单价, 为空时取原入库成本或商品成本价
+	LocationID *uint    `json:"location_id"`
+	Reason     string   `json:"reason"`
+}
+
+// SupplierReturnRequest 创建退供应商单请求
+type SupplierReturnRequest struct {
+	SupplierID uint                        `json:"supplier_id" binding:"required"`
+	ReceiptNo  string                      `json:"receipt_no"`
+	Reason     string                      `json:"reason"`
+	Notes      string                      `json:"notes"`
+	Items      []SupplierReturnItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// SupplierCreditRequest 供应商确认贷项请求
+type SupplierCreditRequest struct {
+	CreditNoteNo   string   `json:"credit_note_no"`
+	CreditedAmount *float64 `json:"credited_amount" binding:"omitempty,min=0"` // 为空时按应退金额
+	Notes          string   `json:"notes"`
+}
+
+// SupplierReturnRateQuery 供应商退货率报表参数
+type SupplierReturnRateQuery struct {
+	From string `form:"from"` // 起始日期 (含), 默认 90 天前
+	To   string `form:"to"`   // 截止日期 (含), 默认今天
+}
+
+// SupplierReturnRateRow 单个供应商的退货率
+type SupplierReturnRateRow struct {
+	SupplierID      uint    `json:"supplier_id"`
+	SupplierName    string  `json:"supplier_name"`
+	ReceivedQty     float64 `json:"received_qty"`      // 期间入库数量
+	ReceivedValue   float64 `json:"received_value"`    // 期间入库金额
+	ReturnCount     int     `json:"return_count"`      // 期间退货出库的退供应商单数
+	ReturnedQty     float64 `json:"returned_qty"`      // 期间退货数量
+	ReturnedValue   float64 `json:"returned_value"`    // 期间应退金额
+	QtyReturnRate   float64 `json:"qty_return_rate"`   // 退货数量 / 入库数量 × 100
+	ValueReturnRate float64 `json:"value_return_rate"` // 应退金额 / 入库金额 × 100
+	PendingCredit   float64 `json:"pending_credit"`    // 已退货尚未确认贷项的金额 (不限期间)
+	CreditedAmount  float64 `json:"credited_amount"`   // 期间退货中供应商已确认的贷项金额
+}
+
+// SupplierReturnRateReport 供应商退货率报表
+type SupplierReturnRateReport struct {
+	From  string                  `json:"from"`
+	To    string                  `json:"to"`
+	Total SupplierReturnRateRow   `json:"total"`
+	Items []SupplierReturnRateRow `json:"items"`
+}
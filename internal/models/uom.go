@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// ---------- 计量单位换算 ----------
+
+// 包装层级
+const (
+	UnitLevelEach   = "each"   // 单件
+	UnitLevelInner  = "inner"  // 内包装
+	UnitLevelCase   = "case"   // 箱
+	UnitLevelPallet = "pallet" // 托盘
+)
+
+// ProductUnit 商品包装单位; 基本单位为 Product.Unit, Factor 为每个包装单位折合的基本单位数量
+type ProductUnit struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProductID uint      `json:"product_id" gorm:"uniqueIndex:idx_product_unit;not null"`
+	Name      string    `json:"name" gorm:"uniqueIndex:idx_product_unit;size:20;not null"` // 如 "箱"
+	Level     string    `json:"level" gorm:"size:20;not null"`
+	Factor    int       `json:"factor" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ProductUnit) TableName() string { return "product_units" }
+
+// ProductUnitRequest 包装单位请求
+type ProductUnitRequest struct {
+	Name   string `json:"name" binding:"required,max=20"`
+	Level  string `json:"level" binding:"required,oneof=each inner case pallet"`
+	Factor int    `json:"factor" binding:"required,min=2"`
+}
+
+// ProductUnitList 商品的基本单位与包装单位
+type ProductUnitList struct {
+	BaseUnit string        `json:"base_unit"`
+	Units    []ProductUnit `json:"units"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// ---------- 多规格商品 ----------
+
+// 商品列表视图
+const (
+	ProductViewGrouped = "grouped" // 仅款式与普通商品, 款式显示规格汇总库存
+	ProductViewFlat    = "flat"    // 仅可出入库的商品 (规格与普通商品)
+)
+
+// ProductAttribute 款式的规格属性定义, 如 颜色: 红色,蓝色
+type ProductAttribute struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProductID uint      `json:"product_id" gorm:"uniqueIndex:idx_product_attribute;not null"`
+	Name      string    `json:"name" gorm:"uniqueIndex:idx_product_attribute;size:50;not null"`
+	Options   string    `json:"options" gorm:"size:1000;not null"` // 可选值, 逗号分隔, 按顺序生成规格
+	SortOrder int       `json:"sort_order" gorm:"default:0"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ProductAttribute) TableName() string { return "product_attributes" }
+
+// ProductVariantValue 规格商品的属性取值
+type ProductVariantValue struct {
+	ID        uint   `json:"-" gorm:"primaryKey"`
+	ProductID uint   `json:"-" gorm:"uniqueIndex:idx_variant_value;not null"`
+	Name      string `json:"name" gorm:"uniqueIndex:idx_variant_value;size:50;not null"`
+	Value     string `json:"value" gorm:"size:100;not null"`
+}
+
+// TableName 指定表名
+func (ProductVariantValue) TableName() string { return "product_variant_values" }
+
+// ProductAttributeRequest 单个规格属性
+type ProductAttributeRequest struct {
+	Name    string   `json:"name" binding:"required,max=50"`
+	Options []string `json:"options" binding:"required,min=1,dive,required,max=100"`
+}
+
+// ProductAttributesRequest 设置款式的全部规格属性 (按顺序)
+type ProductAttributesRequest struct {
+	Attributes []ProductAttributeRequest `json:"attributes" binding:"dive"`
+}
+
+// GenerateVariantsRequest 生成规格请求
+// Options 为空时生成全部组合, 否则仅在指定属性的指定取值范围内生成
+type GenerateVariantsRequest struct {
+	Options map[string][]string `json:"options"`
+}
+
+// GenerateVariantsResult 生成规格结果
+type GenerateVariantsResult struct {
+	Created []Product `json:"created"`
+	Skipped int       `json:"skipped"` // 已存在而跳过的组合数
+}
+
+// VariantRequest 更新规格的独立设置; 价格均为空时恢复跟随款式价格
+type VariantRequest struct {
+	Barcode      string   `json:"barcode"`
+	Location     string   `json:"location"`
+	CostPrice    *float64 `json:"cost_price"`
+	SellingPrice *float64 `json:"selling_price"`
+	MinStock     float64  `json:"min_stock"`
+	MaxStock     float64  `json:"max_stock"`
+	SafetyStock  float64  `json:"safety_stock"`
+	Status       int      `json:"status"`
+}
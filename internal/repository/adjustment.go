@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"go-cargo/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 调整原因 ====================
+
+// ListReasonCodes 获取原因代码列表
+func (r *Repository) ListReasonCodes(query *models.PaginationQuery) ([]models.ReasonCode, int64, error) {
+	var reasons []models.ReasonCode
+	var total int64
+
+	db := r.db.Model(&models.ReasonCode{})
+	if query.Keyword != "" {
+		db = db.Where("code LIKE ? OR name LIKE ?", "%"+query.Keyword+"%", "%"+query.Keyword+"%")
+	}
+	if query.Status != nil {
+		db = db.Where("status = ?", *query.Status)
+	}
+
+	db.Count(&total)
+	err := db.Order("sort_order ASC, id ASC").
+		Offset(query.GetOffset()).
+		Limit(query.PageSize).
+		Find(&reasons).Error
+	return reasons, total, err
+}
+
+// GetAllReasonCodes 获取所有启用的原因代码 (用于下拉选择)
+func (r *Repository) GetAllReasonCodes() ([]models.ReasonCode, error) {
+	var reasons []models.ReasonCode
+	err := r.db.Where("status = 1").Order("sort_order ASC").Find(&reasons).Error
+	return reasons, err
+}
+
+// GetReasonCodeByID 根据ID查找原因代码
+func (r *Repository) GetReasonCodeByID(id uint) (*models.ReasonCode, error) {
+	var reason models.ReasonCode
+	err := r.db.First(&reason, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reason, nil
+}
+
+// GetReasonCodeByCode 根据代码查找原因代码
+func (r *Repository) GetReasonCodeByCode(code string) (*models.ReasonCode, error) {
+	var reason models.ReasonCode
+	err := r.db.Where("code = ?", code).First(&reason).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reason, nil
+}
+
+// CreateReasonCode 创建原因代码
+func (r *Repository) CreateReasonCode(reason *models.ReasonCode) error {
+	return r.db.Create(reason).Error
+}
+
+// UpdateReasonCode 更新原因代码
+func (r *Repository) UpdateReasonCode(reason *models.ReasonCode) error {
+	return r.db.Save(reason).Error
+}
+
+// DeleteReasonCode 软删除原因代码
+func (r *Repository) DeleteReasonCode(id uint) error {
+	return r.db.Delete(&models.ReasonCode{}, id).Error
+}
+
+// ==================== 调整审批 ====================
+
+// CreateStockAdjustment 创建待审批调整
+func (r *Repository) CreateStockAdjustment(adj *models.StockAdjustment) error {
+	return r.db.Create(adj).Error
+}
+
+// GetStockAdjustmentByID 根据ID查找调整单
+func (r *Repository) GetStockAdjustmentByID(id uint) (*models.StockAdjustment, error) {
+	var adj models.StockAdjustment
+	err := r.db.Preload("Product").First(&adj, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &adj, nil
+}
+
+// ListStockAdjustments 获取调整单列表
+func (r *Repository) ListStockAdjustments(query *models.PaginationQuery, status string) ([]models.StockAdjustment, int64, error) {
+	var adjs []models.StockAdjustment
+	var total int64
+
+	db := r.db.Model(&models.StockAdjustment{})
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	db.Count(&total)
+	err := db.Preload("Product").
+		Order("id DESC").
+		Offset(query.GetOffset()).
+		Limit(query.PageSize).
+		Find(&adjs).Error
+	return adjs, total, err
+}
+
+// UpdateStockAdjustment 更新调整单
+func (r *Repository) UpdateStockAdjustment(adj *models.StockAdjustment) error {
+	return r.db.Omit("Product").Save(adj).Error
+}
+
+// ApproveStockAdjustment 批准调整 (事务): 更新库存、写入记录、回填调整单
+func (r *Repository) ApproveStockAdjustment(adj *models.StockAdjustment, record *models.InventoryRecord) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		if err := applyStockRecord(tx, record); err != nil {
+			return err
+		}
+		adj.RecordID = &record.ID
+		return tx.Omit("Product").Save(adj).Error
+	})
+}
+
+// CountPendingVoidAdjustments 统计某调整记录待审批的冲销申请数
+func (r *Repository) CountPendingVoidAdjustments(recordID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.StockAdjustment{}).
+		Where("void_record_id = ? AND status = ?", recordID, models.AdjustmentPending).
+		Count(&count).Error
+	return count, err
+}
+
+// ApproveVoidAdjustment 批准冲销申请 (事务): 写入冲销记录、标记原记录、回填调整单
+func (r *Repository) ApproveVoidAdjustment(adj *models.StockAdjustment, original, reversal *models.InventoryRecord) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		if err := voidRecord(tx, original, reversal); err != nil {
+			return err
+		}
+		adj.RecordID = &reversal.ID
+		return tx.Omit("Product").Save(adj).Error
+	})
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"time"
+
+	"go-cargo/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ==================== ABC/XYZ 分类 ====================
+
+// GetConsumptionByProduct 汇总各商品出库消耗 (与 GetDailyOutbound 口径一致, 含组装消耗的组件)
+// 消耗金额优先使用记录上的单位成本, 未记录成本时使用商品成本价
+func (r *Repository) GetConsumptionByProduct(since time.Time) ([]models.ProductConsumption, error) {
+	var rows []models.ProductConsumption
+	err := r.db.Model(&models.InventoryRecord{}).
+		Select(`inventory_records.product_id,
+			SUM(inventory_records.quantity) AS quantity,
+			SUM(inventory_records.quantity * CASE WHEN inventory_records.unit_cost > 0
+				THEN inventory_records.unit_cost ELSE products.cost_price END) AS value`).
+		Joins("JOIN products ON products.id = inventory_records.product_id").
+		Scopes(outboundRecords).
+		Where("inventory_records.created_at >= ?", since).
+		Group("inventory_records.product_id").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// UpdateProductClasses 批量写入商品分类 (事务)
+func (r *Repository) UpdateProductClasses(items []models.ProductClassification, at time.Time) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			if err := tx.Model(&models.Product{}).Where("id = ?", item.ProductID).
+				UpdateColumns(map[string]interface{}{
+					"abc_class":     item.ABCClass,
+					"xyz_class":     item.XYZClass,
+					"classified_at": at,
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"time"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 幂等键 ====================
+
+// GetIdempotencyKey 根据用户和键查找幂等记录
+func (r *Repository) GetIdempotencyKey(userID uint, key string) (*models.IdempotencyKey, error) {
+	var rec models.IdempotencyKey
+	err := r.db.Where("user_id = ? AND key = ?", userID, key).First(&rec).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// CreateIdempotencyKey 创建幂等记录 (唯一索引冲突时返回错误)
+func (r *Repository) CreateIdempotencyKey(rec *models.IdempotencyKey) error {
+	return r.db.Create(rec).Error
+}
+
+// UpdateIdempotencyKey 更新幂等记录
+func (r *Repository) UpdateIdempotencyKey(rec *models.IdempotencyKey) error {
+	return r.db.Save(rec).Error
+}
+
+// DeleteIdempotencyKey 删除幂等记录
+func (r *Repository) DeleteIdempotencyKey(id uint) error {
+	return r.db.Delete(&models.IdempotencyKey{}, id).Error
+}
+
+// PurgeExpiredIdempotencyKeys 清理过期的幂等记录
+func (r *Repository) PurgeExpiredIdempotencyKeys(now time.Time) error {
+	return r.db.Where("expires_at < ?", now).Delete(&models.IdempotencyKey{}).Error
+}
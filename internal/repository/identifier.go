@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"go-cargo/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 商品标识 ====================
+
+// ListProductIdentifiers 获取商品的全部标识
+func (r *Repository) ListProductIdentifiers(productID uint) ([]models.ProductIdentifier, error) {
+	var identifiers []models.ProductIdentifier
+	err := r.db.Preload("Supplier").Where("product_id = ?", productID).
+		Order("type ASC, id ASC").Find(&identifiers).Error
+	return identifiers, err
+}
+
+// GetProductIdentifier 获取商品下的单个标识
+func (r *Repository) GetProductIdentifier(productID, id uint) (*models.ProductIdentifier, error) {
+	var identifier models.ProductIdentifier
+	err := r.db.Where("product_id = ?", productID).First(&identifier, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identifier, nil
+}
+
+// GetIdentifierByUniqueKey 按唯一键查找标识
+func (r *Repository) GetIdentifierByUniqueKey(key string) (*models.ProductIdentifier, error) {
+	var identifier models.ProductIdentifier
+	err := r.db.Where("unique_key = ?", key).First(&identifier).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identifier, nil
+}
+
+// FindIdentifiers 按类型与值精确查找标识 (含商品及供应商)
+func (r *Repository) FindIdentifiers(identifierType string, values []string) ([]models.ProductIdentifier, error) {
+	var identifiers []models.ProductIdentifier
+	err := r.db.Preload("Supplier").Preload("Product.Category").Preload("Product.Supplier").
+		Joins("JOIN products ON products.id = product_identifiers.product_id AND products.deleted_at IS NULL").
+		Where("product_identifiers.type = ? AND product_identifiers.value IN ?", identifierType, values).
+		Order("product_identifiers.id ASC").Find(&identifiers).Error
+	return identifiers, err
+}
+
+// CountProductsWithBarcode 统计使用该主条码的其他商品数量
+func (r *Repository) CountProductsWithBarcode(barcode string, excludeProductID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Product{}).
+		Where("barcode = ? AND id <> ?", barcode, excludeProductID).Count(&count).Error
+	return count, err
+}
+
+// CreateProductIdentifier 创建商品标识
+func (r *Repository) CreateProductIdentifier(identifier *models.ProductIdentifier) error {
+	return r.db.Create(identifier).Error
+}
+
+// UpdateProductIdentifier 更新商品标识
+func (r *Repository) UpdateProductIdentifier(identifier *models.ProductIdentifier) error {
+	return r.db.Save(identifier).Error
+}
+
+// DeleteProductIdentifier 删除商品标识
+func (r *Repository) DeleteProductIdentifier(id uint) error {
+	return r.db.Delete(&models.ProductIdentifier{}, id).Error
+}
+
+// identifierSubquery 按值模糊匹配标识的商品ID子查询
+func (r *Repository) identifierSubquery(keyword string) *gorm.DB {
+	return r.db.Model(&models.ProductIdentifier{}).Select("product_id").
+		Where("value LIKE ?", "%"+keyword+"%")
+}
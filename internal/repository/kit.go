@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"math"
+
+	"go-cargo/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 组合商品 ====================
+
+// ListKitComponents 获取组合商品的物料清单 (含组件商品)
+func (r *Repository) ListKitComponents(kitID uint) ([]models.KitComponent, error) {
+	var components []models.KitComponent
+	err := r.db.Preload("Component").Where("kit_id = ?", kitID).Order("id ASC").Find(&components).Error
+	return components, err
+}
+
+// SaveKitBOM 整体替换组合商品的物料清单并更新组合类型
+func (r *Repository) SaveKitBOM(kit *models.Product, components []models.KitComponent) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("kit_id = ?", kit.ID).Delete(&models.KitComponent{}).Error; err != nil {
+			return err
+		}
+		if len(components) > 0 {
+			if err := tx.Omit("Component").Create(&components).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Product{}).Where("id = ?", kit.ID).Update("kit_type", kit.KitType).Error
+	})
+}
+
+// CountKitsUsingComponent 统计使用某商品作为组件的组合商品数
+func (r *Repository) CountKitsUsingComponent(componentID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.KitComponent{}).
+		Joins("JOIN products ON products.id = kit_components.kit_id AND products.deleted_at IS NULL").
+		Where("kit_components.component_id = ?", componentID).
+		Count(&count).Error
+	return count, err
+}
+
+// CreateKitAssembly 组装/拆卸 (事务): 创建组装单, 按 BeforeQty 乐观更新各商品库存并写入关联记录
+func (r *Repository) CreateKitAssembly(assembly *models.KitAssembly, records []*models.InventoryRecord) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Kit").Create(assembly).Error; err != nil {
+			return err
+		}
+		for _, record := range records {
+			record.SourceType = models.SourceKitAssembly
+			record.SourceID = assembly.ID
+			if err := applyStockRecord(tx, record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListKitAssemblies 获取组装单列表
+func (r *Repository) ListKitAssemblies(query *models.PaginationQuery, kitID *uint, assemblyType string) ([]models.KitAssembly, int64, error) {
+	var assemblies []models.KitAssembly
+	var total int64
+
+	db := r.db.Model(&models.KitAssembly{})
+	if kitID != nil {
+		db = db.Where("kit_id = ?", *kitID)
+	}
+	if assemblyType != "" {
+		db = db.Where("type = ?", assemblyType)
+	}
+	if query.Keyword != "" {
+		db = db.Where("assembly_no LIKE ? OR notes LIKE ?", "%"+query.Keyword+"%", "%"+query.Keyword+"%")
+	}
+
+	db.Count(&total)
+	err := db.Preload("Kit").
+		Order("id DESC").
+		Offset(query.GetOffset()).
+		Limit(query.PageSize).
+		Find(&assemblies).Error
+	return assemblies, total, err
+}
+
+// GetKitAssemblyByID 获取组装单及其库存记录
+func (r *Repository) GetKitAssemblyByID(id uint) (*models.KitAssembly, error) {
+	var assembly models.KitAssembly
+	if err := r.db.Preload("Kit").First(&assembly, id).Error; err != nil {
+		return nil, err
+	}
+	err := r.db.Preload("Product").
+		Where("source_type = ? AND source_id = ?", models.SourceKitAssembly, id).
+		Order("id ASC").
+		Find(&assembly.Records).Error
+	if err != nil {
+		return nil, err
+	}
+	return &assembly, nil
+}
+
+// fillKitAvailability 为列表中的虚拟组合商品按组件库存推算可用量
+func (r *Repository) fillKitAvailability(products []models.Product) error {
+	var ids []uint
+	for i := range products {
+		if products[i].KitType == models.KitTypeVirtual {
+			ids = append(ids, products[i].ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var rows []struct {
+		KitID        uint
+		Quantity     float64
+		CurrentStock float64
+	}
+	err := r.db.Model(&models.KitComponent{}).
+		Select("kit_components.kit_id, kit_components.quantity, products.current_stock").
+		Joins("JOIN products ON products.id = kit_components.component_id").
+		Where("kit_components.kit_id IN ?", ids).
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+	available := make(map[uint]float64, len(ids))
+	for _, row := range rows {
+		qty := row.CurrentStock / row.Quantity
+		if cur, ok := available[row.KitID]; !ok || qty < cur {
+			available[row.KitID] = qty
+		}
+	}
+	for i := range products {
+		if qty, ok := available[products[i].ID]; ok {
+			qty = models.FloorQty(math.Max(qty, 0), products[i].QtyPrecision)
+			products[i].KitAvailable = &qty
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"time"
+
+	"go-cargo/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 库存台账 ====================
+
+// GetProductRecordsBetween 按时间顺序查询商品在 [from, to) 内的全部记录 (含已冲销记录)
+func (r *Repository) GetProductRecordsBetween(productID uint, from, to time.Time) ([]models.InventoryRecord, error) {
+	var records []models.InventoryRecord
+	err := r.db.Where("product_id = ? AND created_at >= ? AND created_at < ?", productID, from, to).
+		Order("created_at ASC, id ASC").
+		Find(&records).Error
+	return records, err
+}
+
+// GetBalancesAsOf 由记录链推算各商品在某时间点的库存
+// 取该时间之前最后一条记录的操作后数量; 若之前无记录, 取之后第一条记录的操作前数量
+// productID 为 0 时统计全部商品; 返回值不包含该时间前后均无记录的商品
+func (r *Repository) GetBalancesAsOf(at time.Time, productID uint) (map[uint]float64, error) {
+	type balance struct {
+		ProductID uint
+		Qty       float64
+	}
+	edge := func(agg, cond string) *gorm.DB {
+		q := r.db.Model(&models.InventoryRecord{}).Select(agg).Where(cond, at)
+		if productID > 0 {
+			q = q.Where("product_id = ?", productID)
+		}
+		return q.Group("product_id")
+	}
+
+	var after, before []balance
+	err := r.db.Model(&models.InventoryRecord{}).
+		Select("product_id, before_qty AS qty").
+		Where("id IN (?)", edge("MIN(id)", "created_at >= ?")).
+		Scan(&after).Error
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.Model(&models.InventoryRecord{}).
+		Select("product_id, after_qty AS qty").
+		Where("id IN (?)", edge("MAX(id)", "created_at < ?")).
+		Scan(&before).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]float64, len(after)+len(before))
+	for _, b := range after {
+		result[b.ProductID] = b.Qty
+	}
+	// 时点之前的记录优先
+	for _, b := range before {
+		result[b.ProductID] = b.Qty
+	}
+	return result, nil
+}
+
+// WalkInventoryRecords 按商品、ID 顺序分批遍历全部记录
+func (r *Repository) WalkInventoryRecords(fn func(records []models.InventoryRecord) error) error {
+	var batch []models.InventoryRecord
+	var lastProductID, lastID uint
+	for {
+		batch = batch[:0]
+		err := r.db.Select("id, product_id, type, quantity, before_qty, after_qty, created_at").
+			Where("product_id > ? OR (product_id = ? AND id > ?)", lastProductID, lastProductID, lastID).
+			Order("product_id ASC, id ASC").
+			Limit(1000).
+			Find(&batch).Error
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		last := batch[len(batch)-1]
+		lastProductID, lastID = last.ProductID, last.ID
+	}
+}
+
+// GetProductsExistingAt 查询某时间点已创建且未删除的商品 (含已停用) 及分类
+func (r *Repository) GetProductsExistingAt(at time.Time) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Unscoped().Preload("Category").
+		Where("created_at < ? AND (deleted_at IS NULL OR deleted_at >= ?)", at, at).
+		Order("id ASC").
+		Find(&products).Error
+	return products, err
+}
@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"go-cargo/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ==================== 库位 ====================
+
+// ListLocations 获取库位列表 (按完整编码排序), 货位附带已用量
+func (r *Repository) ListLocations(query *models.PaginationQuery, filter *models.LocationQuery) ([]models.Location, int64, error) {
+	var locations []models.Location
+	var total int64
+
+	db := r.db.Model(&models.Location{})
+	if filter.ParentID != nil {
+		db = db.Where("parent_id = ?", *filter.ParentID)
+	}
+	if filter.Level != "" {
+		db = db.Where("level = ?", filter.Level)
+	}
+	if filter.Type != "" {
+		db = db.Where("type = ?", filter.Type)
+	}
+	if filter.Prefix != "" {
+		db = db.Where(pathPrefix("path", filter.Prefix))
+	}
+	if query.Keyword != "" {
+		db = db.Where("path LIKE ? OR name LIKE ?", "%"+query.Keyword+"%", "%"+query.Keyword+"%")
+	}
+	if query.Status != nil {
+		db = db.Where("status = ?", *query.Status)
+	}
+
+	db.Count(&total)
+	err := db.Order("path ASC").
+		Offset(query.GetOffset()).
+		Limit(query.PageSize).
+		Find(&locations).Error
+	if err != nil {
+		return locations, total, err
+	}
+	return locations, total, r.fillLocationUsed(locations)
+}
+
+// GetLocationByID 根据ID查找库位 (货位附带已用量)
+func (r *Repository) GetLocationByID(id uint) (*models.Location, error) {
+	locations := make([]models.Location, 1)
+	if err := r.db.First(&locations[0], id).Error; err != nil {
+		return nil, err
+	}
+	if err := r.fillLocationUsed(locations); err != nil {
+		return nil, err
+	}
+	return &locations[0], nil
+}
+
+// GetLocationByPath 根据完整编码查找库位
+func (r *Repository) GetLocationByPath(path string) (*models.Location, error) {
+	var location models.Location
+	if err := r.db.Where("path = ?", path).First(&location).Error; err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// CountChildLocations 统计子库位数
+func (r *Repository) CountChildLocations(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Location{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// CreateLocation 创建库位
+func (r *Repository) CreateLocation(location *models.Location) error {
+	return r.db.Omit("Used").Create(location).Error
+}
+
+// UpdateLocation 更新库位; 完整编码变更时同步更新全部下级库位的编码
+func (r *Repository) UpdateLocation(location *models.Location, oldPath string) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(location).Error; err != nil {
+			return err
+		}
+		if location.Path == oldPath {
+			return nil
+		}
+		return tx.Model(&models.Location{}).
+			Where(pathPrefix("path", oldPath+"-")).
+			Update("path", gorm.Expr("? || SUBSTR(path, ?)", location.Path, utf8.RuneCountInString(oldPath)+1)).Error
+	})
+}
+
+// DeleteLocation 删除库位
+func (r *Repository) DeleteLocation(id uint) error {
+	return r.db.Delete(&models.Location{}, id).Error
+}
+
+// fillLocationUsed 为货位填充已用量 (各商品库存之和)
+func (r *Repository) fillLocationUsed(locations []models.Location) error {
+	var ids []uint
+	for i := range locations {
+		if locations[i].Level == models.LocationBin {
+			ids = append(ids, locations[i].ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	used, err := r.GetLocationUsed(ids)
+	if err != nil {
+		return err
+	}
+	for i := range locations {
+		if locations[i].Level == models.LocationBin {
+			qty := used[locations[i].ID]
+			locations[i].Used = &qty
+		}
+	}
+	return nil
+}
+
+// GetLocationUsed 汇总货位已用量
+func (r *Repository) GetLocationUsed(ids []uint) (map[uint]float64, error) {
+	var rows []struct {
+		LocationID uint
+		Quantity   float64
+	}
+	err := r.db.Model(&models.BinStock{}).
+		Select("location_id, SUM(quantity) AS quantity").
+		Where("location_id IN ?", ids).
+		Group("location_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		result[row.LocationID] = models.RoundQty(row.Quantity, models.MaxQtyPrecision)
+	}
+	return result, nil
+}
+
+// ==================== 货位库存 ====================
+
+// ListProductBinStocks 获取商品在各货位的库存 (按完整编码排序)
+func (r *Repository) ListProductBinStocks(productID uint) ([]models.BinStock, error) {
+	var stocks []models.BinStock
+	err := r.db.Preload("Location").
+		Joins("JOIN locations ON locations.id = bin_stocks.location_id").
+		Where("bin_stocks.product_id = ?", productID).
+		Order("locations.path ASC").
+		Find(&stocks).Error
+	return stocks, err
+}
+
+// ListLocationBinStocks 获取库位及其全部下级货位中的库存 (按完整编码、商品排序)
+func (r *Repository) ListLocationBinStocks(path string) ([]models.BinStock, error) {
+	var stocks []models.BinStock
+	err := r.db.Preload("Location").Preload("Product").
+		Joins("JOIN locations ON locations.id = bin_stocks.location_id").
+		Where("locations.path = ? OR ?", path, pathPrefix("locations.path", path+"-")).
+		Order("locations.path ASC, bin_stocks.product_id ASC").
+		Find(&stocks).Error
+	return stocks, err
+}
+
+// GetBinStockQty 获取商品在某货位的库存
+func (r *Repository) GetBinStockQty(locationID, productID uint) (float64, error) {
+	var qty float64
+	err := r.db.Model(&models.BinStock{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("location_id = ? AND product_id = ?", locationID, productID).
+		Scan(&qty).Error
+	return models.RoundQty(qty, models.MaxQtyPrecision), err
+}
+
+// CountLocationStock 统计库位及其下级货位中的库存行数
+func (r *Repository) CountLocationStock(path string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.BinStock{}).
+		Joins("JOIN locations ON locations.id = bin_stocks.location_id").
+		Where("locations.path = ? OR ?", path, pathPrefix("locations.path", path+"-")).
+		Count(&count).Error
+	return count, err
+}
+
+// GetBinnedQty 获取商品在各货位的库存合计
+func (r *Repository) GetBinnedQty(productID uint) (float64, error) {
+	var qty float64
+	err := r.db.Model(&models.BinStock{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("product_id = ?", productID).
+		Scan(&qty).Error
+	return models.RoundQty(qty, models.MaxQtyPrecision), err
+}
+
+// GetEmptyBins 获取可容纳 quantity 的空货位 (启用且非隔离位), 拣货位在前, 同类型按完整编码排序
+func (r *Repository) GetEmptyBins(quantity float64, limit int) ([]models.Location, error) {
+	var bins []models.Location
+	err := r.db.Where("level = ? AND status = 1 AND type <> ?", models.LocationBin, models.LocationTypeQuarantine).
+		Where("capacity = 0 OR capacity >= ?", quantity).
+		Where("id NOT IN (?)", r.db.Model(&models.BinStock{}).Select("location_id")).
+		Order(fmt.Sprintf("CASE type WHEN '%s' THEN 0 ELSE 1 END, path ASC", models.LocationTypePick)).
+		Limit(limit).
+		Find(&bins).Error
+	return bins, err
+}
+
+// MoveStock 货位间移库: 只调整货位库存并写入记录, 不改动商品总库存
+// 记录的前后数量取事务内读取的当前库存
+func (r *Repository) MoveStock(record *models.InventoryRecord) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		var product models.Product
+		if err := tx.Select("id", "current_stock").First(&product, record.ProductID).Error; err != nil {
+			return err
+		}
+		record.BeforeQty, record.AfterQty = product.CurrentStock, product.CurrentStock
+		if err := applyBinStock(tx, record); err != nil {
+			return err
+		}
+		return tx.Create(record).Error
+	})
+}
+
+// applyBinStock 按库存记录在事务内更新货位库存
+//   - 移库: 来源货位减少、目标货位增加, 为空的一侧为未分配库存
+//   - 指定货位: 按记录的数量变化增减该货位
+//   - 未指定货位的减少: 依次扣减未分配、拣货位、存储位库存, 隔离位不自动扣减
+//   - 未指定货位的增加: 计入未分配库存
+func applyBinStock(tx *gorm.DB, record *models.InventoryRecord) error {
+	if record.Type == models.StockMove {
+		if record.LocationID == nil {
+			if err := checkUnallocated(tx, record.ProductID, record.BeforeQty, record.Quantity); err != nil {
+				return err
+			}
+		} else if err := addBinStock(tx, *record.LocationID, record.ProductID, -record.Quantity); err != nil {
+			return err
+		}
+		if record.ToLocationID != nil {
+			return addBinStock(tx, *record.ToLocationID, record.ProductID, record.Quantity)
+		}
+		return nil
+	}
+
+	delta := models.RoundQty(record.AfterQty-record.BeforeQty, models.MaxQtyPrecision)
+	if record.LocationID != nil {
+		return addBinStock(tx, *record.LocationID, record.ProductID, delta)
+	}
+	if delta >= 0 {
+		return nil
+	}
+
+	var bins []models.BinStock
+	err := tx.Joins("JOIN locations ON locations.id = bin_stocks.location_id").
+		Where("bin_stocks.product_id = ?", record.ProductID).
+		Order("locations.path ASC").
+		Find(&bins).Error
+	if err != nil {
+		return err
+	}
+	var binned float64
+	for _, b := range bins {
+		binned += b.Quantity
+	}
+	need := models.RoundQty(-delta-(record.BeforeQty-binned), models.MaxQtyPrecision)
+	if need <= 0 {
+		return nil
+	}
+
+	// 拣货位优先, 其次存储位
+	for _, locType := range []string{models.LocationTypePick, models.LocationTypeBulk} {
+		for _, b := range bins {
+			if need <= 0 {
+				return nil
+			}
+			var loc models.Location
+			if err := tx.First(&loc, b.LocationID).Error; err != nil {
+				return err
+			}
+			if loc.Type != locType {
+				continue
+			}
+			take := b.Quantity
+			if take > need {
+				take = need
+			}
+			if err := addBinStock(tx, b.LocationID, record.ProductID, -take); err != nil {
+				return err
+			}
+			need = models.RoundQty(need-take, models.MaxQtyPrecision)
+		}
+	}
+	if need > 0 {
+		return fmt.Errorf("商品 %d 可自动扣减的库存不足 (隔离位库存需指定货位出库)", record.ProductID)
+	}
+	return nil
+}
+
+// pathPrefix 匹配以 prefix 开头的库位编码; 按字符截取比较, 编码中的 % 与 _ 不会被当作 LIKE 通配符
+func pathPrefix(column, prefix string) clause.Expr {
+	return gorm.Expr("SUBSTR("+column+", 1, ?) = ?", utf8.RuneCountInString(prefix), prefix)
+}
+
+// checkUnallocated 校验商品未分配库位的库存不少于 quantity
+func checkUnallocated(tx *gorm.DB, productID uint, currentStock, quantity float64) error {
+	var binned float64
+	if err := tx.Model(&models.BinStock{}).Select("COALESCE(SUM(quantity), 0)").
+		Where("product_id = ?", productID).Scan(&binned).Error; err != nil {
+		return err
+	}
+	if models.RoundQty(currentStock-binned-quantity, models.MaxQtyPrecision) < 0 {
+		return fmt.Errorf("商品 %d 未分配库位的库存不足", productID)
+	}
+	return nil
+}
+
+// addBinStock 增减商品在货位的库存, 减至零时删除该行
+func addBinStock(tx *gorm.DB, locationID, productID uint, delta float64) error {
+	var stock models.BinStock
+	err := tx.Where("location_id = ? AND product_id = ?", locationID, productID).First(&stock).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	qty := models.RoundQty(stock.Quantity+delta, models.MaxQtyPrecision)
+	if qty < 0 {
+		return fmt.Errorf("货位 %d 中商品 %d 的库存不足", locationID, productID)
+	}
+	if stock.ID == 0 {
+		if qty == 0 {
+			return nil
+		}
+		return tx.Create(&models.BinStock{LocationID: locationID, ProductID: productID, Quantity: qty}).Error
+	}
+	if qty == 0 {
+		return tx.Delete(&stock).Error
+	}
+	return tx.Model(&stock).Update("quantity", qty).Error
+}
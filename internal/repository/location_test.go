@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"go-cargo/internal/models"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestRepository 基于内存 SQLite 创建仓储
+func newTestRepository(t *testing.T) *Repository {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("打开数据库失败: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1) // 内存库每个连接独立, 固定单连接
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.AutoMigrate(&models.Category{}, &models.Supplier{}, &models.Product{}, &models.Location{}, &models.BinStock{}); err != nil {
+		t.Fatalf("迁移失败: %v", err)
+	}
+	return New(db)
+}
+
+// createLocationTree 按完整编码创建库位, 上级库位需先于下级创建
+func createLocationTree(t *testing.T, r *Repository, paths ...string) map[string]*models.Location {
+	t.Helper()
+	created := make(map[string]*models.Location, len(paths))
+	for _, path := range paths {
+		loc := &models.Location{Path: path, Code: path[strings.LastIndex(path, "-")+1:], Level: models.LocationWarehouse}
+		if i := strings.LastIndex(path, "-"); i >= 0 {
+			loc.ParentID = &created[path[:i]].ID
+			loc.Level = models.LocationBin
+		}
+		if err := r.CreateLocation(loc); err != nil {
+			t.Fatalf("创建库位 %s 失败: %v", path, err)
+		}
+		created[path] = loc
+	}
+	return created
+}
+
+func TestLocationPrefixIsLiteral(t *testing.T) {
+	r := newTestRepository(t)
+	createLocationTree(t, r, "A_", "A_-01", "AB", "AB-01", "A%", "A%-01")
+	for i, loc := range []string{"A_-01", "AB-01", "A%-01"} {
+		p := &models.Product{SKU: "P" + string(rune('1'+i)), Name: loc, Unit: "个", Status: 1, Location: loc}
+		if err := r.db.Create(p).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	locations, _, err := r.ListLocations(&models.PaginationQuery{Page: 1, PageSize: 50}, &models.LocationQuery{Prefix: "A_"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, l := range locations {
+		paths = append(paths, l.Path)
+	}
+	if got := strings.Join(paths, ","); got != "A_,A_-01" {
+		t.Errorf("ListLocations(prefix=A_) = %s, 期望 A_,A_-01", got)
+	}
+
+	used, err := r.GetProductLocations("A_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(used, ","); got != "A_-01" {
+		t.Errorf("GetProductLocations(A_) = %s, 期望 A_-01", got)
+	}
+
+	products, err := r.GetProductsForStocktakeScope(models.StocktakeScopeLocation, nil, "A%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(products) != 1 || products[0].Location != "A%-01" {
+		t.Errorf("盘点范围 A%% 应只包含 A%%-01, 实际 %d 个商品", len(products))
+	}
+}
+
+func TestUpdateLocationRenamesOnlyDescendants(t *testing.T) {
+	r := newTestRepository(t)
+	locs := createLocationTree(t, r, "W_", "W_-01", "WX", "WX-01")
+
+	w := locs["W_"]
+	w.Code, w.Path = "W9", "W9"
+	if err := r.UpdateLocation(w, "W_"); err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	if err := r.db.Model(&models.Location{}).Order("path").Pluck("path", &paths).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(paths, ","); got != "W9,W9-01,WX,WX-01" {
+		t.Errorf("重命名后编码 = %s, 期望 W9,W9-01,WX,WX-01", got)
+	}
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"fmt"
+
+	"go-cargo/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 拣货单 ====================
+
+// GetPickReservations 汇总待拣货拣货单对商品各货位的占用数量, 键 0 为未分配库位
+func (r *Repository) GetPickReservations(productID uint) (map[uint]float64, error) {
+	var rows []struct {
+		LocationID uint
+		Quantity   float64
+	}
+	err := r.db.Model(&models.PickListLine{}).
+		Select("COALESCE(pick_list_lines.location_id, 0) AS location_id, SUM(pick_list_lines.quantity) AS quantity").
+		Joins("JOIN pick_lists ON pick_lists.id = pick_list_lines.pick_list_id AND pick_lists.deleted_at IS NULL").
+		Where("pick_list_lines.product_id = ? AND pick_lists.status = ?", productID, models.PickListOpen).
+		Group("COALESCE(pick_list_lines.location_id, 0)").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		result[row.LocationID] = models.RoundQty(row.Quantity, models.MaxQtyPrecision)
+	}
+	return result, nil
+}
+
+// CreatePickList 创建拣货单 (含拣货明细与批次明细)
+func (r *Repository) CreatePickList(list *models.PickList) error {
+	return r.db.Create(list).Error
+}
+
+// ListPickLists 获取拣货单列表
+func (r *Repository) ListPickLists(query *models.PaginationQuery, status string) ([]models.PickList, int64, error) {
+	var lists []models.PickList
+	var total int64
+
+	db := r.db.Model(&models.PickList{})
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	if query.Keyword != "" {
+		db = db.Where("pick_no LIKE ? OR notes LIKE ?", "%"+query.Keyword+"%", "%"+query.Keyword+"%")
+	}
+
+	db.Count(&total)
+	err := db.Order("id DESC").
+		Offset(query.GetOffset()).
+		Limit(query.PageSize).
+		Find(&lists).Error
+	return lists, total, err
+}
+
+// GetPickListByID 根据ID查找拣货单 (拣货明细按拣货顺序, 含商品)
+func (r *Repository) GetPickListByID(id uint) (*models.PickList, error) {
+	var list models.PickList
+	err := r.db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sequence ASC")
+	}).Preload("Lines.Product").Preload("Orders", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Preload("Orders.Product").First(&list, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// UpdatePickList 更新拣货单
+func (r *Repository) UpdatePickList(list *models.PickList) error {
+	return r.db.Omit("Lines", "Orders").Save(list).Error
+}
+
+// ConfirmPickList 拣货确认 (事务): 写入出库记录、回填实拣与短拣数量、更新拣货单状态
+// 仅待拣货的拣货单可确认, 已被其他请求确认或取消时整体回滚
+func (r *Repository) ConfirmPickList(list *models.PickList, records []*models.InventoryRecord) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.PickList{}).
+			Where("id = ? AND status = ?", list.ID, models.PickListOpen).
+			Updates(map[string]interface{}{
+				"status":            list.Status,
+				"confirmed_by":      list.ConfirmedBy,
+				"confirmed_by_name": list.ConfirmedByName,
+				"confirmed_at":      list.ConfirmedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("拣货单已被确认或取消")
+		}
+		for _, record := range records {
+			record.SourceType = models.SourcePickList
+			record.SourceID = list.ID
+			if err := applyStockRecord(tx, record); err != nil {
+				return err
+			}
+		}
+		for i := range list.Lines {
+			if err := tx.Omit("Product").Save(&list.Lines[i]).Error; err != nil {
+				return err
+			}
+		}
+		for i := range list.Orders {
+			if err := tx.Omit("Product").Save(&list.Orders[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListPickListRecords 获取拣货单确认时写入的出库记录
+func (r *Repository) ListPickListRecords(id uint) ([]models.InventoryRecord, error) {
+	var records []models.InventoryRecord
+	err := r.db.Preload("Product").
+		Where("source_type = ? AND source_id = ?", models.SourcePickList, id).
+		Order("id ASC").
+		Find(&records).Error
+	return records, err
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"go-cargo/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 补货与采购 ====================
+
+// outboundRecords 出库消耗的库存记录: 出库及组装消耗的组件, 不含已冲销记录
+// 补货、预测与 ABC/XYZ 分类共用同一口径
+func outboundRecords(db *gorm.DB) *gorm.DB {
+	return db.Where(`(inventory_records.type = ? OR (inventory_records.type = ? AND inventory_records.after_qty < inventory_records.before_qty))
+		AND inventory_records.voided = ?`, models.StockOut, models.StockAssemble, false)
+}
+
+// GetDailyOutbound 按商品、日期汇总出库数量 (不含已冲销记录), productID 为 0 时统计全部商品
+// 组装消耗的组件数量同样计入, 以便为组件补货
+func (r *Repository) GetDailyOutbound(since time.Time, productID uint) ([]models.DailyQuantity, error) {
+	var rows []models.DailyQuantity
+	db := r.db.Model(&models.InventoryRecord{}).
+		Select("product_id, DATE(created_at) AS date, SUM(quantity) AS quantity").
+		Scopes(outboundRecords).
+		Where("created_at >= ?", since)
+	if productID > 0 {
+		db = db.Where("product_id = ?", productID)
+	}
+	err := db.Group("product_id, DATE(created_at)").
+		Order("product_id, date").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// GetActiveProductsWithSupplier 获取所有启用商品 (含分类、供应商)
+func (r *Repository) GetActiveProductsWithSupplier() ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Scopes(stockableProducts).Where("status = 1").Preload("Category").Preload("Supplier").Order("id ASC").Find(&products).Error
+	return products, err
+}
+
+// GetOnOrderQuantities 汇总草稿采购单中各商品的在途数量
+func (r *Repository) GetOnOrderQuantities() (map[uint]float64, error) {
+	var rows []struct {
+		ProductID uint
+		Quantity  float64
+	}
+	err := r.db.Model(&models.PurchaseOrderItem{}).
+		Select("purchase_order_items.product_id, SUM(purchase_order_items.quantity) AS quantity").
+		Joins("JOIN purchase_orders ON purchase_orders.id = purchase_order_items.order_id").
+		Where("purchase_orders.status = ? AND purchase_orders.deleted_at IS NULL", models.PurchaseOrderDraft).
+		Group("purchase_order_items.product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		result[row.ProductID] = row.Quantity
+	}
+	return result, nil
+}
+
+// CreatePurchaseOrders 批量创建采购单 (事务), 单号按当日序号分配: PO-YYYYMMDD-NNNN
+func (r *Repository) CreatePurchaseOrders(orders []*models.PurchaseOrder) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		prefix := "PO-" + time.Now().Format("20060102") + "-"
+		var seq int
+		if err := tx.Unscoped().Model(&models.PurchaseOrder{}).
+			Select("COALESCE(MAX(CAST(SUBSTR(order_no, ?) AS INTEGER)), 0)", len(prefix)+1).
+			Where("order_no LIKE ?", prefix+"%").
+			Scan(&seq).Error; err != nil {
+			return err
+		}
+		for _, order := range orders {
+			seq++
+			order.OrderNo = fmt.Sprintf("%s%04d", prefix, seq)
+			// 单号冲突重试时重新插入, 清除上次失败写入的主键
+			order.ID = 0
+			for i := range order.Items {
+				order.Items[i].ID = 0
+				order.Items[i].OrderID = 0
+			}
+			if err := tx.Omit("Supplier").Create(order).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListPurchaseOrders 获取采购单列表
+func (r *Repository) ListPurchaseOrders(query *models.PaginationQuery, supplierID *uint, status string) ([]models.PurchaseOrder, int64, error) {
+	var orders []models.PurchaseOrder
+	var total int64
+
+	db := r.db.Model(&models.PurchaseOrder{})
+	if supplierID != nil && *supplierID > 0 {
+		db = db.Where("supplier_id = ?", *supplierID)
+	}
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	if query.Keyword != "" {
+		db = db.Where("order_no LIKE ?", "%"+query.Keyword+"%")
+	}
+
+	db.Count(&total)
+	err := db.Preload("Supplier").
+		Order("id DESC").
+		Offset(query.GetOffset()).
+		Limit(query.PageSize).
+		Find(&orders).Error
+	return orders, total, err
+}
+
+// GetPurchaseOrderByID 根据ID查找采购单 (含明细)
+func (r *Repository) GetPurchaseOrderByID(id uint) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := r.db.Preload("Supplier").Preload("Items").Preload("Items.Product").First(&order, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// UpdatePurchaseOrder 更新采购单
+func (r *Repository) UpdatePurchaseOrder(order *models.PurchaseOrder) error {
+	return r.db.Omit("Supplier", "Items").Save(order).Error
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"time"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 报表 ====================
+
+// GetLastMovements 查询各商品最近一次入库、出库时间 (不含已冲销记录)
+func (r *Repository) GetLastMovements() (map[uint]models.ProductLastMovement, error) {
+	var rows []struct {
+		ProductID uint
+		LastIn    string
+		LastOut   string
+	}
+	err := r.db.Model(&models.InventoryRecord{}).
+		Select(`product_id,
+			COALESCE(MAX(CASE WHEN type = ? THEN created_at END), '') AS last_in,
+			COALESCE(MAX(CASE WHEN type = ? THEN created_at END), '') AS last_out`,
+			models.StockIn, models.StockOut).
+		Where("voided = ?", false).
+		Group("product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]models.ProductLastMovement, len(rows))
+	for _, row := range rows {
+		result[row.ProductID] = models.ProductLastMovement{
+			ProductID: row.ProductID,
+			LastIn:    parseDBTime(row.LastIn),
+			LastOut:   parseDBTime(row.LastOut),
+		}
+	}
+	return result, nil
+}
+
+// dbTimeLayouts SQLite 中时间文本的可能格式
+var dbTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseDBTime 解析聚合查询返回的时间文本, 为空或无法解析时返回 nil
+func parseDBTime(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	for _, layout := range dbTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// GetNetChangeSince 汇总各商品在某时间之后的库存净变化 (after_qty - before_qty)
+// 当前库存减去净变化即为该时间点的库存
+func (r *Repository) GetNetChangeSince(since time.Time) (map[uint]float64, error) {
+	var rows []struct {
+		ProductID uint
+		Change    float64
+	}
+	err := r.db.Model(&models.InventoryRecord{}).
+		Select("product_id, SUM(after_qty - before_qty) AS `change`").
+		Where("created_at >= ?", since).
+		Group("product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		result[row.ProductID] = row.Change
+	}
+	return result, nil
+}
+
+// GetPeriodFlows 汇总各商品期间入库量、出库量及出库成本 (不含已冲销记录)
+func (r *Repository) GetPeriodFlows(from, to time.Time) (map[uint]models.ProductPeriodFlow, error) {
+	var rows []models.ProductPeriodFlow
+	err := r.db.Model(&models.InventoryRecord{}).
+		Select(`inventory_records.product_id,
+			COALESCE(SUM(CASE WHEN inventory_records.type = ? THEN inventory_records.quantity ELSE 0 END), 0) AS received_qty,
+			COALESCE(SUM(CASE WHEN inventory_records.type = ? THEN inventory_records.quantity ELSE 0 END), 0) AS sold_qty,
+			COALESCE(SUM(CASE WHEN inventory_records.type = ? THEN inventory_records.quantity * CASE WHEN inventory_records.unit_cost > 0
+				THEN inventory_records.unit_cost ELSE products.cost_price END ELSE 0 END), 0) AS sold_value`,
+			models.StockIn, models.StockOut, models.StockOut).
+		Joins("JOIN products ON products.id = inventory_records.product_id").
+		Where("inventory_records.voided = ? AND inventory_records.created_at >= ? AND inventory_records.created_at < ?", false, from, to).
+		Group("inventory_records.product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]models.ProductPeriodFlow, len(rows))
+	for _, row := range rows {
+		result[row.ProductID] = row
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"time"
+
+	"go-cargo/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 定时报表 ====================
+
+// ListReportJobs 获取报表任务列表
+func (r *Repository) ListReportJobs(query *models.PaginationQuery) ([]models.ReportJob, int64, error) {
+	var jobs []models.ReportJob
+	var total int64
+
+	db := r.db.Model(&models.ReportJob{})
+	if query.Keyword != "" {
+		db = db.Where("name LIKE ?", "%"+query.Keyword+"%")
+	}
+
+	db.Count(&total)
+	err := db.Order("id DESC").
+		Offset(query.GetOffset()).
+		Limit(query.PageSize).
+		Find(&jobs).Error
+	return jobs, total, err
+}
+
+// GetReportJobByID 根据ID查找报表任务
+func (r *Repository) GetReportJobByID(id uint) (*models.ReportJob, error) {
+	var job models.ReportJob
+	err := r.db.First(&job, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetDueReportJobs 获取已到执行时间的启用任务
+func (r *Repository) GetDueReportJobs(now time.Time) ([]models.ReportJob, error) {
+	var jobs []models.ReportJob
+	err := r.db.Where("enabled = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, now).
+		Order("next_run_at ASC").
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// CreateReportJob 创建报表任务
+func (r *Repository) CreateReportJob(job *models.ReportJob) error {
+	return r.db.Create(job).Error
+}
+
+// UpdateReportJob 更新报表任务
+func (r *Repository) UpdateReportJob(job *models.ReportJob) error {
+	return r.db.Save(job).Error
+}
+
+// DeleteReportJob 删除报表任务
+func (r *Repository) DeleteReportJob(id uint) error {
+	return r.db.Delete(&models.ReportJob{}, id).Error
+}
+
+// CreateReportJobRun 保存执行记录并更新任务的最近执行状态与下次执行时间
+func (r *Repository) CreateReportJobRun(run *models.ReportJobRun, nextRunAt *time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+		updates := map[string]interface{}{
+			"last_run_at": run.StartedAt,
+			"last_status": run.Status,
+		}
+		if nextRunAt != nil {
+			updates["next_run_at"] = *nextRunAt
+		}
+		return tx.Model(&models.ReportJob{}).Where("id = ?", run.JobID).UpdateColumns(updates).Error
+	})
+}
+
+// ListReportJobRuns 获取任务执行记录
+func (r *Repository) ListReportJobRuns(jobID uint, query *models.PaginationQuery) ([]models.ReportJobRun, int64, error) {
+	var runs []models.ReportJobRun
+	var total int64
+
+	db := r.db.Model(&models.ReportJobRun{}).Where("job_id = ?", jobID)
+	db.Count(&total)
+	err := db.Order("id DESC").
+		Offset(query.GetOffset()).
+		Limit(query.PageSize).
+		Find(&runs).Error
+	return runs, total, err
+}
@@ -2,7 +2,9 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"go-cargo/internal/models"
@@ -12,7 +14,8 @@ import (
 
 // Repository 数据仓库，封装所有数据库操作
 type Repository struct {
-	db *gorm.DB
+	db      *gorm.DB
+	onWrite func() // 业务数据写入提交后的回调, 见 OnWrite
 }
 
 // New 创建 Repository 实例
@@ -20,6 +23,17 @@ func New(db *gorm.DB) *Repository {
 	return &Repository{db: db}
 }
 
+// IsDuplicateKey 判断错误是否为唯一索引冲突
+func (r *Repository) IsDuplicateKey(err error) bool {
+	translator, ok := r.db.Dialector.(gorm.ErrorTranslator)
+	for ; err != nil; err = errors.Unwrap(err) {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || (ok && translator.Translate(err) == gorm.ErrDuplicatedKey) {
+			return true
+		}
+	}
+	return false
+}
+
 // ==================== 用户 ====================
 
 // CreateUser 创建用户
@@ -73,10 +87,18 @@ func (r *Repository) ListCategories(query *models.PaginationQuery) ([]models.Cat
 		Offset(query.GetOffset()).
 		Limit(query.PageSize).
 		Find(&categories).Error
+	if err != nil || len(categories) == 0 {
+		return categories, total, err
+	}
 
 	// 填充商品数量
+	ids := make([]uint, len(categories))
+	for i := range categories {
+		ids[i] = categories[i].ID
+	}
+	counts, err := r.countProductsBy("category_id", ids)
 	for i := range categories {
-		r.db.Model(&models.Product{}).Where("category_id = ?", categories[i].ID).Count(&categories[i].ProductCount)
+		categories[i].ProductCount = counts[categories[i].ID]
 	}
 
 	return categories, total, err
@@ -142,15 +164,41 @@ func (r *Repository) ListSuppliers(query *models.PaginationQuery) ([]models.Supp
 		Offset(query.GetOffset()).
 		Limit(query.PageSize).
 		Find(&suppliers).Error
+	if err != nil || len(suppliers) == 0 {
+		return suppliers, total, err
+	}
 
 	// 填充商品数量
+	ids := make([]uint, len(suppliers))
 	for i := range suppliers {
-		r.db.Model(&models.Product{}).Where("supplier_id = ?", suppliers[i].ID).Count(&suppliers[i].ProductCount)
+		ids[i] = suppliers[i].ID
+	}
+	counts, err := r.countProductsBy("supplier_id", ids)
+	for i := range suppliers {
+		suppliers[i].ProductCount = counts[suppliers[i].ID]
 	}
 
 	return suppliers, total, err
 }
 
+// countProductsBy 按 category_id 或 supplier_id 分组统计商品数量
+func (r *Repository) countProductsBy(column string, ids []uint) (map[uint]int64, error) {
+	var rows []struct {
+		ID    uint
+		Count int64
+	}
+	err := r.db.Model(&models.Product{}).
+		Select(column+" AS id, COUNT(*) AS count").
+		Where(column+" IN ?", ids).
+		Group(column).
+		Scan(&rows).Error
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ID] = row.Count
+	}
+	return counts, err
+}
+
 // GetAllSuppliers 获取所有启用的供应商 (用于下拉选择)
 func (r *Repository) GetAllSuppliers() ([]models.Supplier, error) {
 	var suppliers []models.Supplier
@@ -191,44 +239,91 @@ func (r *Repository) DeleteSupplier(id uint) error {
 // ==================== 商品 ====================
 
 // ListProducts 获取商品列表 (支持分页、搜索、筛选)
-func (r *Repository) ListProducts(query *models.PaginationQuery, categoryID, supplierID *uint) ([]models.Product, int64, error) {
+func (r *Repository) ListProducts(query *models.PaginationQuery, filter *models.ProductFilter) ([]models.Product, int64, error) {
 	var products []models.Product
 	var total int64
 
+	db := r.filterProducts(query, filter)
+	db.Count(&total)
+	err := db.Preload("Category").Preload("Supplier").Preload("Attributes").
+		Order("id DESC").
+		Offset(query.GetOffset()).
+		Limit(query.PageSize).
+		Find(&products).Error
+	if err != nil {
+		return products, total, err
+	}
+	if err := r.fillVariantStats(products); err != nil {
+		return products, total, err
+	}
+	return products, total, r.fillKitAvailability(products)
+}
+
+// FindProducts 按列表条件查询全部商品 (不分页, 按 SKU 排序), 最多 limit 条
+func (r *Repository) FindProducts(query *models.PaginationQuery, filter *models.ProductFilter, limit int) ([]models.Product, error) {
+	var products []models.Product
+	err := r.filterProducts(query, filter).
+		Preload("Category").Preload("Supplier").
+		Order("sku ASC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+// filterProducts 构造商品列表筛选条件
+func (r *Repository) filterProducts(query *models.PaginationQuery, filter *models.ProductFilter) *gorm.DB {
 	db := r.db.Model(&models.Product{})
 
 	if query.Keyword != "" {
-		db = db.Where("name LIKE ? OR sku LIKE ? OR barcode LIKE ?",
-			"%"+query.Keyword+"%", "%"+query.Keyword+"%", "%"+query.Keyword+"%")
+		db = db.Where("name LIKE ? OR sku LIKE ? OR barcode LIKE ? OR id IN (?)",
+			"%"+query.Keyword+"%", "%"+query.Keyword+"%", "%"+query.Keyword+"%", r.identifierSubquery(query.Keyword))
 	}
 	if query.Status != nil {
 		db = db.Where("status = ?", *query.Status)
 	}
-	if categoryID != nil && *categoryID > 0 {
-		db = db.Where("category_id = ?", *categoryID)
+	if filter.CategoryID != nil && *filter.CategoryID > 0 {
+		db = db.Where("category_id = ?", *filter.CategoryID)
 	}
-	if supplierID != nil && *supplierID > 0 {
-		db = db.Where("supplier_id = ?", *supplierID)
+	if filter.SupplierID != nil && *filter.SupplierID > 0 {
+		db = db.Where("supplier_id = ?", *filter.SupplierID)
 	}
+	if filter.ABCClass != "" {
+		db = db.Where("abc_class = ?", filter.ABCClass)
+	}
+	if filter.XYZClass != "" {
+		db = db.Where("xyz_class = ?", filter.XYZClass)
+	}
+	switch filter.View {
+	case models.ProductViewGrouped:
+		db = db.Where("parent_id IS NULL")
+	case models.ProductViewFlat:
+		db = db.Where("has_variants = ?", false)
+	}
+	if filter.ParentID != nil && *filter.ParentID > 0 {
+		db = db.Where("parent_id = ?", *filter.ParentID)
+	}
+	return db
+}
 
-	db.Count(&total)
-	err := db.Preload("Category").Preload("Supplier").
-		Order("id DESC").
-		Offset(query.GetOffset()).
-		Limit(query.PageSize).
-		Find(&products).Error
-
-	return products, total, err
+// stockableProducts 仅包含自身持有库存的商品 (排除多规格款式与虚拟组合商品)
+func stockableProducts(db *gorm.DB) *gorm.DB {
+	return db.Where("has_variants = ? AND kit_type <> ?", false, models.KitTypeVirtual)
 }
 
 // GetProductByID 根据ID查找商品 (含关联)
 func (r *Repository) GetProductByID(id uint) (*models.Product, error) {
-	var product models.Product
-	err := r.db.Preload("Category").Preload("Supplier").First(&product, id).Error
+	products := make([]models.Product, 1)
+	err := r.db.Preload("Category").Preload("Supplier").First(&products[0], id).Error
 	if err != nil {
 		return nil, err
 	}
-	return &product, nil
+	if err := r.fillVariantStats(products); err != nil {
+		return nil, err
+	}
+	if err := r.fillKitAvailability(products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
 }
 
 // GetProductBySKU 根据SKU查找商品
@@ -241,6 +336,14 @@ func (r *Repository) GetProductBySKU(sku string) (*models.Product, error) {
 	return &product, nil
 }
 
+// GetProductsByBarcodes 按条码精确查找商品 (含关联)
+func (r *Repository) GetProductsByBarcodes(barcodes []string) ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Preload("Category").Preload("Supplier").
+		Where("barcode IN ?", barcodes).Order("id ASC").Find(&products).Error
+	return products, err
+}
+
 // CreateProduct 创建商品
 func (r *Repository) CreateProduct(product *models.Product) error {
 	return r.db.Create(product).Error
@@ -252,14 +355,23 @@ func (r *Repository) UpdateProduct(product *models.Product) error {
 }
 
 // DeleteProduct 软删除商品
+// 商品的附加标识一并删除, 以便其他商品复用这些条码
 func (r *Repository) DeleteProduct(id uint) error {
-	return r.db.Delete(&models.Product{}, id).Error
+	return r.transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", id).Delete(&models.ProductIdentifier{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", id).Delete(&models.ProductAttribute{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Product{}, id).Error
+	})
 }
 
 // GetLowStockProducts 获取低库存商品
 func (r *Repository) GetLowStockProducts(limit int) ([]models.Product, error) {
 	var products []models.Product
-	err := r.db.Where("current_stock <= min_stock AND min_stock > 0 AND status = 1").
+	err := r.db.Scopes(stockableProducts).Where("current_stock <= min_stock AND min_stock > 0 AND status = 1").
 		Preload("Category").
 		Order("current_stock ASC").
 		Limit(limit).
@@ -275,19 +387,23 @@ func (r *Repository) CreateInventoryRecord(record *models.InventoryRecord) error
 }
 
 // UpdateProductStock 更新商品库存数量
-func (r *Repository) UpdateProductStock(productID uint, newStock int) error {
+func (r *Repository) UpdateProductStock(productID uint, newStock float64) error {
 	return r.db.Model(&models.Product{}).Where("id = ?", productID).
 		Update("current_stock", newStock).Error
 }
 
 // StockOperation 库存操作 (事务)
-func (r *Repository) StockOperation(record *models.InventoryRecord, newStock int) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *Repository) StockOperation(record *models.InventoryRecord, newStock float64) error {
+	return r.transaction(func(tx *gorm.DB) error {
 		// 更新商品库存
 		if err := tx.Model(&models.Product{}).Where("id = ?", record.ProductID).
 			Update("current_stock", newStock).Error; err != nil {
 			return err
 		}
+		// 更新货位库存
+		if err := applyBinStock(tx, record); err != nil {
+			return err
+		}
 		// 创建操作记录
 		if err := tx.Create(record).Error; err != nil {
 			return err
@@ -296,8 +412,44 @@ func (r *Repository) StockOperation(record *models.InventoryRecord, newStock int
 	})
 }
 
+// BatchStockOperation 批量库存操作 (单一事务, 全部成功或全部回滚)
+// 每行按 BeforeQty 做乐观校验, 库存在校验后被其他操作修改时整批回滚; adjustments 为同批提交的待审批调整单
+func (r *Repository) BatchStockOperation(records []*models.InventoryRecord, adjustments []*models.StockAdjustment) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		for _, record := range records {
+			if err := applyStockRecord(tx, record); err != nil {
+				return err
+			}
+		}
+		for _, adj := range adjustments {
+			if err := tx.Omit("Product").Create(adj).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// applyStockRecord 在事务内按 BeforeQty 乐观更新库存、同步货位库存并写入记录
+func applyStockRecord(tx *gorm.DB, record *models.InventoryRecord) error {
+	result := tx.Model(&models.Product{}).
+		Where("id = ? AND current_stock = ?", record.ProductID, record.BeforeQty).
+		Update("current_stock", record.AfterQty)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("商品 %d 库存已被其他操作修改，请重试", record.ProductID)
+	}
+	if err := applyBinStock(tx, record); err != nil {
+		return err
+	}
+	return tx.Create(record).Error
+}
+
 // ListInventoryRecords 查询库存操作记录
-func (r *Repository) ListInventoryRecords(query *models.PaginationQuery, productID *uint, recordType string, startDate, endDate string) ([]models.InventoryRecord, int64, error) {
+// excludeVoided 为 true 时排除已冲销的原记录及其冲销记录
+func (r *Repository) ListInventoryRecords(query *models.PaginationQuery, productID *uint, recordType string, startDate, endDate string, excludeVoided bool) ([]models.InventoryRecord, int64, error) {
 	var records []models.InventoryRecord
 	var total int64
 
@@ -309,6 +461,9 @@ func (r *Repository) ListInventoryRecords(query *models.PaginationQuery, product
 	if recordType != "" {
 		db = db.Where("type = ?", recordType)
 	}
+	if excludeVoided {
+		db = db.Where("voided = ? AND reversal_of_id IS NULL", false)
+	}
 	if startDate != "" {
 		db = db.Where("created_at >= ?", startDate+" 00:00:00")
 	}
@@ -330,6 +485,54 @@ func (r *Repository) ListInventoryRecords(query *models.PaginationQuery, product
 	return records, total, err
 }
 
+// GetInventoryRecordsByReference 查找同一关联单号下的全部库存记录
+func (r *Repository) GetInventoryRecordsByReference(referenceNo string) ([]models.InventoryRecord, error) {
+	var records []models.InventoryRecord
+	err := r.db.Preload("Product").
+		Where("reference_no = ?", referenceNo).
+		Order("id ASC").
+		Find(&records).Error
+	return records, err
+}
+
+// GetInventoryRecordByID 根据ID查找库存记录
+func (r *Repository) GetInventoryRecordByID(id uint) (*models.InventoryRecord, error) {
+	var record models.InventoryRecord
+	err := r.db.Preload("Product").First(&record, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// VoidInventoryRecord 冲销库存记录 (事务): 写入反向记录并标记原记录已冲销
+func (r *Repository) VoidInventoryRecord(original, reversal *models.InventoryRecord) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		return voidRecord(tx, original, reversal)
+	})
+}
+
+// voidRecord 在事务内写入冲销记录并标记原记录
+func voidRecord(tx *gorm.DB, original, reversal *models.InventoryRecord) error {
+	if err := applyStockRecord(tx, reversal); err != nil {
+		return err
+	}
+	result := tx.Model(&models.InventoryRecord{}).
+		Where("id = ? AND voided = ?", original.ID, false).
+		Updates(map[string]interface{}{
+			"voided":         true,
+			"voided_at":      reversal.CreatedAt,
+			"reversed_by_id": reversal.ID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("记录已被冲销")
+	}
+	return nil
+}
+
 // ==================== 仪表盘统计 ====================
 
 // GetDashboardStats 获取仪表盘统计
@@ -352,7 +555,7 @@ func (r *Repository) GetDashboardStats() (*models.DashboardStats, error) {
 
 	// 低库存预警数
 	r.db.Model(&models.Product{}).
-		Where("current_stock <= min_stock AND min_stock > 0 AND status = 1").
+		Scopes(stockableProducts).Where("current_stock <= min_stock AND min_stock > 0 AND status = 1").
 		Count(&stats.LowStockCount)
 
 	// 今日统计
@@ -362,13 +565,15 @@ func (r *Repository) GetDashboardStats() (*models.DashboardStats, error) {
 
 	// 今日入库数量
 	r.db.Model(&models.InventoryRecord{}).
-		Where("type = ? AND created_at BETWEEN ? AND ?", models.StockIn, todayStart, todayEnd).
+		Where("type = ? AND voided = ? AND created_at BETWEEN ? AND ?", models.StockIn, false, todayStart, todayEnd).
 		Select("COALESCE(SUM(quantity), 0)").Scan(&stats.TodayStockIn)
 
 	// 今日出库数量
 	r.db.Model(&models.InventoryRecord{}).
-		Where("type = ? AND created_at BETWEEN ? AND ?", models.StockOut, todayStart, todayEnd).
+		Where("type = ? AND voided = ? AND created_at BETWEEN ? AND ?", models.StockOut, false, todayStart, todayEnd).
 		Select("COALESCE(SUM(quantity), 0)").Scan(&stats.TodayStockOut)
+	stats.TodayStockIn = models.RoundQty(stats.TodayStockIn, models.MaxQtyPrecision)
+	stats.TodayStockOut = models.RoundQty(stats.TodayStockOut, models.MaxQtyPrecision)
 
 	// 今日操作记录数
 	r.db.Model(&models.InventoryRecord{}).
@@ -378,52 +583,166 @@ func (r *Repository) GetDashboardStats() (*models.DashboardStats, error) {
 	return stats, nil
 }
 
-// GetChartData 获取图表数据
-func (r *Repository) GetChartData() (*models.ChartData, error) {
-	data := &models.ChartData{}
+// GetDailyMovements 按日汇总 [from, to) 内的入库、出库数量 (不含已冲销记录), 仅返回有记录的日期
+func (r *Repository) GetDailyMovements(from, to time.Time, filter *models.ChartQuery) ([]models.DailyMovement, error) {
+	var rows []models.DailyMovement
+	db := r.db.Model(&models.InventoryRecord{}).
+		Select(`DATE(inventory_records.created_at) AS date,
+			COALESCE(SUM(CASE WHEN inventory_records.type = ? THEN inventory_records.quantity ELSE 0 END), 0) AS stock_in,
+			COALESCE(SUM(CASE WHEN inventory_records.type = ? THEN inventory_records.quantity ELSE 0 END), 0) AS stock_out`,
+			models.StockIn, models.StockOut).
+		Where("inventory_records.voided = ? AND inventory_records.created_at >= ? AND inventory_records.created_at < ?", false, from, to)
+	if filter.CategoryID != nil || filter.SupplierID != nil {
+		db = db.Joins("JOIN products ON products.id = inventory_records.product_id")
+		db = chartProductFilter(db, filter)
+	}
+	err := db.Group("DATE(inventory_records.created_at)").
+		Order("date").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// GetTopStockValueProducts 库存价值最高的启用商品
+func (r *Repository) GetTopStockValueProducts(filter *models.ChartQuery, limit int) ([]models.ProductRank, error) {
+	var rows []models.ProductRank
+	db := r.db.Model(&models.Product{}).
+		Select("products.name, products.current_stock * products.cost_price AS value").
+		Where("products.status = 1")
+	err := chartProductFilter(db, filter).
+		Order("value DESC").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// GetCategoryProductStats 各启用分类下的启用商品数量
+func (r *Repository) GetCategoryProductStats(filter *models.ChartQuery) ([]models.CategoryStat, error) {
+	var rows []models.CategoryStat
+	join := "LEFT JOIN products ON products.category_id = categories.id AND products.status = 1 AND products.deleted_at IS NULL"
+	args := []interface{}{}
+	if filter.SupplierID != nil {
+		join += " AND products.supplier_id = ?"
+		args = append(args, *filter.SupplierID)
+	}
+	db := r.db.Model(&models.Category{}).
+		Select("categories.name, COUNT(products.id) AS count").
+		Joins(join, args...).
+		Where("categories.status = 1")
+	if filter.CategoryID != nil {
+		db = db.Where("categories.id = ?", *filter.CategoryID)
+	}
+	err := db.Group("categories.id, categories.name, categories.sort_order").
+		Order("categories.sort_order ASC").
+		Scan(&rows).Error
+	return rows, err
+}
 
-	// 最近30天出入库趋势
-	for i := 29; i >= 0; i-- {
-		date := time.Now().AddDate(0, 0, -i)
-		dateStr := date.Format("2006-01-02")
+// chartProductFilter 按分类、供应商过滤商品
+func chartProductFilter(db *gorm.DB, filter *models.ChartQuery) *gorm.DB {
+	if filter.CategoryID != nil {
+		db = db.Where("products.category_id = ?", *filter.CategoryID)
+	}
+	if filter.SupplierID != nil {
+		db = db.Where("products.supplier_id = ?", *filter.SupplierID)
+	}
+	return db
+}
 
-		movement := models.DailyMovement{Date: dateStr}
+// ==================== 写入通知 ====================
 
-		r.db.Model(&models.InventoryRecord{}).
-			Where("type = ? AND DATE(created_at) = ?", models.StockIn, dateStr).
-			Select("COALESCE(SUM(quantity), 0)").Scan(&movement.StockIn)
+// writeNotifySkipTables 不影响业务数据的表, 写入时不触发通知
+var writeNotifySkipTables = map[string]bool{
+	"idempotency_keys": true,
+	"report_jobs":      true,
+	"report_job_runs":  true,
+}
 
-		r.db.Model(&models.InventoryRecord{}).
-			Where("type = ? AND DATE(created_at) = ?", models.StockOut, dateStr).
-			Select("COALESCE(SUM(quantity), 0)").Scan(&movement.StockOut)
+// OnWrite 注册写入回调: 业务数据的创建、更新、删除提交成功后调用 fn
+// 单条写入在其默认事务提交后触发; 显式事务内的写入不单独触发, 由 transaction 在整个事务提交后触发一次
+func (r *Repository) OnWrite(fn func()) error {
+	notify := func(db *gorm.DB) {
+		if db.Error != nil || db.Statement.RowsAffected == 0 || writeNotifySkipTables[db.Statement.Table] {
+			return
+		}
+		if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+			return
+		}
+		fn()
+	}
+	cb := r.db.Callback()
+	if err := cb.Create().After("gorm:commit_or_rollback_transaction").Register("go-cargo:notify_create", notify); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:commit_or_rollback_transaction").Register("go-cargo:notify_update", notify); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:commit_or_rollback_transaction").Register("go-cargo:notify_delete", notify); err != nil {
+		return err
+	}
+	r.onWrite = fn
+	return nil
+}
 
-		data.StockMovement = append(data.StockMovement, movement)
+// transaction 执行业务数据写入事务, 提交成功后触发写入回调
+func (r *Repository) transaction(fn func(tx *gorm.DB) error) error {
+	if err := r.db.Transaction(fn); err != nil {
+		return err
+	}
+	if r.onWrite != nil {
+		r.onWrite()
 	}
+	return nil
+}
+
+// ==================== 标签 ====================
 
-	// 库存价值 TOP 10 商品
+// GetProductsByIDs 按ID查询商品, 按传入顺序返回 (不存在的ID跳过)
+func (r *Repository) GetProductsByIDs(ids []uint) ([]models.Product, error) {
 	var products []models.Product
-	r.db.Where("status = 1").
-		Order("(current_stock * cost_price) DESC").
-		Limit(10).
-		Find(&products)
+	if err := r.db.Where("id IN ?", ids).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Product, len(products))
 	for _, p := range products {
-		data.TopProducts = append(data.TopProducts, models.ProductRank{
-			Name:  p.Name,
-			Value: float64(p.CurrentStock) * p.CostPrice,
-		})
+		byID[p.ID] = p
+	}
+	result := make([]models.Product, 0, len(products))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			result = append(result, p)
+		}
 	}
+	return result, nil
+}
 
-	// 各分类商品数量
-	var categories []models.Category
-	r.db.Where("status = 1").Order("sort_order ASC").Find(&categories)
-	for _, cat := range categories {
-		var count int64
-		r.db.Model(&models.Product{}).Where("category_id = ? AND status = 1", cat.ID).Count(&count)
-		data.CategoryStats = append(data.CategoryStats, models.CategoryStat{
-			Name:  cat.Name,
-			Count: count,
-		})
+// GetProductLocations 获取商品已使用的库位及已建立的货位 (去重排序), prefix 为空时返回全部
+func (r *Repository) GetProductLocations(prefix string) ([]string, error) {
+	var locations []string
+	db := r.db.Model(&models.Product{}).Where("location <> ''")
+	if prefix != "" {
+		db = db.Where(pathPrefix("location", prefix))
+	}
+	if err := db.Distinct("location").Pluck("location", &locations).Error; err != nil {
+		return nil, err
+	}
+
+	var bins []string
+	db = r.db.Model(&models.Location{}).Where("level = ?", models.LocationBin)
+	if prefix != "" {
+		db = db.Where(pathPrefix("path", prefix))
+	}
+	if err := db.Pluck("path", &bins).Error; err != nil {
+		return nil, err
 	}
 
-	return data, nil
+	seen := make(map[string]bool, len(locations)+len(bins))
+	result := make([]string, 0, len(locations)+len(bins))
+	for _, l := range append(locations, bins...) {
+		if !seen[l] {
+			seen[l] = true
+			result = append(result, l)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
 }
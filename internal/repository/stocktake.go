@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"fmt"
+
+	"go-cargo/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 盘点 ====================
+
+// GetProductsForStocktakeScope 按盘点范围查询启用商品
+func (r *Repository) GetProductsForStocktakeScope(scopeType string, categoryID *uint, locationPrefix string) ([]models.Product, error) {
+	var products []models.Product
+	db := r.db.Scopes(stockableProducts).Where("status = 1")
+	switch scopeType {
+	case models.StocktakeScopeCategory:
+		db = db.Where("category_id = ?", categoryID)
+	case models.StocktakeScopeLocation:
+		db = db.Where(pathPrefix("location", locationPrefix))
+	}
+	err := db.Order("location ASC, sku ASC").Find(&products).Error
+	return products, err
+}
+
+// CreateStocktake 创建盘点单 (含明细快照)
+func (r *Repository) CreateStocktake(session *models.StocktakeSession) error {
+	return r.db.Create(session).Error
+}
+
+// ListStocktakes 获取盘点单列表
+func (r *Repository) ListStocktakes(query *models.PaginationQuery, status string) ([]models.StocktakeSession, int64, error) {
+	var sessions []models.StocktakeSession
+	var total int64
+
+	db := r.db.Model(&models.StocktakeSession{})
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	if query.Keyword != "" {
+		db = db.Where("session_no LIKE ? OR name LIKE ?", "%"+query.Keyword+"%", "%"+query.Keyword+"%")
+	}
+
+	db.Count(&total)
+	err := db.Order("id DESC").
+		Offset(query.GetOffset()).
+		Limit(query.PageSize).
+		Find(&sessions).Error
+	return sessions, total, err
+}
+
+// GetStocktakeByID 根据ID查找盘点单 (含明细及商品)
+func (r *Repository) GetStocktakeByID(id uint) (*models.StocktakeSession, error) {
+	var session models.StocktakeSession
+	err := r.db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Preload("Items.Product").First(&session, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetStocktakeItem 查找盘点单中某商品的明细
+func (r *Repository) GetStocktakeItem(sessionID, productID uint) (*models.StocktakeItem, error) {
+	var item models.StocktakeItem
+	err := r.db.Where("session_id = ? AND product_id = ?", sessionID, productID).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// SaveStocktakeCounts 保存计数并重新汇总明细的实盘数量与差异 (事务)
+// 同一盘点员对同一明细的新计数覆盖旧计数; 实盘数量取该明细最新提交的计数 (复盘覆盖初盘), 各盘点员的计数保留供核对
+func (r *Repository) SaveStocktakeCounts(counts []*models.StocktakeCount) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		for _, count := range counts {
+			if err := tx.Where("item_id = ? AND counter_id = ?", count.ItemID, count.CounterID).
+				Delete(&models.StocktakeCount{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(count).Error; err != nil {
+				return err
+			}
+
+			var item models.StocktakeItem
+			if err := tx.First(&item, count.ItemID).Error; err != nil {
+				return err
+			}
+			counted := models.RoundQty(count.Quantity, models.MaxQtyPrecision)
+			item.CountedQty = &counted
+			item.VarianceQty = models.RoundQty(counted-item.ExpectedQty, models.MaxQtyPrecision)
+			item.VarianceValue = item.VarianceQty * item.UnitCost
+			if err := tx.Save(&item).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListStocktakeCounts 获取盘点单的全部计数
+func (r *Repository) ListStocktakeCounts(sessionID uint) ([]models.StocktakeCount, error) {
+	var counts []models.StocktakeCount
+	err := r.db.Where("session_id = ?", sessionID).Order("id ASC").Find(&counts).Error
+	return counts, err
+}
+
+// UpdateStocktake 更新盘点单
+func (r *Repository) UpdateStocktake(session *models.StocktakeSession) error {
+	return r.db.Omit("Items").Save(session).Error
+}
+
+// ApproveStocktake 盘点过账 (事务): 写入调整记录、回填明细、更新盘点单状态
+// records 与 items 一一对应, 无差异的明细对应 nil; 仅盘点中的盘点单可过账, 已被其他请求审核或取消时整体回滚
+func (r *Repository) ApproveStocktake(session *models.StocktakeSession, items []*models.StocktakeItem, records []*models.InventoryRecord) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.StocktakeSession{}).
+			Where("id = ? AND status = ?", session.ID, models.StocktakeCounting).
+			Updates(map[string]interface{}{
+				"status":           session.Status,
+				"approved_by":      session.ApprovedBy,
+				"approved_by_name": session.ApprovedByName,
+				"approved_at":      session.ApprovedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("盘点单已被审核或取消")
+		}
+		for i, record := range records {
+			item := items[i]
+			if record != nil {
+				if err := applyStockRecord(tx, record); err != nil {
+					return err
+				}
+				item.RecordID = &record.ID
+			}
+			if err := tx.Omit("Product").Save(item).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// IsProductFrozen 商品是否处于冻结中的盘点单范围内
+func (r *Repository) IsProductFrozen(productID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.StocktakeItem{}).
+		Joins("JOIN stocktake_sessions ON stocktake_sessions.id = stocktake_items.session_id").
+		Where("stocktake_items.product_id = ? AND stocktake_sessions.freeze = ? AND stocktake_sessions.status = ? AND stocktake_sessions.deleted_at IS NULL",
+			productID, true, models.StocktakeCounting).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"time"
+
+	"go-cargo/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ==================== 退供应商 ====================
+
+// GetReceiptQty 汇总原入库单 (关联单号) 中某商品的有效入库数量与成本
+func (r *Repository) GetReceiptQty(receiptNo string, productID uint) (qty float64, unitCost float64, err error) {
+	var row struct {
+		Quantity  float64
+		TotalCost float64
+	}
+	err = r.db.Model(&models.InventoryRecord{}).
+		Select("COALESCE(SUM(quantity), 0) AS quantity, COALESCE(SUM(quantity * unit_cost), 0) AS total_cost").
+		Where("reference_no = ? AND product_id = ? AND type = ? AND voided = ?", receiptNo, productID, models.StockIn, false).
+		Scan(&row).Error
+	if err != nil || row.Quantity == 0 {
+		return 0, 0, err
+	}
+	return models.RoundQty(row.Quantity, models.MaxQtyPrecision), row.TotalCost / row.Quantity, nil
+}
+
+// CountReceiptRecords 统计关联单号下的有效入库记录数
+func (r *Repository) CountReceiptRecords(receiptNo string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.InventoryRecord{}).
+		Where("reference_no = ? AND type = ? AND voided = ?", receiptNo, models.StockIn, false).
+		Count(&count).Error
+	return count, err
+}
+
+// GetReturnedQtyForReceipt 汇总未取消的退供应商单中已从原入库单退回的商品数量
+func (r *Repository) GetReturnedQtyForReceipt(receiptNo string, productID uint) (float64, error) {
+	var qty float64
+	err := r.db.Model(&models.SupplierReturnItem{}).
+		Select("COALESCE(SUM(supplier_return_items.quantity), 0)").
+		Joins("JOIN supplier_returns ON supplier_returns.id = supplier_return_items.return_id AND supplier_returns.deleted_at IS NULL").
+		Where("supplier_returns.receipt_no = ? AND supplier_returns.status <> ? AND supplier_return_items.product_id = ?",
+			receiptNo, models.SupplierReturnCancelled, productID).
+		Scan(&qty).Error
+	return models.RoundQty(qty, models.MaxQtyPrecision), err
+}
+
+// CreateSupplierReturn 创建退供应商单 (含明细)
+func (r *Repository) CreateSupplierReturn(ret *models.SupplierReturn) error {
+	return r.db.Omit("Supplier", "Items.Product").Create(ret).Error
+}
+
+// ListSupplierReturns 获取退供应商单列表
+func (r *Repository) ListSupplierReturns(query *models.PaginationQuery, supplierID *uint, status string) ([]models.SupplierReturn, int64, error) {
+	var returns []models.SupplierReturn
+	var total int64
+
+	db := r.db.Model(&models.SupplierReturn{})
+	if supplierID != nil {
+		db = db.Where("supplier_id = ?", *supplierID)
+	}
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+	if query.Keyword != "" {
+		db = db.Where("return_no LIKE ? OR receipt_no LIKE ? OR credit_note_no LIKE ?",
+			"%"+query.Keyword+"%", "%"+query.Keyword+"%", "%"+query.Keyword+"%")
+	}
+
+	db.Count(&total)
+	err := db.Preload("Supplier").
+		Order("id DESC").
+		Offset(query.GetOffset()).
+		Limit(query.PageSize).
+		Find(&returns).Error
+	return returns, total, err
+}
+
+// GetSupplierReturnByID 根据ID查找退供应商单 (含供应商、明细及商品)
+func (r *Repository) GetSupplierReturnByID(id uint) (*models.SupplierReturn, error) {
+	var ret models.SupplierReturn
+	err := r.db.Preload("Supplier").Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Preload("Items.Product").First(&ret, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpdateSupplierReturn 更新退供应商单
+func (r *Repository) UpdateSupplierReturn(ret *models.SupplierReturn) error {
+	return r.db.Omit("Supplier", "Items").Save(ret).Error
+}
+
+// ShipSupplierReturn 退货出库 (事务): 写入退供应商记录、回填明细、更新单据状态
+// records 与 ret.Items 一一对应
+func (r *Repository) ShipSupplierReturn(ret *models.SupplierReturn, records []*models.InventoryRecord) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		for i, record := range records {
+			record.SourceType = models.SourceSupplierReturn
+			record.SourceID = ret.ID
+			if err := applyStockRecord(tx, record); err != nil {
+				return err
+			}
+			ret.Items[i].RecordID = &record.ID
+			if err := tx.Omit("Product").Save(&ret.Items[i]).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit("Supplier", "Items").Save(ret).Error
+	})
+}
+
+// GetSupplierReceipts 按商品所属供应商汇总期间入库数量与金额 (不含已冲销的记录)
+func (r *Repository) GetSupplierReceipts(from, to time.Time) (map[uint]models.SupplierReturnRateRow, error) {
+	var rows []models.SupplierReturnRateRow
+	err := r.db.Model(&models.InventoryRecord{}).
+		Select(`products.supplier_id,
+			COALESCE(SUM(inventory_records.quantity), 0) AS received_qty,
+			COALESCE(SUM(inventory_records.quantity * CASE WHEN inventory_records.unit_cost > 0
+				THEN inventory_records.unit_cost ELSE products.cost_price END), 0) AS received_value`).
+		Joins("JOIN products ON products.id = inventory_records.product_id").
+		Where("products.supplier_id IS NOT NULL").
+		Where("inventory_records.type = ? AND inventory_records.voided = ?", models.StockIn, false).
+		Where("inventory_records.created_at >= ? AND inventory_records.created_at < ?", from, to).
+		Group("products.supplier_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]models.SupplierReturnRateRow, len(rows))
+	for _, row := range rows {
+		result[row.SupplierID] = row
+	}
+	return result, nil
+}
+
+// GetSupplierReturnTotals 按供应商汇总期间退货出库的退供应商单
+func (r *Repository) GetSupplierReturnTotals(from, to time.Time) (map[uint]models.SupplierReturnRateRow, error) {
+	var rows []models.SupplierReturnRateRow
+	err := r.db.Model(&models.SupplierReturn{}).
+		Select(`supplier_id, COUNT(*) AS return_count,
+			COALESCE(SUM(total_quantity), 0) AS returned_qty,
+			COALESCE(SUM(credit_amount), 0) AS returned_value,
+			COALESCE(SUM(credited_amount), 0) AS credited_amount`).
+		Where("status IN ?", []string{models.SupplierReturnShipped, models.SupplierReturnCredited}).
+		Where("shipped_at >= ? AND shipped_at < ?", from, to).
+		Group("supplier_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]models.SupplierReturnRateRow, len(rows))
+	for _, row := range rows {
+		result[row.SupplierID] = row
+	}
+	return result, nil
+}
+
+// GetPendingSupplierCredits 按供应商汇总已退货尚未确认贷项的应退金额
+func (r *Repository) GetPendingSupplierCredits() (map[uint]float64, error) {
+	var rows []struct {
+		SupplierID uint
+		Amount     float64
+	}
+	err := r.db.Model(&models.SupplierReturn{}).
+		Select("supplier_id, COALESCE(SUM(credit_amount), 0) AS amount").
+		Where("status = ?", models.SupplierReturnShipped).
+		Group("supplier_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		result[row.SupplierID] = row.Amount
+	}
+	return result, nil
+}
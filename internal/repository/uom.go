@@ -0,0 +1,47 @@
+package repository
+
+import "go-cargo/internal/models"
+
+// ==================== 计量单位 ====================
+
+// ListProductUnits 获取商品的包装单位 (按换算系数升序)
+func (r *Repository) ListProductUnits(productID uint) ([]models.ProductUnit, error) {
+	var units []models.ProductUnit
+	err := r.db.Where("product_id = ?", productID).Order("factor ASC, id ASC").Find(&units).Error
+	return units, err
+}
+
+// GetProductUnit 获取商品下的单个包装单位
+func (r *Repository) GetProductUnit(productID, id uint) (*models.ProductUnit, error) {
+	var unit models.ProductUnit
+	err := r.db.Where("product_id = ?", productID).First(&unit, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &unit, nil
+}
+
+// GetProductUnitByName 按名称获取商品的包装单位
+func (r *Repository) GetProductUnitByName(productID uint, name string) (*models.ProductUnit, error) {
+	var unit models.ProductUnit
+	err := r.db.Where("product_id = ? AND name = ?", productID, name).First(&unit).Error
+	if err != nil {
+		return nil, err
+	}
+	return &unit, nil
+}
+
+// CreateProductUnit 创建包装单位
+func (r *Repository) CreateProductUnit(unit *models.ProductUnit) error {
+	return r.db.Create(unit).Error
+}
+
+// UpdateProductUnit 更新包装单位
+func (r *Repository) UpdateProductUnit(unit *models.ProductUnit) error {
+	return r.db.Save(unit).Error
+}
+
+// DeleteProductUnit 删除包装单位
+func (r *Repository) DeleteProductUnit(id uint) error {
+	return r.db.Delete(&models.ProductUnit{}, id).Error
+}
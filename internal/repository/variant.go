@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"go-cargo/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ==================== 多规格商品 ====================
+
+// ListProductAttributes 获取款式的规格属性 (按顺序)
+func (r *Repository) ListProductAttributes(productID uint) ([]models.ProductAttribute, error) {
+	var attrs []models.ProductAttribute
+	err := r.db.Where("product_id = ?", productID).Order("sort_order ASC, id ASC").Find(&attrs).Error
+	return attrs, err
+}
+
+// SaveProductAttributes 整体替换款式的规格属性, 并同步款式标记
+func (r *Repository) SaveProductAttributes(product *models.Product, attrs []models.ProductAttribute) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", product.ID).Delete(&models.ProductAttribute{}).Error; err != nil {
+			return err
+		}
+		if len(attrs) > 0 {
+			if err := tx.Create(&attrs).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Product{}).Where("id = ?", product.ID).
+			Update("has_variants", product.HasVariants).Error
+	})
+}
+
+// ListVariants 获取款式下的全部规格 (含属性取值)
+func (r *Repository) ListVariants(parentID uint) ([]models.Product, error) {
+	var variants []models.Product
+	err := r.db.Preload("Attributes").
+		Where("parent_id = ?", parentID).
+		Order("id ASC").
+		Find(&variants).Error
+	return variants, err
+}
+
+// GetVariant 获取款式下的单个规格 (含属性取值)
+func (r *Repository) GetVariant(parentID, id uint) (*models.Product, error) {
+	var variant models.Product
+	err := r.db.Preload("Attributes").Where("parent_id = ?", parentID).First(&variant, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// CreateVariants 在单一事务中创建规格及其属性取值
+func (r *Repository) CreateVariants(variants []*models.Product) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		for _, v := range variants {
+			if err := tx.Create(v).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SaveProducts 在单一事务中保存多个商品 (不含关联)
+func (r *Repository) SaveProducts(products []*models.Product) error {
+	return r.transaction(func(tx *gorm.DB) error {
+		for _, p := range products {
+			if err := tx.Omit(clause.Associations).Save(p).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// fillVariantStats 为列表中的款式填充规格数与汇总库存
+func (r *Repository) fillVariantStats(products []models.Product) error {
+	var ids []uint
+	for i := range products {
+		if products[i].HasVariants {
+			ids = append(ids, products[i].ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var rows []struct {
+		ParentID uint
+		Count    int64
+		Stock    float64
+	}
+	err := r.db.Model(&models.Product{}).
+		Select("parent_id, COUNT(*) AS count, COALESCE(SUM(current_stock), 0) AS stock").
+		Where("parent_id IN ?", ids).
+		Group("parent_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		for i := range products {
+			if products[i].ID == row.ParentID {
+				products[i].VariantCount = row.Count
+				products[i].VariantStock = models.RoundQty(row.Stock, models.MaxQtyPrecision)
+			}
+		}
+	}
+	return nil
+}
@@ -13,7 +13,7 @@ import (
 )
 
 // Setup 配置路由
-func Setup(h *handler.Handler, webFS fs.FS) *gin.Engine {
+func Setup(h *handler.Handler, idem middleware.IdempotencyStore, webFS fs.FS) *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Recovery())
 	r.Use(middleware.RequestLogger())
@@ -22,7 +22,7 @@ func Setup(h *handler.Handler, webFS fs.FS) *gin.Engine {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.IdempotencyHeader},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 	}))
@@ -63,6 +63,7 @@ func Setup(h *handler.Handler, webFS fs.FS) *gin.Engine {
 		// 需要认证的路由
 		protected := v1.Group("")
 		protected.Use(middleware.JWTAuth())
+		protected.Use(middleware.Idempotency(idem))
 		{
 			// 个人信息
 			protected.GET("/auth/profile", h.GetProfile)
@@ -88,18 +89,136 @@ func Setup(h *handler.Handler, webFS fs.FS) *gin.Engine {
 			protected.PUT("/suppliers/:id", h.UpdateSupplier)
 			protected.DELETE("/suppliers/:id", h.DeleteSupplier)
 
+			// 退供应商
+			protected.GET("/supplier-returns", h.ListSupplierReturns)
+			protected.POST("/supplier-returns", h.CreateSupplierReturn)
+			protected.GET("/supplier-returns/:id", h.GetSupplierReturn)
+			protected.POST("/supplier-returns/:id/ship", h.ShipSupplierReturn)
+			protected.POST("/supplier-returns/:id/credit", h.ConfirmSupplierCredit)
+			protected.POST("/supplier-returns/:id/cancel", h.CancelSupplierReturn)
+
 			// 商品管理
 			protected.GET("/products", h.ListProducts)
 			protected.GET("/products/:id", h.GetProduct)
+			protected.GET("/products/:id/ledger", h.GetProductLedger)
+			protected.GET("/products/:id/barcode", h.GetProductBarcode)
 			protected.POST("/products", h.CreateProduct)
 			protected.PUT("/products/:id", h.UpdateProduct)
 			protected.DELETE("/products/:id", h.DeleteProduct)
+			protected.GET("/products/:id/identifiers", h.ListProductIdentifiers)
+			protected.POST("/products/:id/identifiers", h.CreateProductIdentifier)
+			protected.PUT("/products/:id/identifiers/:identifier_id", h.UpdateProductIdentifier)
+			protected.DELETE("/products/:id/identifiers/:identifier_id", h.DeleteProductIdentifier)
+			protected.GET("/products/:id/units", h.ListProductUnits)
+			protected.POST("/products/:id/units", h.CreateProductUnit)
+			protected.PUT("/products/:id/units/:unit_id", h.UpdateProductUnit)
+			protected.DELETE("/products/:id/units/:unit_id", h.DeleteProductUnit)
+			protected.GET("/products/:id/attributes", h.ListProductAttributes)
+			protected.PUT("/products/:id/attributes", h.SetProductAttributes)
+			protected.GET("/products/:id/variants", h.ListVariants)
+			protected.POST("/products/:id/variants", h.GenerateVariants)
+			protected.PUT("/products/:id/variants/:variant_id", h.UpdateVariant)
+			protected.DELETE("/products/:id/variants/:variant_id", h.DeleteVariant)
+			protected.GET("/products/:id/bom", h.GetKitBOM)
+			protected.PUT("/products/:id/bom", h.SetKitBOM)
+			protected.DELETE("/products/:id/bom", h.DeleteKitBOM)
+			protected.POST("/products/:id/assemble", h.AssembleKit)
+			protected.POST("/products/:id/disassemble", h.DisassembleKit)
+			protected.GET("/kit-assemblies", h.ListKitAssemblies)
+			protected.GET("/kit-assemblies/:id", h.GetKitAssembly)
+			protected.GET("/products/:id/bins", h.GetProductBins)
+
+			// 库位管理
+			protected.GET("/locations", h.ListLocations)
+			protected.GET("/locations/putaway", h.GetPutawaySuggestions)
+			protected.GET("/locations/:id", h.GetLocation)
+			protected.GET("/locations/:id/stock", h.GetLocationStock)
+			protected.POST("/locations", h.CreateLocation)
+			protected.PUT("/locations/:id", h.UpdateLocation)
+			protected.DELETE("/locations/:id", h.DeleteLocation)
 
 			// 库存操作
 			protected.POST("/inventory/stock-in", h.StockIn)
 			protected.POST("/inventory/stock-out", h.StockOut)
 			protected.POST("/inventory/adjust", h.StockAdjust)
+			protected.POST("/inventory/move", h.MoveStock)
+			protected.GET("/pick-lists", h.ListPickLists)
+			protected.POST("/pick-lists", h.CreatePickList)
+			protected.GET("/pick-lists/:id", h.GetPickList)
+			protected.POST("/pick-lists/:id/confirm", h.ConfirmPickList)
+			protected.POST("/pick-lists/:id/cancel", h.CancelPickList)
+			protected.POST("/inventory/batch/stock-in", h.BatchStockIn)
+			protected.POST("/inventory/batch/stock-out", h.BatchStockOut)
+			protected.POST("/inventory/batch/adjust", h.BatchStockAdjust)
+			protected.GET("/scan/*code", h.Scan)
+			protected.POST("/scan/stock-in", h.ScanStockIn)
+			protected.POST("/scan/stock-out", h.ScanStockOut)
 			protected.GET("/inventory/records", h.ListInventoryRecords)
+			protected.POST("/inventory/records/:id/void", h.VoidInventoryRecord)
+			protected.GET("/inventory/adjustments", h.ListStockAdjustments)
+			protected.POST("/inventory/adjustments/:id/approve", middleware.AdminOnly(), h.ApproveStockAdjustment)
+			protected.POST("/inventory/adjustments/:id/reject", middleware.AdminOnly(), h.RejectStockAdjustment)
+
+			// 调整原因代码
+			protected.GET("/reason-codes", h.ListReasonCodes)
+			protected.GET("/reason-codes/all", h.GetAllReasonCodes)
+			protected.POST("/reason-codes", middleware.AdminOnly(), h.CreateReasonCode)
+			protected.PUT("/reason-codes/:id", middleware.AdminOnly(), h.UpdateReasonCode)
+			protected.DELETE("/reason-codes/:id", middleware.AdminOnly(), h.DeleteReasonCode)
+
+			// 补货与采购
+			protected.GET("/replenishment/suggestions", h.GetReorderSuggestions)
+			protected.POST("/replenishment/purchase-orders", h.CreatePurchaseOrdersFromSuggestions)
+			protected.GET("/purchase-orders", h.ListPurchaseOrders)
+			protected.GET("/purchase-orders/:id", h.GetPurchaseOrder)
+			protected.POST("/purchase-orders/:id/cancel", h.CancelPurchaseOrder)
+
+			// 需求预测
+			protected.GET("/forecast/products/:id", h.GetProductForecast)
+			protected.GET("/forecast/stockout", h.GetStockoutProjections)
+
+			// 分析报表
+			protected.GET("/analysis/abc-xyz", h.AnalyzeClassification)
+			protected.POST("/analysis/abc-xyz/apply", middleware.AdminOnly(), h.ApplyClassification)
+			protected.GET("/reports/stock-aging", h.GetStockAgingReport)
+			protected.GET("/reports/turnover", h.GetTurnoverReport)
+			protected.GET("/reports/stock-as-of", h.GetStockAsOf)
+			protected.GET("/reports/ledger-check", h.CheckLedgerConsistency)
+			protected.GET("/reports/supplier-returns", h.GetSupplierReturnRateReport)
+
+			// 打印单据 (PDF)
+			protected.GET("/documents/inventory-records/:id", h.GetRecordSlipPDF)
+			protected.GET("/documents/references/:reference_no", h.GetReferenceSlipPDF)
+			protected.GET("/documents/stocktakes/:id/count-sheet", h.GetStocktakeSheetPDF)
+			protected.GET("/documents/pick-lists/:id", h.GetPickListPDF)
+			protected.GET("/documents/products", h.GetProductListPDF)
+
+			// 条码标签
+			protected.GET("/labels/layouts", h.GetLabelLayouts)
+			protected.GET("/labels/products", h.GetProductLabels)
+			protected.GET("/labels/locations", h.GetLocationLabels)
+
+			// 定时报表 (管理员)
+			jobs := protected.Group("/report-jobs", middleware.AdminOnly())
+			{
+				jobs.GET("", h.ListReportJobs)
+				jobs.POST("", h.CreateReportJob)
+				jobs.GET("/:id", h.GetReportJob)
+				jobs.PUT("/:id", h.UpdateReportJob)
+				jobs.DELETE("/:id", h.DeleteReportJob)
+				jobs.POST("/:id/run", h.RunReportJob)
+				jobs.GET("/:id/runs", h.ListReportJobRuns)
+			}
+
+			// 盘点
+			protected.GET("/stocktakes", h.ListStocktakes)
+			protected.POST("/stocktakes", h.CreateStocktake)
+			protected.GET("/stocktakes/:id", h.GetStocktake)
+			protected.POST("/stocktakes/:id/counts", h.SubmitStocktakeCounts)
+			protected.GET("/stocktakes/:id/counts", h.ListStocktakeCounts)
+			protected.GET("/stocktakes/:id/variances", h.GetStocktakeVariances)
+			protected.POST("/stocktakes/:id/approve", middleware.AdminOnly(), h.ApproveStocktake)
+			protected.POST("/stocktakes/:id/cancel", h.CancelStocktake)
 		}
 	}
 
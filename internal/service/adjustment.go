@@ -0,0 +1,221 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 调整原因 ====================
+
+// ListReasonCodes 获取原因代码列表
+func (s *Service) ListReasonCodes(query *models.PaginationQuery) ([]models.ReasonCode, int64, error) {
+	return s.repo.ListReasonCodes(query)
+}
+
+// GetAllReasonCodes 获取所有启用原因代码
+func (s *Service) GetAllReasonCodes() ([]models.ReasonCode, error) {
+	return s.repo.GetAllReasonCodes()
+}
+
+// CreateReasonCode 创建原因代码
+func (s *Service) CreateReasonCode(req *models.ReasonCodeRequest) (*models.ReasonCode, error) {
+	if _, err := s.repo.GetReasonCodeByCode(req.Code); err == nil {
+		return nil, fmt.Errorf("原因代码 '%s' 已存在", req.Code)
+	}
+	reason := &models.ReasonCode{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		SortOrder:   req.SortOrder,
+		Status:      1,
+	}
+	if req.Status != 0 {
+		reason.Status = req.Status
+	}
+	if err := s.repo.CreateReasonCode(reason); err != nil {
+		return nil, fmt.Errorf("创建原因代码失败: %w", err)
+	}
+	return reason, nil
+}
+
+// UpdateReasonCode 更新原因代码
+func (s *Service) UpdateReasonCode(id uint, req *models.ReasonCodeRequest) (*models.ReasonCode, error) {
+	reason, err := s.repo.GetReasonCodeByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("原因代码不存在")
+	}
+	if req.Code != reason.Code {
+		if existing, err := s.repo.GetReasonCodeByCode(req.Code); err == nil && existing.ID != id {
+			return nil, fmt.Errorf("原因代码 '%s' 已存在", req.Code)
+		}
+	}
+	reason.Code = req.Code
+	reason.Name = req.Name
+	reason.Description = req.Description
+	reason.SortOrder = req.SortOrder
+	if req.Status != 0 {
+		reason.Status = req.Status
+	}
+	if err := s.repo.UpdateReasonCode(reason); err != nil {
+		return nil, fmt.Errorf("更新原因代码失败: %w", err)
+	}
+	return reason, nil
+}
+
+// DeleteReasonCode 删除原因代码
+func (s *Service) DeleteReasonCode(id uint) error {
+	return s.repo.DeleteReasonCode(id)
+}
+
+// validateReasonCode 校验原因代码 (必填, 须为启用的原因代码)
+func (s *Service) validateReasonCode(code string) error {
+	if code == "" {
+		return fmt.Errorf("请选择调整原因代码")
+	}
+	reason, err := s.repo.GetReasonCodeByCode(code)
+	if err != nil || reason.Status != 1 {
+		return fmt.Errorf("无效的调整原因代码: %s", code)
+	}
+	return nil
+}
+
+// adjustNeedsApproval 调整数量或金额是否超出审批阈值
+func (s *Service) adjustNeedsApproval(quantity float64, costPrice float64) bool {
+	if s.cfg.AdjustApprovalQty > 0 && quantity > float64(s.cfg.AdjustApprovalQty) {
+		return true
+	}
+	if s.cfg.AdjustApprovalValue > 0 && quantity*costPrice > s.cfg.AdjustApprovalValue {
+		return true
+	}
+	return false
+}
+
+// newPendingAdjustment 构造待审批的调整单, diff 为相对 beforeQty 的差异
+func newPendingAdjustment(product *models.Product, beforeQty, newQuantity, diff float64, reasonCode, notes string, operatorID uint, operatorName string) *models.StockAdjustment {
+	return &models.StockAdjustment{
+		ProductID:       product.ID,
+		ReasonCode:      reasonCode,
+		BeforeQty:       beforeQty,
+		NewQuantity:     newQuantity,
+		DiffQty:         diff,
+		DiffValue:       diff * product.CostPrice,
+		Notes:           notes,
+		Status:          models.AdjustmentPending,
+		RequestedBy:     operatorID,
+		RequestedByName: operatorName,
+	}
+}
+
+// ==================== 调整审批 ====================
+
+// ListStockAdjustments 获取调整单列表
+func (s *Service) ListStockAdjustments(query *models.PaginationQuery, status string) ([]models.StockAdjustment, int64, error) {
+	return s.repo.ListStockAdjustments(query, status)
+}
+
+// ApproveStockAdjustment 批准调整单并过账
+// 申请时的差异叠加到当前库存上, 申请后发生的出入库不会被覆盖
+func (s *Service) ApproveStockAdjustment(id uint, req *models.AdjustmentReviewRequest, reviewerID uint, reviewerName string) (*models.StockAdjustment, error) {
+	adj, err := s.repo.GetStockAdjustmentByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("调整单不存在")
+	}
+	if adj.Status != models.AdjustmentPending {
+		return nil, fmt.Errorf("调整单状态为 %s，无法审批", adj.Status)
+	}
+	if adj.RequestedBy == reviewerID {
+		return nil, fmt.Errorf("不能审批自己提交的调整")
+	}
+	if adj.VoidRecordID != nil {
+		return s.approveVoidAdjustment(adj, req, reviewerID, reviewerName)
+	}
+	if err := s.checkNotFrozen(adj.ProductID); err != nil {
+		return nil, err
+	}
+
+	product, err := s.repo.GetProductByID(adj.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	if err := checkStockable(product); err != nil {
+		return nil, err
+	}
+	afterQty := product.RoundQty(product.CurrentStock + adj.DiffQty)
+	if afterQty < 0 {
+		return nil, fmt.Errorf("批准后库存为负 (当前 %s，差异 %s)", formatQty(product.CurrentStock), formatQty(adj.DiffQty))
+	}
+	quantity := adj.DiffQty
+	if quantity < 0 {
+		quantity = -quantity
+	}
+
+	now := time.Now()
+	adj.Status = models.AdjustmentApproved
+	adj.ReviewedBy = reviewerID
+	adj.ReviewedByName = reviewerName
+	adj.ReviewedAt = &now
+	adj.ReviewNotes = req.Notes
+
+	record := &models.InventoryRecord{
+		ProductID:    adj.ProductID,
+		Type:         models.StockAdjust,
+		Quantity:     quantity,
+		BeforeQty:    product.CurrentStock,
+		AfterQty:     afterQty,
+		Notes:        adj.Notes,
+		ReasonCode:   adj.ReasonCode,
+		OperatorID:   reviewerID,
+		OperatorName: reviewerName,
+		SourceType:   models.SourceAdjustment,
+		SourceID:     adj.ID,
+	}
+	if err := s.repo.ApproveStockAdjustment(adj, record); err != nil {
+		return nil, fmt.Errorf("调整过账失败: %w", err)
+	}
+	return adj, nil
+}
+
+// approveVoidAdjustment 批准冲销申请: 按当前库存冲销原记录
+func (s *Service) approveVoidAdjustment(adj *models.StockAdjustment, req *models.AdjustmentReviewRequest, reviewerID uint, reviewerName string) (*models.StockAdjustment, error) {
+	original, _, reversal, err := s.prepareVoid(*adj.VoidRecordID, adj.Notes, reviewerID, reviewerName)
+	if err != nil {
+		return nil, err
+	}
+	reversal.SourceType = models.SourceAdjustment
+	reversal.SourceID = adj.ID
+
+	now := time.Now()
+	adj.Status = models.AdjustmentApproved
+	adj.ReviewedBy = reviewerID
+	adj.ReviewedByName = reviewerName
+	adj.ReviewedAt = &now
+	adj.ReviewNotes = req.Notes
+	if err := s.repo.ApproveVoidAdjustment(adj, original, reversal); err != nil {
+		return nil, fmt.Errorf("冲销失败: %w", err)
+	}
+	return adj, nil
+}
+
+// RejectStockAdjustment 驳回调整单 (不改动库存)
+func (s *Service) RejectStockAdjustment(id uint, req *models.AdjustmentReviewRequest, reviewerID uint, reviewerName string) (*models.StockAdjustment, error) {
+	adj, err := s.repo.GetStockAdjustmentByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("调整单不存在")
+	}
+	if adj.Status != models.AdjustmentPending {
+		return nil, fmt.Errorf("调整单状态为 %s，无法审批", adj.Status)
+	}
+
+	now := time.Now()
+	adj.Status = models.AdjustmentRejected
+	adj.ReviewedBy = reviewerID
+	adj.ReviewedByName = reviewerName
+	adj.ReviewedAt = &now
+	adj.ReviewNotes = req.Notes
+	if err := s.repo.UpdateStockAdjustment(adj); err != nil {
+		return nil, fmt.Errorf("驳回调整失败: %w", err)
+	}
+	return adj, nil
+}
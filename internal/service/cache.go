@@ -0,0 +1,85 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// ==================== 聚合缓存 ====================
+
+// maxCacheEntries 缓存条目上限; 键含查询参数, 需限制条目数以免内存无限增长
+const maxCacheEntries = 256
+
+// aggregateCache 进程内聚合结果缓存, 条目按 TTL 过期, 任何业务数据写入时整体清空
+type aggregateCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	gen     uint64 // 每次清空递增, 避免清空前开始的加载把旧结果写回
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newAggregateCache(ttl time.Duration) *aggregateCache {
+	return &aggregateCache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+// invalidate 清空全部缓存
+func (c *aggregateCache) invalidate() {
+	c.mu.Lock()
+	c.gen++
+	c.entries = make(map[string]cacheEntry)
+	c.mu.Unlock()
+}
+
+// cached 命中缓存直接返回, 否则调用 load 并缓存结果; TTL 为 0 时不缓存
+func cached[T any](c *aggregateCache, key string, load func() (T, error)) (T, error) {
+	if c.ttl <= 0 {
+		return load()
+	}
+
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok && time.Now().Before(e.expiresAt) {
+		c.mu.Unlock()
+		return e.value.(T), nil
+	}
+	gen := c.gen
+	c.mu.Unlock()
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	if gen == c.gen {
+		now := time.Now()
+		if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCacheEntries {
+			c.evict(now)
+		}
+		c.entries[key] = cacheEntry{value: value, expiresAt: now.Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// evict 删除已过期的条目; 仍达到上限时删除最早过期的条目 (调用方持有锁)
+func (c *aggregateCache) evict(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for k, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, k)
+			continue
+		}
+		if oldestKey == "" || e.expiresAt.Before(oldest) {
+			oldestKey, oldest = k, e.expiresAt
+		}
+	}
+	if len(c.entries) >= maxCacheEntries {
+		delete(c.entries, oldestKey)
+	}
+}
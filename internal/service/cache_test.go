@@ -0,0 +1,42 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAggregateCacheBounded(t *testing.T) {
+	c := newAggregateCache(time.Minute)
+	load := func(v int) func() (int, error) {
+		return func() (int, error) { return v, nil }
+	}
+
+	for i := 0; i < maxCacheEntries*3; i++ {
+		if _, err := cached(c, fmt.Sprintf("charts:%d", i), load(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(c.entries); n > maxCacheEntries {
+		t.Fatalf("缓存条目数 = %d, 超过上限 %d", n, maxCacheEntries)
+	}
+
+	// 最近写入的条目仍可命中
+	key := fmt.Sprintf("charts:%d", maxCacheEntries*3-1)
+	if v, _ := cached(c, key, load(-1)); v != maxCacheEntries*3-1 {
+		t.Errorf("最近写入的条目未命中: %d", v)
+	}
+}
+
+func TestAggregateCacheEvictsExpired(t *testing.T) {
+	c := newAggregateCache(time.Minute)
+	for i := 0; i < maxCacheEntries; i++ {
+		c.entries[fmt.Sprintf("old:%d", i)] = cacheEntry{value: i, expiresAt: time.Now().Add(-time.Second)}
+	}
+	if _, err := cached(c, "new", func() (int, error) { return 1, nil }); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(c.entries); n != 1 {
+		t.Errorf("过期条目未清理, 剩余 %d 条", n)
+	}
+}
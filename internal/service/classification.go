@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== ABC/XYZ 分类 ====================
+
+// ABC 累计金额占比阈值 (%) 与 XYZ 变异系数阈值
+const (
+	abcClassAMax = 80.0
+	abcClassBMax = 95.0
+	xyzClassXMax = 0.5
+	xyzClassYMax = 1.0
+)
+
+// AnalyzeClassification 计算 ABC/XYZ 分类 (不写入商品)
+//
+//	ABC: 按期间出库消耗金额降序累计, 累计占比前 80% 为 A, 80%~95% 为 B, 其余为 C
+//	XYZ: 按周出库量的变异系数, ≤0.5 为 X, ≤1.0 为 Y, 其余 (含无需求) 为 Z
+func (s *Service) AnalyzeClassification(days int) (*models.ClassificationReport, error) {
+	if days <= 0 {
+		days = s.cfg.ClassifyPeriodDays
+	}
+
+	weekQuery := &models.ForecastQuery{Granularity: "week", History: days}
+	start, periods := forecastPeriods(weekQuery)
+
+	consumption, err := s.repo.GetConsumptionByProduct(start)
+	if err != nil {
+		return nil, fmt.Errorf("统计出库消耗失败: %w", err)
+	}
+	byProduct := make(map[uint]models.ProductConsumption, len(consumption))
+	for _, c := range consumption {
+		byProduct[c.ProductID] = c
+	}
+
+	daily, err := s.repo.GetDailyOutbound(start, 0)
+	if err != nil {
+		return nil, fmt.Errorf("统计出库历史失败: %w", err)
+	}
+	dailyByProduct := make(map[uint][]models.DailyQuantity)
+	for _, d := range daily {
+		dailyByProduct[d.ProductID] = append(dailyByProduct[d.ProductID], d)
+	}
+
+	products, err := s.repo.GetActiveProductsWithSupplier()
+	if err != nil {
+		return nil, fmt.Errorf("查询商品失败: %w", err)
+	}
+
+	report := &models.ClassificationReport{
+		From:   start.Format("2006-01-02"),
+		To:     start.AddDate(0, 0, 7*periods-1).Format("2006-01-02"),
+		Matrix: make(map[string]int),
+		Items:  make([]models.ProductClassification, 0, len(products)),
+	}
+	for _, p := range products {
+		c := byProduct[p.ID]
+		weekly := bucketSeries(dailyByProduct[p.ID], start, periods, "week")
+		report.TotalValue += c.Value
+		item := models.ProductClassification{
+			ProductID: p.ID,
+			SKU:       p.SKU,
+			Name:      p.Name,
+			Quantity:  c.Quantity,
+			Value:     round2(c.Value),
+			XYZClass:  "Z", // 无需求时变异系数无意义, 归为最不稳定
+		}
+		if cv, ok := coefficientOfVariation(weekly); ok {
+			cv = round2(cv)
+			item.CV = &cv
+			item.XYZClass = xyzClass(cv)
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	sort.SliceStable(report.Items, func(i, j int) bool { return report.Items[i].Value > report.Items[j].Value })
+	var cumulative float64
+	for i := range report.Items {
+		item := &report.Items[i]
+		share := 0.0
+		if report.TotalValue > 0 {
+			share = item.Value / report.TotalValue * 100
+		}
+		switch {
+		case item.Value <= 0:
+			item.ABCClass = "C"
+		case cumulative < abcClassAMax:
+			item.ABCClass = "A"
+		case cumulative < abcClassBMax:
+			item.ABCClass = "B"
+		default:
+			item.ABCClass = "C"
+		}
+		cumulative += share
+		item.ValueShare = round2(share)
+		item.CumulativePct = round2(cumulative)
+		report.Matrix[item.ABCClass+item.XYZClass]++
+	}
+	report.TotalValue = round2(report.TotalValue)
+	return report, nil
+}
+
+// ApplyClassification 计算分类并写入商品
+func (s *Service) ApplyClassification(days int) (*models.ClassificationReport, error) {
+	report, err := s.AnalyzeClassification(days)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := s.repo.UpdateProductClasses(report.Items, now); err != nil {
+		return nil, fmt.Errorf("保存商品分类失败: %w", err)
+	}
+	report.Applied = true
+	report.AppliedAt = &now
+	return report, nil
+}
+
+// StartClassificationScheduler 按配置间隔定时重算商品分类, ctx 取消时退出
+func (s *Service) StartClassificationScheduler(ctx context.Context) {
+	if s.cfg.ClassifyIntervalHours <= 0 {
+		return
+	}
+	interval := time.Duration(s.cfg.ClassifyIntervalHours) * time.Hour
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if _, err := s.ApplyClassification(0); err != nil {
+				log.Printf("[Classify] 商品分类重算失败: %v", err)
+			} else {
+				log.Printf("[Classify] 商品分类已重算")
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// coefficientOfVariation 变异系数 (标准差 / 均值), 无需求 (均值为 0) 时 ok 为 false
+func coefficientOfVariation(series []float64) (cv float64, ok bool) {
+	if len(series) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, v := range series {
+		sum += v
+	}
+	mean := sum / float64(len(series))
+	if mean == 0 {
+		return 0, false
+	}
+	var variance float64
+	for _, v := range series {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance/float64(len(series))) / mean, true
+}
+
+// xyzClass 按变异系数划分 XYZ
+func xyzClass(cv float64) string {
+	switch {
+	case cv <= xyzClassXMax:
+		return "X"
+	case cv <= xyzClassYMax:
+		return "Y"
+	default:
+		return "Z"
+	}
+}
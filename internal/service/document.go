@@ -0,0 +1,343 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"go-cargo/internal/export"
+	"go-cargo/internal/models"
+)
+
+// ==================== 打印单据 ====================
+
+// maxDocumentProducts 商品清单 PDF 最多包含的商品数
+const maxDocumentProducts = 5000
+
+// recordTypeNames 库存记录类型对应的单据名称
+var recordTypeNames = map[models.InventoryRecordType]string{
+	models.StockIn:     "入库单",
+	models.StockOut:    "出库单",
+	models.StockAdjust: "库存调整单",
+	models.StockVoid:   "冲销单",
+
+	models.StockAssemble:    "组装单",
+	models.StockDisassemble: "拆卸单",
+	models.StockMove:        "移库单",
+	models.StockReturn:      "退供应商单",
+}
+
+// RecordSlipPDF 单条库存记录的出入库单, 返回 PDF 内容与文件名
+func (s *Service) RecordSlipPDF(id uint) ([]byte, string, error) {
+	record, err := s.repo.GetInventoryRecordByID(id)
+	if err != nil {
+		return nil, "", fmt.Errorf("库存记录不存在")
+	}
+	docNo := record.ReferenceNo
+	if docNo == "" {
+		docNo = fmt.Sprintf("IR%08d", record.ID)
+	}
+	return s.slipPDF(docNo, []models.InventoryRecord{*record})
+}
+
+// ReferenceSlipPDF 同一关联单号下全部库存记录合并为一张单据
+func (s *Service) ReferenceSlipPDF(referenceNo string) ([]byte, string, error) {
+	if referenceNo == "" {
+		return nil, "", fmt.Errorf("关联单号不能为空")
+	}
+	records, err := s.repo.GetInventoryRecordsByReference(referenceNo)
+	if err != nil {
+		return nil, "", fmt.Errorf("查询库存记录失败: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, "", fmt.Errorf("关联单号 %s 下没有库存记录", referenceNo)
+	}
+	return s.slipPDF(referenceNo, records)
+}
+
+// slipPDF 生成出入库单: 单据信息、条码、明细、备注与签字栏
+func (s *Service) slipPDF(docNo string, records []models.InventoryRecord) ([]byte, string, error) {
+	first := records[0]
+	title := recordTypeNames[first.Type]
+	mixed := false
+	for _, r := range records[1:] {
+		if r.Type != first.Type {
+			mixed = true
+			title = "库存单据"
+			break
+		}
+	}
+
+	d := s.renderer.NewDocument(title, false)
+	documentBarcode(d, docNo)
+	d.InfoGrid([][2]string{
+		{"单据编号", docNo},
+		{"日期", first.CreatedAt.Local().Format("2006-01-02 15:04")},
+		{"经办人", first.OperatorName},
+		{"明细行数", strconv.Itoa(len(records))},
+	}, 2)
+
+	t := &export.Table{
+		Headers: []string{"序号", "SKU", "商品名称", "单位", "数量", "单价", "金额", "库位"},
+		Numeric: []bool{true, false, false, false, true, true, true, false},
+	}
+	if mixed {
+		t.Headers = append(t.Headers[:3:3], append([]string{"类型"}, t.Headers[3:]...)...)
+		t.Numeric = append(t.Numeric[:3:3], append([]bool{false}, t.Numeric[3:]...)...)
+	}
+	var totalQty float64
+	var totalAmount float64
+	var notes []string
+	voided := false
+	for i, r := range records {
+		var sku, name, unit, location string
+		unitCost := r.UnitCost
+		if r.Product != nil {
+			sku, name, unit, location = r.Product.SKU, r.Product.Name, r.Product.Unit, r.Product.Location
+			if unitCost == 0 {
+				unitCost = r.Product.CostPrice
+			}
+		}
+		amount := r.Quantity * unitCost
+		totalQty += r.Quantity
+		totalAmount += amount
+		row := []string{strconv.Itoa(i + 1), sku, name}
+		if mixed {
+			row = append(row, recordTypeNames[r.Type])
+		}
+		row = append(row, unit, formatQty(r.Quantity), formatMoney(unitCost), formatMoney(amount), location)
+		t.Rows = append(t.Rows, row)
+		if r.Notes != "" {
+			notes = append(notes, r.Notes)
+		}
+		voided = voided || r.Voided
+	}
+	t.Footer = make([]string, len(t.Headers))
+	t.Footer[0] = "合计"
+	t.Footer[len(t.Headers)-4] = formatQty(totalQty)
+	t.Footer[len(t.Headers)-2] = formatMoney(totalAmount)
+	d.Table(t, nil)
+
+	if len(notes) > 0 {
+		d.Ln(2)
+		d.SetFontStyle("", 9)
+		d.MultiCell(0, 5, d.Text("备注: "+joinUnique(notes, "; ")), "", "L", false)
+	}
+	if voided {
+		d.Ln(2)
+		d.SetFontStyle("B", 10)
+		d.SetTextColor(200, 0, 0)
+		d.CellFormat(0, 6, d.Text("本单据包含已冲销的记录"), "", 1, "L", false, 0, "")
+		d.SetTextColor(0, 0, 0)
+	}
+
+	switch {
+	case mixed:
+		d.Signatures("制单人", "仓管员", "审核人")
+	case first.Type == models.StockIn:
+		d.Signatures("制单人", "送货人", "仓管员")
+	case first.Type == models.StockOut:
+		d.Signatures("制单人", "领货人", "仓管员")
+	case first.Type == models.StockReturn:
+		d.Signatures("制单人", "承运人", "仓管员")
+	default:
+		d.Signatures("制单人", "仓管员", "审核人")
+	}
+
+	data, err := d.Bytes()
+	if err != nil {
+		return nil, "", fmt.Errorf("生成 PDF 失败: %w", err)
+	}
+	return data, safeFileName(docNo) + ".pdf", nil
+}
+
+// StocktakeSheetPDF 盘点表: 按库位、SKU 排序列出待盘商品, 留空实盘数量栏; blind 为 true 时不打印账面数量
+func (s *Service) StocktakeSheetPDF(id uint, blind bool) ([]byte, string, error) {
+	session, err := s.repo.GetStocktakeByID(id)
+	if err != nil {
+		return nil, "", fmt.Errorf("盘点单不存在")
+	}
+
+	d := s.renderer.NewDocument("盘点表", false)
+	documentBarcode(d, session.SessionNo)
+	scope := "全部商品"
+	switch session.ScopeType {
+	case models.StocktakeScopeCategory:
+		scope = "指定分类"
+	case models.StocktakeScopeLocation:
+		scope = "库位前缀 " + session.LocationPrefix
+	}
+	d.InfoGrid([][2]string{
+		{"盘点单号", session.SessionNo},
+		{"名称", session.Name},
+		{"盘点范围", scope},
+		{"商品数", strconv.Itoa(len(session.Items))},
+		{"创建人", session.CreatedByName},
+		{"创建时间", session.CreatedAt.Local().Format("2006-01-02 15:04")},
+	}, 2)
+
+	items := session.Items
+	sort.SliceStable(items, func(i, j int) bool {
+		li, lj := productLocation(items[i].Product), productLocation(items[j].Product)
+		if li != lj {
+			return li < lj
+		}
+		return productSKU(items[i].Product) < productSKU(items[j].Product)
+	})
+
+	t := &export.Table{
+		Headers:  []string{"序号", "库位", "SKU", "商品名称", "单位", "账面数量", "实盘数量", "备注"},
+		Numeric:  []bool{true, false, false, false, false, true, true, false},
+		Barcodes: []bool{false, false, true},
+	}
+	widths := []float64{10, 22, 40, 48, 12, 18, 18, 18}
+	if blind {
+		t.Headers = append(t.Headers[:5:5], t.Headers[6:]...)
+		t.Numeric = append(t.Numeric[:5:5], t.Numeric[6:]...)
+		widths = []float64{10, 22, 40, 56, 14, 22, 22}
+	}
+	for i, item := range items {
+		row := []string{strconv.Itoa(i + 1), productLocation(item.Product), productSKU(item.Product), "", ""}
+		if item.Product != nil {
+			row[3], row[4] = item.Product.Name, item.Product.Unit
+		}
+		if !blind {
+			row = append(row, formatQty(item.ExpectedQty))
+		}
+		row = append(row, "", "")
+		t.Rows = append(t.Rows, row)
+	}
+	d.Table(t, widths)
+	d.Signatures("盘点人", "复核人", "监盘人")
+
+	data, err := d.Bytes()
+	if err != nil {
+		return nil, "", fmt.Errorf("生成 PDF 失败: %w", err)
+	}
+	return data, safeFileName(session.SessionNo) + ".pdf", nil
+}
+
+// PickListPDF 拣货单: 按拣货顺序列出库位与应拣数量, 已确认的打印实拣数量, 备注为需求该商品的批次单号
+func (s *Service) PickListPDF(id uint) ([]byte, string, error) {
+	list, err := s.repo.GetPickListByID(id)
+	if err != nil {
+		return nil, "", fmt.Errorf("拣货单不存在")
+	}
+
+	d := s.renderer.NewDocument("拣货单", false)
+	documentBarcode(d, list.PickNo)
+	d.InfoGrid([][2]string{
+		{"拣货单号", list.PickNo},
+		{"出库批次数", strconv.Itoa(list.OrderCount)},
+		{"明细数", strconv.Itoa(len(list.Lines))},
+		{"状态", list.Status},
+		{"创建人", list.CreatedByName},
+		{"创建时间", list.CreatedAt.Local().Format("2006-01-02 15:04")},
+	}, 2)
+
+	refs := make(map[uint][]string)
+	for _, order := range list.Orders {
+		refs[order.ProductID] = append(refs[order.ProductID], order.ReferenceNo)
+	}
+
+	t := &export.Table{
+		Headers:  []string{"序号", "库位", "SKU", "商品名称", "单位", "应拣数量", "实拣数量", "出库单号"},
+		Numeric:  []bool{true, false, false, false, false, true, true, false},
+		Barcodes: []bool{false, false, true},
+	}
+	for _, line := range list.Lines {
+		picked := ""
+		if line.PickedQty != nil {
+			picked = formatQty(*line.PickedQty)
+		}
+		row := []string{strconv.Itoa(line.Sequence), line.LocationPath, productSKU(line.Product), "", "",
+			formatQty(line.Quantity), picked, joinUnique(refs[line.ProductID], ", ")}
+		if line.Product != nil {
+			row[3], row[4] = line.Product.Name, line.Product.Unit
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	d.Table(t, []float64{10, 26, 36, 44, 12, 18, 18, 26})
+	d.Signatures("拣货人", "复核人")
+
+	data, err := d.Bytes()
+	if err != nil {
+		return nil, "", fmt.Errorf("生成 PDF 失败: %w", err)
+	}
+	return data, safeFileName(list.PickNo) + ".pdf", nil
+}
+
+// ProductListPDF 商品清单, 筛选条件与商品列表一致, SKU 打印为条码
+func (s *Service) ProductListPDF(query *models.PaginationQuery, filter *models.ProductFilter) ([]byte, string, error) {
+	products, err := s.repo.FindProducts(query, filter, maxDocumentProducts)
+	if err != nil {
+		return nil, "", fmt.Errorf("查询商品失败: %w", err)
+	}
+
+	d := s.renderer.NewDocument("商品清单", true)
+	d.InfoGrid([][2]string{{"商品数", strconv.Itoa(len(products))}}, 1)
+	t := &export.Table{
+		Headers:  []string{"SKU", "商品名称", "分类", "供应商", "单位", "库存", "库位", "成本价", "售价"},
+		Numeric:  []bool{false, false, false, false, false, true, false, true, true},
+		Barcodes: []bool{true},
+	}
+	for _, p := range products {
+		t.Rows = append(t.Rows, []string{
+			p.SKU, p.Name, categoryName(&p), supplierName(&p), p.Unit, formatQty(p.CurrentStock),
+			p.Location, formatMoney(p.CostPrice), formatMoney(p.SellingPrice),
+		})
+	}
+	d.Table(t, nil)
+
+	data, err := d.Bytes()
+	if err != nil {
+		return nil, "", fmt.Errorf("生成 PDF 失败: %w", err)
+	}
+	return data, "products.pdf", nil
+}
+
+// documentBarcode 在单据信息区上方右侧绘制单号条码; 单号无法编码 (如含非 ASCII 字符) 时改为打印文字
+func documentBarcode(d *export.Document, docNo string) {
+	const w, h = 60, 14
+	left, _, _, _ := d.GetMargins()
+	y := d.GetY()
+	if err := d.Code128(docNo, left+d.ContentWidth()-w, y, w, h); err != nil {
+		d.SetXY(left+d.ContentWidth()-w, y)
+		d.SetFontStyle("B", 11)
+		d.CellFormat(w, h, d.Fit(docNo, w), "", 0, "R", false, 0, "")
+	}
+	d.SetXY(left, y+h+2)
+}
+
+// productLocation 商品库位 (商品为空时返回空字符串)
+func productLocation(p *models.Product) string {
+	if p == nil {
+		return ""
+	}
+	return p.Location
+}
+
+// productSKU 商品 SKU (商品为空时返回空字符串)
+func productSKU(p *models.Product) string {
+	if p == nil {
+		return ""
+	}
+	return p.SKU
+}
+
+// joinUnique 去重后拼接
+func joinUnique(values []string, sep string) string {
+	seen := make(map[string]bool, len(values))
+	var out string
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		if out != "" {
+			out += sep
+		}
+		out += v
+		seen[v] = true
+	}
+	return out
+}
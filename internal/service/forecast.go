@@ -0,0 +1,399 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 需求预测 ====================
+
+// forecastResult 单个模型的拟合与预测结果
+type forecastResult struct {
+	model     string
+	fitted    []float64 // 历史一步预测值 (与序列等长, 无法预测的期为 NaN)
+	forecasts []float64 // 未来各期预测值
+	mae       float64
+	sigma     float64 // 一步预测残差标准差
+}
+
+// 预测参数上限, 历史与预测序列按期数分配内存
+const (
+	maxForecastHistory = 3650 // 历史天数
+	maxForecastHorizon = 365  // 预测期数
+)
+
+// normalizeForecastQuery 填充预测参数默认值, 校验取值范围
+func normalizeForecastQuery(q *models.ForecastQuery) error {
+	if q.Model == "" {
+		q.Model = models.ForecastAuto
+	}
+	switch q.Model {
+	case models.ForecastAuto, models.ForecastMovingAverage, models.ForecastExponential, models.ForecastHoltWinters:
+	default:
+		return fmt.Errorf("不支持的预测模型: %s", q.Model)
+	}
+	if q.Granularity == "" {
+		q.Granularity = "day"
+	}
+	if q.Granularity != "day" && q.Granularity != "week" {
+		return fmt.Errorf("粒度仅支持 day / week")
+	}
+	if q.History <= 0 {
+		q.History = 180
+	}
+	if q.History > maxForecastHistory {
+		return fmt.Errorf("历史天数最多 %d 天", maxForecastHistory)
+	}
+	weekly := q.Granularity == "week"
+	if q.Horizon <= 0 {
+		q.Horizon = 30
+		if weekly {
+			q.Horizon = 12
+		}
+	}
+	if q.Horizon > maxForecastHorizon {
+		return fmt.Errorf("预测期数最多 %d 期", maxForecastHorizon)
+	}
+	if q.Window <= 0 {
+		q.Window = 7
+		if weekly {
+			q.Window = 4
+		}
+	}
+	if q.Season <= 0 {
+		q.Season = 7
+		if weekly {
+			q.Season = 52
+		}
+	}
+	if q.Alpha <= 0 || q.Alpha >= 1 {
+		q.Alpha = 0.3
+	}
+	if q.Beta <= 0 || q.Beta >= 1 {
+		q.Beta = 0.1
+	}
+	if q.Gamma <= 0 || q.Gamma >= 1 {
+		q.Gamma = 0.2
+	}
+	if q.Confidence <= 0 {
+		q.Confidence = 0.95
+	}
+	return nil
+}
+
+// confidenceZ 置信水平对应的正态分位数
+func confidenceZ(level float64) float64 {
+	switch {
+	case level >= 0.99:
+		return 2.576
+	case level >= 0.95:
+		return 1.96
+	case level >= 0.9:
+		return 1.645
+	default:
+		return 1.282
+	}
+}
+
+// GetProductForecast 预测单个商品的出库需求及断货日期
+func (s *Service) GetProductForecast(productID uint, q *models.ForecastQuery) (*models.ProductForecast, error) {
+	if err := normalizeForecastQuery(q); err != nil {
+		return nil, err
+	}
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+
+	start, periods := forecastPeriods(q)
+	daily, err := s.repo.GetDailyOutbound(start, productID)
+	if err != nil {
+		return nil, fmt.Errorf("统计出库历史失败: %w", err)
+	}
+
+	series := bucketSeries(daily, start, periods, q.Granularity)
+	forecast := buildForecast(product, series, start, q)
+	for i, v := range series {
+		forecast.History = append(forecast.History, models.PeriodQuantity{
+			Period:   periodStart(start, i, q.Granularity).Format("2006-01-02"),
+			Quantity: models.RoundQty(v, models.MaxQtyPrecision),
+		})
+	}
+	return forecast, nil
+}
+
+// GetStockoutProjections 预测全部启用商品的断货日期 (最早断货的排在前面)
+func (s *Service) GetStockoutProjections(q *models.ForecastQuery) ([]models.ProductForecast, error) {
+	if err := normalizeForecastQuery(q); err != nil {
+		return nil, err
+	}
+
+	start, periods := forecastPeriods(q)
+	daily, err := s.repo.GetDailyOutbound(start, 0)
+	if err != nil {
+		return nil, fmt.Errorf("统计出库历史失败: %w", err)
+	}
+	byProduct := make(map[uint][]models.DailyQuantity)
+	for _, d := range daily {
+		byProduct[d.ProductID] = append(byProduct[d.ProductID], d)
+	}
+
+	products, err := s.repo.GetActiveProductsWithSupplier()
+	if err != nil {
+		return nil, fmt.Errorf("查询商品失败: %w", err)
+	}
+
+	result := make([]models.ProductForecast, 0, len(products))
+	for i := range products {
+		series := bucketSeries(byProduct[products[i].ID], start, periods, q.Granularity)
+		result = append(result, *buildForecast(&products[i], series, start, q))
+	}
+
+	// 有断货日期的按天数升序, 无断货日期的排在最后
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i].DaysUntilStockout, result[j].DaysUntilStockout
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return *a < *b
+	})
+	return result, nil
+}
+
+// forecastPeriods 计算历史序列的起始日期与期数
+// 历史只取完整的期 (截止到昨天), 今天起为预测期, 避免当天未结束的数据拉低预测
+func forecastPeriods(q *models.ForecastQuery) (time.Time, int) {
+	today := truncateDay(time.Now())
+	if q.Granularity == "week" {
+		periods := (q.History + 6) / 7
+		return today.AddDate(0, 0, -7*periods), periods
+	}
+	return today.AddDate(0, 0, -q.History), q.History
+}
+
+// periodStart 第 i 期的起始日期
+func periodStart(start time.Time, i int, granularity string) time.Time {
+	if granularity == "week" {
+		return start.AddDate(0, 0, 7*i)
+	}
+	return start.AddDate(0, 0, i)
+}
+
+// bucketSeries 将按日汇总的出库数量整理为连续序列, 缺失的期补 0
+func bucketSeries(daily []models.DailyQuantity, start time.Time, periods int, granularity string) []float64 {
+	series := make([]float64, periods)
+	for _, d := range daily {
+		day, err := time.ParseInLocation("2006-01-02", d.Date, time.Local)
+		if err != nil {
+			continue
+		}
+		idx := int(day.Sub(start).Hours() / 24)
+		if granularity == "week" {
+			idx /= 7
+		}
+		if idx >= 0 && idx < periods {
+			series[idx] += d.Quantity
+		}
+	}
+	return series
+}
+
+// buildForecast 选择模型、生成预测区间并推算断货日期
+func buildForecast(product *models.Product, series []float64, start time.Time, q *models.ForecastQuery) *models.ProductForecast {
+	var res forecastResult
+	if q.Model == models.ForecastAuto {
+		res = bestForecast(series, q)
+	} else {
+		res = runForecast(q.Model, series, q)
+	}
+
+	fc := &models.ProductForecast{
+		ProductID:    product.ID,
+		SKU:          product.SKU,
+		Name:         product.Name,
+		CurrentStock: product.CurrentStock,
+		Model:        res.model,
+		Granularity:  q.Granularity,
+		MAE:          round2(res.mae),
+	}
+
+	z := confidenceZ(q.Confidence)
+	periodDays := 1.0
+	if q.Granularity == "week" {
+		periodDays = 7
+	}
+	remaining := product.CurrentStock
+	for h, f := range res.forecasts {
+		band := z * res.sigma * math.Sqrt(float64(h+1))
+		fc.Points = append(fc.Points, models.ForecastPoint{
+			Period:   periodStart(start, len(series)+h, q.Granularity).Format("2006-01-02"),
+			Forecast: round2(f),
+			Lower:    round2(math.Max(0, f-band)),
+			Upper:    round2(f + band),
+		})
+
+		// 断货日期: 累计预测需求首次超过当前库存的时点 (期内线性插值)
+		if fc.StockoutDate == nil && f > 0 {
+			if remaining <= f {
+				days := (float64(h) + remaining/f) * periodDays
+				date := truncateDay(time.Now()).Add(time.Duration(days*24) * time.Hour).Format("2006-01-02")
+				days = round2(days)
+				fc.StockoutDate = &date
+				fc.DaysUntilStockout = &days
+			}
+			remaining -= f
+		}
+	}
+	return fc
+}
+
+// bestForecast 依次拟合各模型, 取历史平均绝对误差最小者
+func bestForecast(series []float64, q *models.ForecastQuery) forecastResult {
+	best := runForecast(models.ForecastMovingAverage, series, q)
+	for _, model := range []string{models.ForecastExponential, models.ForecastHoltWinters} {
+		res := runForecast(model, series, q)
+		if res.model == model && res.mae < best.mae {
+			best = res
+		}
+	}
+	return best
+}
+
+// runForecast 运行指定模型; Holt-Winters 历史不足两个季节周期时退化为指数平滑
+func runForecast(model string, series []float64, q *models.ForecastQuery) forecastResult {
+	var res forecastResult
+	switch model {
+	case models.ForecastMovingAverage:
+		res = movingAverage(series, q.Window, q.Horizon)
+	case models.ForecastHoltWinters:
+		if len(series) < 2*q.Season {
+			return runForecast(models.ForecastExponential, series, q)
+		}
+		res = holtWinters(series, q.Season, q.Alpha, q.Beta, q.Gamma, q.Horizon)
+	default:
+		res = exponentialSmoothing(series, q.Alpha, q.Horizon)
+	}
+	res.model = model
+	res.mae, res.sigma = residualStats(series, res.fitted)
+	for i, f := range res.forecasts {
+		res.forecasts[i] = math.Max(0, f)
+	}
+	return res
+}
+
+// movingAverage 移动平均: 预测值为最近 window 期的均值
+func movingAverage(series []float64, window, horizon int) forecastResult {
+	if window > len(series) {
+		window = len(series)
+	}
+	fitted := make([]float64, len(series))
+	var sum float64
+	for i := range series {
+		if i < window {
+			fitted[i] = math.NaN()
+		} else {
+			fitted[i] = sum / float64(window)
+			sum -= series[i-window]
+		}
+		sum += series[i]
+	}
+
+	next := 0.0
+	if window > 0 {
+		next = sum / float64(window)
+	}
+	return forecastResult{fitted: fitted, forecasts: repeat(next, horizon)}
+}
+
+// exponentialSmoothing 一次指数平滑
+func exponentialSmoothing(series []float64, alpha float64, horizon int) forecastResult {
+	fitted := make([]float64, len(series))
+	if len(series) == 0 {
+		return forecastResult{fitted: fitted, forecasts: repeat(0, horizon)}
+	}
+	level := series[0]
+	fitted[0] = math.NaN()
+	for i := 1; i < len(series); i++ {
+		fitted[i] = level
+		level = alpha*series[i] + (1-alpha)*level
+	}
+	return forecastResult{fitted: fitted, forecasts: repeat(level, horizon)}
+}
+
+// holtWinters Holt-Winters 加法模型 (水平 + 趋势 + 季节), 要求至少两个完整季节
+func holtWinters(series []float64, m int, alpha, beta, gamma float64, horizon int) forecastResult {
+	n := len(series)
+	fitted := make([]float64, n)
+
+	// 初始值: 第一季均值为水平, 前两季均值之差为趋势, 第一季各期偏差为季节因子
+	var first, second float64
+	for i := 0; i < m; i++ {
+		first += series[i]
+		second += series[m+i]
+	}
+	first /= float64(m)
+	second /= float64(m)
+	level := first
+	trend := (second - first) / float64(m)
+	season := make([]float64, n+horizon)
+	for i := 0; i < m; i++ {
+		season[i] = series[i] - first
+		fitted[i] = math.NaN()
+	}
+
+	for t := m; t < n; t++ {
+		fitted[t] = level + trend + season[t-m]
+		prevLevel := level
+		level = alpha*(series[t]-season[t-m]) + (1-alpha)*(level+trend)
+		trend = beta*(level-prevLevel) + (1-beta)*trend
+		season[t] = gamma*(series[t]-level) + (1-gamma)*season[t-m]
+	}
+
+	forecasts := make([]float64, horizon)
+	for h := 1; h <= horizon; h++ {
+		forecasts[h-1] = level + float64(h)*trend + season[n-m+(h-1)%m]
+	}
+	return forecastResult{fitted: fitted, forecasts: forecasts}
+}
+
+// residualStats 计算一步预测的平均绝对误差与残差标准差
+func residualStats(series, fitted []float64) (mae, sigma float64) {
+	var absSum, sqSum float64
+	n := 0
+	for i, f := range fitted {
+		if math.IsNaN(f) {
+			continue
+		}
+		e := series[i] - f
+		absSum += math.Abs(e)
+		sqSum += e * e
+		n++
+	}
+	if n == 0 {
+		return math.Inf(1), 0
+	}
+	return absSum / float64(n), math.Sqrt(sqSum / float64(n))
+}
+
+func repeat(v float64, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
+
+func round2(v float64) float64 {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0
+	}
+	return math.Round(v*100) / 100
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"testing"
+
+	"go-cargo/internal/models"
+)
+
+func TestNormalizeForecastQueryLimits(t *testing.T) {
+	cases := []struct {
+		name    string
+		query   models.ForecastQuery
+		wantErr bool
+	}{
+		{"defaults", models.ForecastQuery{}, false},
+		{"max-history", models.ForecastQuery{History: maxForecastHistory}, false},
+		{"max-horizon", models.ForecastQuery{Granularity: "week", Horizon: maxForecastHorizon}, false},
+		{"history-too-large", models.ForecastQuery{History: maxForecastHistory + 1}, true},
+		{"horizon-too-large", models.ForecastQuery{Horizon: 2000000000}, true},
+		{"invalid-model", models.ForecastQuery{Model: "arima"}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := tc.query
+			err := normalizeForecastQuery(&q)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil && (q.History <= 0 || q.Horizon <= 0) {
+				t.Errorf("默认值未填充: history=%d horizon=%d", q.History, q.Horizon)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 幂等键 ====================
+
+// ReserveIdempotencyKey 占用幂等键
+// 键不存在时创建处理中记录并返回 created=true; 已存在时返回已有记录
+func (s *Service) ReserveIdempotencyKey(userID uint, key, method, path, requestHash string) (*models.IdempotencyKey, bool, error) {
+	now := time.Now()
+	if err := s.repo.PurgeExpiredIdempotencyKeys(now); err != nil {
+		return nil, false, fmt.Errorf("清理过期幂等键失败: %w", err)
+	}
+
+	if existing, err := s.repo.GetIdempotencyKey(userID, key); err == nil {
+		return existing, false, nil
+	}
+
+	rec := &models.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		Method:      method,
+		Path:        path,
+		RequestHash: requestHash,
+		Status:      models.IdempotencyProcessing,
+		ExpiresAt:   now.Add(time.Duration(s.cfg.IdempotencyTTLHours) * time.Hour),
+	}
+	if err := s.repo.CreateIdempotencyKey(rec); err != nil {
+		// 并发请求抢先写入了同一个键
+		if existing, getErr := s.repo.GetIdempotencyKey(userID, key); getErr == nil {
+			return existing, false, nil
+		}
+		return nil, false, fmt.Errorf("保存幂等键失败: %w", err)
+	}
+	return rec, true, nil
+}
+
+// CompleteIdempotencyKey 记录首次响应, 供后续重放
+func (s *Service) CompleteIdempotencyKey(rec *models.IdempotencyKey, statusCode int, contentType string, body []byte) error {
+	rec.Status = models.IdempotencyCompleted
+	rec.StatusCode = statusCode
+	rec.ContentType = contentType
+	rec.ResponseBody = body
+	return s.repo.UpdateIdempotencyKey(rec)
+}
+
+// ReleaseIdempotencyKey 释放幂等键 (服务端错误时允许客户端用同一个键重试)
+func (s *Service) ReleaseIdempotencyKey(rec *models.IdempotencyKey) error {
+	return s.repo.DeleteIdempotencyKey(rec.ID)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 商品标识 ====================
+
+// ListProductIdentifiers 获取商品的附加标识
+func (s *Service) ListProductIdentifiers(productID uint) ([]models.ProductIdentifier, error) {
+	if _, err := s.repo.GetProductByID(productID); err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	return s.repo.ListProductIdentifiers(productID)
+}
+
+// CreateProductIdentifier 为商品添加标识
+func (s *Service) CreateProductIdentifier(productID uint, req *models.ProductIdentifierRequest) (*models.ProductIdentifier, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+
+	identifier := &models.ProductIdentifier{ProductID: productID}
+	if err := s.applyIdentifier(identifier, product, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateProductIdentifier(identifier); err != nil {
+		return nil, fmt.Errorf("创建标识失败: %w", err)
+	}
+	return identifier, nil
+}
+
+// UpdateProductIdentifier 更新商品标识
+func (s *Service) UpdateProductIdentifier(productID, id uint, req *models.ProductIdentifierRequest) (*models.ProductIdentifier, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	identifier, err := s.repo.GetProductIdentifier(productID, id)
+	if err != nil {
+		return nil, fmt.Errorf("标识不存在")
+	}
+
+	if err := s.applyIdentifier(identifier, product, req); err != nil {
+		return nil, err
+	}
+	identifier.Supplier = nil
+	if err := s.repo.UpdateProductIdentifier(identifier); err != nil {
+		return nil, fmt.Errorf("更新标识失败: %w", err)
+	}
+	return identifier, nil
+}
+
+// DeleteProductIdentifier 删除商品标识
+func (s *Service) DeleteProductIdentifier(productID, id uint) error {
+	if _, err := s.repo.GetProductIdentifier(productID, id); err != nil {
+		return fmt.Errorf("标识不存在")
+	}
+	return s.repo.DeleteProductIdentifier(id)
+}
+
+// applyIdentifier 校验请求并按类型的唯一性规则写入标识:
+// 备用条码全局唯一且不能与其他商品的主条码重复; 供应商料号须指定供应商, 同一供应商内唯一;
+// 制造商型号在同一商品内不重复
+func (s *Service) applyIdentifier(identifier *models.ProductIdentifier, product *models.Product, req *models.ProductIdentifierRequest) error {
+	value := strings.TrimSpace(req.Value)
+	if value == "" {
+		return fmt.Errorf("标识值不能为空")
+	}
+	if req.SupplierID != nil {
+		if _, err := s.repo.GetSupplierByID(*req.SupplierID); err != nil {
+			return fmt.Errorf("供应商不存在")
+		}
+	}
+
+	var conflict string
+	switch req.Type {
+	case models.IdentifierBarcode:
+		if value == product.Barcode {
+			return fmt.Errorf("条码 %s 已是该商品的主条码", value)
+		}
+		if n, err := s.repo.CountProductsWithBarcode(value, product.ID); err == nil && n > 0 {
+			return fmt.Errorf("条码 %s 已被其他商品用作主条码", value)
+		}
+		conflict = fmt.Sprintf("条码 %s 已登记", value)
+	case models.IdentifierSupplierPart:
+		if req.SupplierID == nil {
+			return fmt.Errorf("供应商料号须指定供应商")
+		}
+		conflict = fmt.Sprintf("该供应商的料号 %s 已登记", value)
+	case models.IdentifierManufacturerPart:
+		conflict = fmt.Sprintf("型号 %s 已登记", value)
+	default:
+		return fmt.Errorf("不支持的标识类型: %s", req.Type)
+	}
+
+	key := identifierKey(req.Type, value, product.ID, req.SupplierID)
+	if existing, err := s.repo.GetIdentifierByUniqueKey(key); err == nil && existing.ID != identifier.ID {
+		return fmt.Errorf("%s (商品ID %d)", conflict, existing.ProductID)
+	}
+
+	identifier.Type = req.Type
+	identifier.Value = value
+	identifier.SupplierID = req.SupplierID
+	identifier.Notes = req.Notes
+	identifier.UniqueKey = key
+	return nil
+}
+
+// identifierKey 生成标识唯一键, 唯一性范围随类型不同
+func identifierKey(identifierType, value string, productID uint, supplierID *uint) string {
+	switch identifierType {
+	case models.IdentifierBarcode:
+		return identifierType + ":" + value
+	case models.IdentifierSupplierPart:
+		return fmt.Sprintf("%s:%d:%s", identifierType, *supplierID, value)
+	}
+	return fmt.Sprintf("%s:%d:%s", identifierType, productID, value)
+}
+
+// checkBarcodeNotAlternate 主条码不能与其他商品的备用条码重复
+func (s *Service) checkBarcodeNotAlternate(barcode string, productID uint) error {
+	if barcode == "" {
+		return nil
+	}
+	key := identifierKey(models.IdentifierBarcode, barcode, 0, nil)
+	if existing, err := s.repo.GetIdentifierByUniqueKey(key); err == nil && existing.ProductID != productID {
+		return fmt.Errorf("条码 %s 已登记为其他商品的备用条码 (商品ID %d)", barcode, existing.ProductID)
+	}
+	return nil
+}
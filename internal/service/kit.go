@@ -0,0 +1,308 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 组合商品 ====================
+
+// GetKitBOM 获取组合商品的物料清单及可组装数量
+func (s *Service) GetKitBOM(kitID uint) (*models.KitBOM, error) {
+	kit, err := s.repo.GetProductByID(kitID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	components, err := s.repo.ListKitComponents(kitID)
+	if err != nil {
+		return nil, fmt.Errorf("查询物料清单失败: %w", err)
+	}
+	return &models.KitBOM{
+		KitID:      kit.ID,
+		KitType:    kit.KitType,
+		Components: components,
+		Buildable:  buildableQty(kit, components),
+	}, nil
+}
+
+// SetKitBOM 设置组合商品的物料清单 (整体替换)
+// 组件不能是组合商品本身、多规格款式或虚拟组合商品; 设为虚拟组合时商品须无库存
+func (s *Service) SetKitBOM(kitID uint, req *models.KitBOMRequest) (*models.KitBOM, error) {
+	kit, err := s.repo.GetProductByID(kitID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	if kit.HasVariants {
+		return nil, fmt.Errorf("多规格款式不能设为组合商品，请为具体规格设置物料清单")
+	}
+	if req.KitType == models.KitTypeVirtual && kit.CurrentStock != 0 {
+		return nil, fmt.Errorf("商品 %s 有库存 %s，请先清零再设为虚拟组合", kit.SKU, formatQty(kit.CurrentStock))
+	}
+
+	components := make([]models.KitComponent, 0, len(req.Components))
+	seen := make(map[uint]bool, len(req.Components))
+	for _, line := range req.Components {
+		if line.ComponentID == kitID {
+			return nil, fmt.Errorf("组合商品不能包含自身")
+		}
+		if seen[line.ComponentID] {
+			return nil, fmt.Errorf("组件 %d 重复", line.ComponentID)
+		}
+		seen[line.ComponentID] = true
+		component, err := s.repo.GetProductByID(line.ComponentID)
+		if err != nil {
+			return nil, fmt.Errorf("组件商品 %d 不存在", line.ComponentID)
+		}
+		if component.HasVariants || component.KitType == models.KitTypeVirtual {
+			return nil, fmt.Errorf("组件 %s 不持有库存，不能作为组件", component.SKU)
+		}
+		if err := checkQty(component, line.Quantity); err != nil {
+			return nil, err
+		}
+		components = append(components, models.KitComponent{
+			KitID:       kitID,
+			ComponentID: line.ComponentID,
+			Quantity:    line.Quantity,
+			Component:   component,
+		})
+	}
+
+	kit.KitType = req.KitType
+	if err := s.repo.SaveKitBOM(kit, components); err != nil {
+		return nil, fmt.Errorf("保存物料清单失败: %w", err)
+	}
+	return &models.KitBOM{
+		KitID:      kit.ID,
+		KitType:    kit.KitType,
+		Components: components,
+		Buildable:  buildableQty(kit, components),
+	}, nil
+}
+
+// DeleteKitBOM 删除物料清单, 商品恢复为普通商品
+func (s *Service) DeleteKitBOM(kitID uint) error {
+	kit, err := s.repo.GetProductByID(kitID)
+	if err != nil {
+		return fmt.Errorf("商品不存在")
+	}
+	if kit.KitType == "" {
+		return fmt.Errorf("商品 %s 不是组合商品", kit.SKU)
+	}
+	kit.KitType = ""
+	return s.repo.SaveKitBOM(kit, nil)
+}
+
+// AssembleKit 组装: 按物料清单扣减组件库存并增加组合商品库存
+func (s *Service) AssembleKit(kitID uint, req *models.KitAssemblyRequest, operatorID uint, operatorName string) (*models.KitAssembly, error) {
+	return s.runKitAssembly(models.KitAssemble, kitID, req, operatorID, operatorName)
+}
+
+// DisassembleKit 拆卸: 扣减组合商品库存并按物料清单退回组件库存
+func (s *Service) DisassembleKit(kitID uint, req *models.KitAssemblyRequest, operatorID uint, operatorName string) (*models.KitAssembly, error) {
+	return s.runKitAssembly(models.KitDisassemble, kitID, req, operatorID, operatorName)
+}
+
+// runKitAssembly 校验组合商品与各组件的库存后, 在单一事务中写入组装单及全部库存记录
+func (s *Service) runKitAssembly(assemblyType string, kitID uint, req *models.KitAssemblyRequest, operatorID uint, operatorName string) (*models.KitAssembly, error) {
+	kit, err := s.repo.GetProductByID(kitID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	if kit.KitType != models.KitTypeAssembled {
+		return nil, fmt.Errorf("商品 %s 不是实物组合商品，无法组装或拆卸", kit.SKU)
+	}
+	if err := checkQty(kit, req.Quantity); err != nil {
+		return nil, err
+	}
+	components, err := s.repo.ListKitComponents(kitID)
+	if err != nil {
+		return nil, fmt.Errorf("查询物料清单失败: %w", err)
+	}
+	if len(components) == 0 {
+		return nil, fmt.Errorf("商品 %s 未设置物料清单", kit.SKU)
+	}
+
+	assemble := assemblyType == models.KitAssemble
+	recordType, verb := models.StockAssemble, "组装"
+	if !assemble {
+		recordType, verb = models.StockDisassemble, "拆卸"
+	}
+	assembly := &models.KitAssembly{
+		KitID:        kitID,
+		Type:         assemblyType,
+		Quantity:     req.Quantity,
+		Notes:        req.Notes,
+		OperatorID:   operatorID,
+		OperatorName: operatorName,
+	}
+	notes := fmt.Sprintf("%s %s × %s", verb, kit.SKU, formatQty(req.Quantity))
+	if req.Notes != "" {
+		notes += ": " + req.Notes
+	}
+
+	// 组合商品的单位成本按组件成本价汇总
+	var kitCost float64
+	records := make([]*models.InventoryRecord, 0, len(components)+1)
+	for _, c := range components {
+		component := c.Component
+		if component == nil {
+			return nil, fmt.Errorf("组件商品 %d 不存在", c.ComponentID)
+		}
+		if err := s.checkNotFrozen(component.ID); err != nil {
+			return nil, err
+		}
+		quantity := req.Quantity * c.Quantity
+		if err := checkQty(component, quantity); err != nil {
+			return nil, err
+		}
+		quantity = component.RoundQty(quantity)
+		delta := quantity
+		if assemble {
+			if component.CurrentStock < quantity {
+				return nil, fmt.Errorf("组件 %s 库存不足，当前库存: %s，需要: %s", component.SKU, formatQty(component.CurrentStock), formatQty(quantity))
+			}
+			delta = -quantity
+		}
+		kitCost += c.Quantity * component.CostPrice
+		records = append(records, &models.InventoryRecord{
+			ProductID:    component.ID,
+			Type:         recordType,
+			Quantity:     quantity,
+			BeforeQty:    component.CurrentStock,
+			AfterQty:     component.RoundQty(component.CurrentStock + delta),
+			UnitCost:     component.CostPrice,
+			TotalCost:    quantity * component.CostPrice,
+			Notes:        notes,
+			OperatorID:   operatorID,
+			OperatorName: operatorName,
+		})
+	}
+
+	if err := s.checkNotFrozen(kitID); err != nil {
+		return nil, err
+	}
+	delta := req.Quantity
+	if !assemble {
+		if kit.CurrentStock < req.Quantity {
+			return nil, fmt.Errorf("库存不足，当前库存: %s，请求拆卸: %s", formatQty(kit.CurrentStock), formatQty(req.Quantity))
+		}
+		delta = -req.Quantity
+	}
+	kitRecord := &models.InventoryRecord{
+		ProductID:    kitID,
+		Type:         recordType,
+		Quantity:     req.Quantity,
+		BeforeQty:    kit.CurrentStock,
+		AfterQty:     kit.RoundQty(kit.CurrentStock + delta),
+		UnitCost:     kitCost,
+		TotalCost:    req.Quantity * kitCost,
+		Notes:        notes,
+		OperatorID:   operatorID,
+		OperatorName: operatorName,
+	}
+	// 组装时先扣组件再入组合商品, 拆卸时先出组合商品再退组件
+	if assemble {
+		records = append(records, kitRecord)
+	} else {
+		records = append([]*models.InventoryRecord{kitRecord}, records...)
+	}
+
+	// 库存记录以组装单号为关联单号
+	err = s.createWithDocumentNo(func() error {
+		assembly.AssemblyNo = "KA-" + strings.Replace(time.Now().Format("20060102150405.000"), ".", "", 1)
+		for _, r := range records {
+			r.ReferenceNo = assembly.AssemblyNo
+		}
+		return s.repo.CreateKitAssembly(assembly, records)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s失败: %w", verb, err)
+	}
+	assembly.Records = make([]models.InventoryRecord, len(records))
+	for i, r := range records {
+		assembly.Records[i] = *r
+	}
+	return assembly, nil
+}
+
+// ListKitAssemblies 获取组装单列表
+func (s *Service) ListKitAssemblies(query *models.PaginationQuery, kitID *uint, assemblyType string) ([]models.KitAssembly, int64, error) {
+	return s.repo.ListKitAssemblies(query, kitID, assemblyType)
+}
+
+// GetKitAssembly 获取组装单详情 (含库存记录)
+func (s *Service) GetKitAssembly(id uint) (*models.KitAssembly, error) {
+	return s.repo.GetKitAssemblyByID(id)
+}
+
+// virtualKitStockOut 虚拟组合商品出库: 按物料清单在单一事务中扣减各组件库存
+func (s *Service) virtualKitStockOut(kit *models.Product, req *models.StockOutRequest, operatorID uint, operatorName string) error {
+	components, err := s.repo.ListKitComponents(kit.ID)
+	if err != nil {
+		return fmt.Errorf("查询物料清单失败: %w", err)
+	}
+	if len(components) == 0 {
+		return fmt.Errorf("商品 %s 未设置物料清单", kit.SKU)
+	}
+	qty, err := s.toBaseUnit(kit, req.Unit, req.Quantity, 0)
+	if err != nil {
+		return err
+	}
+
+	notes := fmt.Sprintf("组合商品 %s × %s 出库", kit.SKU, formatQty(qty.base))
+	if req.Notes != "" {
+		notes += ": " + req.Notes
+	}
+	records := make([]*models.InventoryRecord, 0, len(components))
+	for _, c := range components {
+		component := c.Component
+		if component == nil {
+			return fmt.Errorf("组件商品 %d 不存在", c.ComponentID)
+		}
+		if err := s.checkNotFrozen(component.ID); err != nil {
+			return err
+		}
+		quantity := qty.base * c.Quantity
+		if err := checkQty(component, quantity); err != nil {
+			return err
+		}
+		quantity = component.RoundQty(quantity)
+		if component.CurrentStock < quantity {
+			return fmt.Errorf("组件 %s 库存不足，当前库存: %s，需要: %s", component.SKU, formatQty(component.CurrentStock), formatQty(quantity))
+		}
+		records = append(records, &models.InventoryRecord{
+			ProductID:    component.ID,
+			Type:         models.StockOut,
+			Quantity:     quantity,
+			BeforeQty:    component.CurrentStock,
+			AfterQty:     component.RoundQty(component.CurrentStock - quantity),
+			ReferenceNo:  req.ReferenceNo,
+			LotNo:        req.LotNo,
+			Notes:        notes,
+			OperatorID:   operatorID,
+			OperatorName: operatorName,
+			SourceType:   models.SourceKit,
+			SourceID:     kit.ID,
+		})
+	}
+	return s.repo.BatchStockOperation(records, nil)
+}
+
+// buildableQty 按组件当前库存计算最多可组装的数量 (按组合商品精度向下取整)
+func buildableQty(kit *models.Product, components []models.KitComponent) float64 {
+	if len(components) == 0 {
+		return 0
+	}
+	buildable := math.Inf(1)
+	for _, c := range components {
+		if c.Component == nil || c.Quantity <= 0 {
+			return 0
+		}
+		buildable = math.Min(buildable, c.Component.CurrentStock/c.Quantity)
+	}
+	return models.FloorQty(math.Max(buildable, 0), kit.QtyPrecision)
+}
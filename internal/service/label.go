@@ -0,0 +1,212 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go-cargo/internal/export"
+	"go-cargo/internal/models"
+)
+
+// ==================== 条码与标签 ====================
+
+// maxLabels 单次打印的标签数上限
+const maxLabels = 2000
+
+// ProductBarcodeImage 生成商品条码图片, 返回图片内容与 MIME 类型
+func (s *Service) ProductBarcodeImage(id uint, q *models.BarcodeQuery) ([]byte, string, error) {
+	product, err := s.repo.GetProductByID(id)
+	if err != nil {
+		return nil, "", fmt.Errorf("商品不存在")
+	}
+	sym, err := parseSymbology(q.Symbology)
+	if err != nil {
+		return nil, "", err
+	}
+	content, err := barcodeContent(product, q.Source)
+	if err != nil {
+		return nil, "", err
+	}
+	bc, err := export.EncodeBarcode(sym, content)
+	if err != nil {
+		return nil, "", err
+	}
+
+	scale := q.Scale
+	if scale <= 0 {
+		scale = 2
+		if sym == export.QR {
+			scale = 4
+		}
+	}
+	if scale > 20 || q.Height > 1000 {
+		return nil, "", fmt.Errorf("图片尺寸过大")
+	}
+	switch q.Format {
+	case "", "png":
+		data, err := export.BarcodePNG(bc, scale, q.Height)
+		if err != nil {
+			return nil, "", fmt.Errorf("生成图片失败: %w", err)
+		}
+		return data, "image/png", nil
+	case "svg":
+		return export.BarcodeSVG(bc, scale, q.Height), "image/svg+xml", nil
+	}
+	return nil, "", fmt.Errorf("图片格式仅支持 png / svg")
+}
+
+// LabelLayouts 内置标签纸版式
+func (s *Service) LabelLayouts() []export.LabelLayout {
+	return export.LabelLayouts()
+}
+
+// ProductLabels 打印商品标签: 名称、SKU 与售价、条码
+func (s *Service) ProductLabels(q *models.LabelQuery) ([]byte, string, error) {
+	ids, err := parseIDList(q.IDs)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(ids) == 0 {
+		return nil, "", fmt.Errorf("请指定商品 ids")
+	}
+	copies := q.Copies
+	if copies <= 0 {
+		copies = 1
+	}
+	if len(ids)*copies > maxLabels {
+		return nil, "", fmt.Errorf("单次最多打印 %d 个标签", maxLabels)
+	}
+	sym, err := parseSymbology(q.Symbology)
+	if err != nil {
+		return nil, "", err
+	}
+	products, err := s.repo.GetProductsByIDs(ids)
+	if err != nil {
+		return nil, "", fmt.Errorf("查询商品失败: %w", err)
+	}
+	if len(products) == 0 {
+		return nil, "", fmt.Errorf("商品不存在")
+	}
+
+	var labels []export.Label
+	for i := range products {
+		p := &products[i]
+		content, err := barcodeContent(p, q.Source)
+		if err != nil {
+			return nil, "", fmt.Errorf("商品 %s: %w", p.SKU, err)
+		}
+		label := export.Label{
+			Title:     p.Name,
+			Subtitle:  fmt.Sprintf("%s  ¥%s", p.SKU, formatMoney(p.SellingPrice)),
+			Code:      content,
+			Symbology: sym,
+		}
+		for c := 0; c < copies; c++ {
+			labels = append(labels, label)
+		}
+	}
+	return s.renderLabels(labels, q, "product-labels")
+}
+
+// LocationLabels 打印库位标签; 指定 codes 时按给定编码, 否则按前缀取商品已使用的库位
+func (s *Service) LocationLabels(q *models.LabelQuery) ([]byte, string, error) {
+	sym, err := parseSymbology(q.Symbology)
+	if err != nil {
+		return nil, "", err
+	}
+	var codes []string
+	for _, code := range strings.Split(q.Codes, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		if codes, err = s.repo.GetProductLocations(q.Prefix); err != nil {
+			return nil, "", fmt.Errorf("查询库位失败: %w", err)
+		}
+	}
+	if len(codes) == 0 {
+		return nil, "", fmt.Errorf("没有可打印的库位")
+	}
+	if len(codes) > maxLabels {
+		return nil, "", fmt.Errorf("单次最多打印 %d 个标签", maxLabels)
+	}
+
+	labels := make([]export.Label, len(codes))
+	for i, code := range codes {
+		labels[i] = export.Label{Title: code, Subtitle: "库位", Code: code, Symbology: sym}
+	}
+	return s.renderLabels(labels, q, "location-labels")
+}
+
+// renderLabels 按格式输出 A4 标签纸 PDF 或热敏 ZPL, 返回内容与文件名
+func (s *Service) renderLabels(labels []export.Label, q *models.LabelQuery, name string) ([]byte, string, error) {
+	switch q.Format {
+	case "", "pdf":
+		layout, err := export.ResolveLabelLayout(q.Layout, q.Columns, q.Rows)
+		if err != nil {
+			return nil, "", err
+		}
+		data, err := s.renderer.LabelSheetPDF(labels, layout, q.Skip, q.Border)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, name + ".pdf", nil
+	case "zpl":
+		data, err := export.LabelsZPL(labels, export.ZPLOptions{WidthMM: q.WidthMM, HeightMM: q.HeightMM, DPI: q.DPI})
+		if err != nil {
+			return nil, "", err
+		}
+		return data, name + ".zpl", nil
+	}
+	return nil, "", fmt.Errorf("标签格式仅支持 pdf / zpl")
+}
+
+// parseSymbology 解析条码类型, 默认 Code128
+func parseSymbology(s string) (export.Symbology, error) {
+	if s == "" {
+		return export.Code128, nil
+	}
+	sym := export.Symbology(strings.ToLower(s))
+	if !sym.Valid() {
+		return "", fmt.Errorf("条码类型仅支持 code128 / ean13 / qr")
+	}
+	return sym, nil
+}
+
+// barcodeContent 取商品条码内容: source 为 sku 时取 SKU, 为 barcode 时取条码, 为空时优先条码
+func barcodeContent(p *models.Product, source string) (string, error) {
+	switch source {
+	case "sku":
+		return p.SKU, nil
+	case "barcode":
+		if p.Barcode == "" {
+			return "", fmt.Errorf("商品未设置条码")
+		}
+		return p.Barcode, nil
+	case "":
+		if p.Barcode != "" {
+			return p.Barcode, nil
+		}
+		return p.SKU, nil
+	}
+	return "", fmt.Errorf("条码来源仅支持 barcode / sku")
+}
+
+// parseIDList 解析逗号分隔的ID列表
+func parseIDList(s string) ([]uint, error) {
+	var ids []uint
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("无效的ID: %s", part)
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 库存台账 ====================
+
+// GetProductLedger 商品库存台账: 期初结存、期间每笔变动及滚动结存
+// 期初结存由记录链的操作前/后数量推算, 不依赖当前库存
+func (s *Service) GetProductLedger(productID uint, q *models.LedgerQuery) (*models.ProductLedger, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	from, to, err := parseDateRange(q.From, q.To, 30)
+	if err != nil {
+		return nil, err
+	}
+
+	opening, err := s.balanceAsOf(product, from)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.repo.GetProductRecordsBetween(productID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("查询库存记录失败: %w", err)
+	}
+
+	ledger := &models.ProductLedger{
+		ProductID:      product.ID,
+		SKU:            product.SKU,
+		Name:           product.Name,
+		From:           from.Format("2006-01-02"),
+		To:             to.AddDate(0, 0, -1).Format("2006-01-02"),
+		OpeningBalance: opening,
+		Entries:        make([]models.LedgerEntry, 0, len(records)),
+	}
+	balance := opening
+	for _, rec := range records {
+		change := models.RoundQty(rec.AfterQty-rec.BeforeQty, models.MaxQtyPrecision)
+		entry := models.LedgerEntry{
+			RecordID:     rec.ID,
+			Type:         rec.Type,
+			Quantity:     rec.Quantity,
+			Change:       change,
+			BeforeQty:    rec.BeforeQty,
+			AfterQty:     rec.AfterQty,
+			ReferenceNo:  rec.ReferenceNo,
+			ReasonCode:   rec.ReasonCode,
+			OperatorName: rec.OperatorName,
+			Voided:       rec.Voided,
+			Gap:          !qtyEqual(rec.BeforeQty, balance),
+			CreatedAt:    rec.CreatedAt,
+		}
+		balance = models.RoundQty(balance+change, models.MaxQtyPrecision)
+		entry.Balance = balance
+		if change > 0 {
+			ledger.TotalIn += change
+		} else {
+			ledger.TotalOut -= change
+		}
+		ledger.Entries = append(ledger.Entries, entry)
+	}
+	ledger.TotalIn = models.RoundQty(ledger.TotalIn, models.MaxQtyPrecision)
+	ledger.TotalOut = models.RoundQty(ledger.TotalOut, models.MaxQtyPrecision)
+	ledger.ClosingBalance = balance
+	return ledger, nil
+}
+
+// balanceAsOf 推算单个商品在某时间点的库存
+func (s *Service) balanceAsOf(product *models.Product, at time.Time) (float64, error) {
+	balances, err := s.repo.GetBalancesAsOf(at, product.ID)
+	if err != nil {
+		return 0, fmt.Errorf("推算期初库存失败: %w", err)
+	}
+	if qty, ok := balances[product.ID]; ok {
+		return qty, nil
+	}
+	return product.CurrentStock, nil
+}
+
+// GetStockAsOf 指定日期日终的全部商品库存
+func (s *Service) GetStockAsOf(dateStr string) (*models.StockAsOfReport, error) {
+	day := truncateDay(time.Now())
+	if dateStr != "" {
+		t, err := time.ParseInLocation("2006-01-02", dateStr, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("无效的日期: %s", dateStr)
+		}
+		day = t
+	}
+	at := day.AddDate(0, 0, 1)
+
+	products, err := s.repo.GetProductsExistingAt(at)
+	if err != nil {
+		return nil, fmt.Errorf("查询商品失败: %w", err)
+	}
+	balances, err := s.repo.GetBalancesAsOf(at, 0)
+	if err != nil {
+		return nil, fmt.Errorf("推算库存失败: %w", err)
+	}
+
+	report := &models.StockAsOfReport{
+		Date:  day.Format("2006-01-02"),
+		Items: make([]models.StockAsOfItem, 0, len(products)),
+	}
+	for _, p := range products {
+		qty, ok := balances[p.ID]
+		if !ok {
+			// 前后均无记录, 库存从未变动
+			qty = p.CurrentStock
+		}
+		item := models.StockAsOfItem{
+			ProductID:  p.ID,
+			SKU:        p.SKU,
+			Name:       p.Name,
+			Quantity:   qty,
+			CostPrice:  p.CostPrice,
+			StockValue: round2(qty * p.CostPrice),
+		}
+		if p.Category != nil {
+			item.CategoryName = p.Category.Name
+		}
+		report.TotalQty += qty
+		report.TotalValue += item.StockValue
+		report.Items = append(report.Items, item)
+	}
+	report.TotalQty = models.RoundQty(report.TotalQty, models.MaxQtyPrecision)
+	report.TotalValue = round2(report.TotalValue)
+	return report, nil
+}
+
+// CheckLedgerConsistency 检查记录链: 相邻记录数量衔接、单条记录数量差、最终数量与当前库存
+func (s *Service) CheckLedgerConsistency() (*models.LedgerCheckResult, error) {
+	products, err := s.repo.GetProductsExistingAt(time.Now().Add(time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("查询商品失败: %w", err)
+	}
+	byID := make(map[uint]*models.Product, len(products))
+	for i := range products {
+		byID[products[i].ID] = &products[i]
+	}
+
+	result := &models.LedgerCheckResult{Issues: []models.LedgerIssue{}}
+	issue := func(p *models.Product, kind string, recordID uint, expected, actual float64, msg string) {
+		result.Issues = append(result.Issues, models.LedgerIssue{
+			ProductID: p.ID, SKU: p.SKU, Name: p.Name, Kind: kind,
+			RecordID: recordID, Expected: expected, Actual: actual, Message: msg,
+		})
+	}
+
+	lastAfter := make(map[uint]float64, len(products))
+	err = s.repo.WalkInventoryRecords(func(records []models.InventoryRecord) error {
+		for _, rec := range records {
+			result.CheckedRecords++
+			p, ok := byID[rec.ProductID]
+			if !ok {
+				continue // 商品已删除
+			}
+			if prev, seen := lastAfter[rec.ProductID]; seen && !qtyEqual(rec.BeforeQty, prev) {
+				issue(p, models.LedgerIssueGap, rec.ID, prev, rec.BeforeQty,
+					fmt.Sprintf("记录 #%d 操作前数量与上一条记录的操作后数量不一致", rec.ID))
+			}
+			if want, ok := expectedChange(rec); ok && !qtyEqual(rec.AfterQty-rec.BeforeQty, want) {
+				issue(p, models.LedgerIssueDelta, rec.ID, want, models.RoundQty(rec.AfterQty-rec.BeforeQty, models.MaxQtyPrecision),
+					fmt.Sprintf("记录 #%d 数量变化与类型 %s、数量 %s 不符", rec.ID, rec.Type, formatQty(rec.Quantity)))
+			}
+			lastAfter[rec.ProductID] = rec.AfterQty
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("遍历库存记录失败: %w", err)
+	}
+
+	for i := range products {
+		p := &products[i]
+		last, seen := lastAfter[p.ID]
+		switch {
+		case seen && !qtyEqual(last, p.CurrentStock):
+			issue(p, models.LedgerIssueCurrent, 0, last, p.CurrentStock, "最后一条记录的操作后数量与当前库存不一致")
+		case !seen && p.CurrentStock != 0:
+			issue(p, models.LedgerIssueNoRecord, 0, 0, p.CurrentStock, "无库存记录但当前库存不为零")
+		}
+	}
+	result.CheckedProducts = len(products)
+	result.IssueCount = len(result.Issues)
+	return result, nil
+}
+
+// expectedChange 按记录类型推算应有的数量变化; 调整、冲销与组装/拆卸方向不定, 仅校验绝对值; 移库不改变总库存
+func expectedChange(rec models.InventoryRecord) (float64, bool) {
+	switch rec.Type {
+	case models.StockIn:
+		return rec.Quantity, true
+	case models.StockOut, models.StockReturn:
+		return -rec.Quantity, true
+	case models.StockMove:
+		return 0, true
+	case models.StockAdjust, models.StockVoid, models.StockAssemble, models.StockDisassemble:
+		if diff := rec.AfterQty - rec.BeforeQty; qtyEqual(diff, rec.Quantity) || qtyEqual(diff, -rec.Quantity) {
+			return models.RoundQty(diff, models.MaxQtyPrecision), true
+		}
+		return rec.Quantity, true
+	}
+	return 0, false
+}
+
+// qtyEqual 按数量最小精度比较, 忽略浮点误差
+func qtyEqual(a, b float64) bool {
+	return models.RoundQty(a-b, models.MaxQtyPrecision) == 0
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 库位 ====================
+
+// defaultPutawayLimit 上架建议默认返回的货位数
+const defaultPutawayLimit = 5
+
+// ListLocations 获取库位列表
+func (s *Service) ListLocations(query *models.PaginationQuery, filter *models.LocationQuery) ([]models.Location, int64, error) {
+	return s.repo.ListLocations(query, filter)
+}
+
+// GetLocation 获取库位详情
+func (s *Service) GetLocation(id uint) (*models.Location, error) {
+	return s.repo.GetLocationByID(id)
+}
+
+// CreateLocation 创建库位; 层级为父库位的下一层级, 无父库位时为仓库, 类型未指定时继承父库位
+func (s *Service) CreateLocation(req *models.LocationRequest) (*models.Location, error) {
+	code, err := locationCode(req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	location := &models.Location{
+		Level:     models.LocationWarehouse,
+		Code:      code,
+		Path:      code,
+		Name:      req.Name,
+		Type:      req.Type,
+		Capacity:  req.Capacity,
+		Status:    1,
+		SortOrder: req.SortOrder,
+	}
+	if req.Status != 0 {
+		location.Status = req.Status
+	}
+	if req.ParentID != nil {
+		parent, err := s.repo.GetLocationByID(*req.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("上级库位不存在")
+		}
+		if parent.Level == models.LocationBin {
+			return nil, fmt.Errorf("货位 %s 下不能再建库位", parent.Path)
+		}
+		location.ParentID = &parent.ID
+		location.Level = models.LocationLevels[indexOf(models.LocationLevels, parent.Level)+1]
+		location.Path = parent.Path + "-" + code
+		if location.Type == "" {
+			location.Type = parent.Type
+		}
+	}
+	if location.Type == "" {
+		location.Type = models.LocationTypePick
+	}
+	if _, err := s.repo.GetLocationByPath(location.Path); err == nil {
+		return nil, fmt.Errorf("库位 %s 已存在", location.Path)
+	}
+
+	if err := s.repo.CreateLocation(location); err != nil {
+		return nil, err
+	}
+	return s.repo.GetLocationByID(location.ID)
+}
+
+// UpdateLocation 更新库位; 上级库位不能变更, 编码变更时下级库位的完整编码随之更新
+func (s *Service) UpdateLocation(id uint, req *models.LocationRequest) (*models.Location, error) {
+	location, err := s.repo.GetLocationByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("库位不存在")
+	}
+	if req.ParentID != nil && (location.ParentID == nil || *req.ParentID != *location.ParentID) {
+		return nil, fmt.Errorf("不支持变更上级库位")
+	}
+	code, err := locationCode(req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	oldPath := location.Path
+	if code != location.Code {
+		location.Path = strings.TrimSuffix(oldPath, location.Code) + code
+		if _, err := s.repo.GetLocationByPath(location.Path); err == nil {
+			return nil, fmt.Errorf("库位 %s 已存在", location.Path)
+		}
+	}
+	if location.Used != nil && req.Capacity > 0 && req.Capacity < *location.Used {
+		return nil, fmt.Errorf("货位已用 %s，容量不能小于已用量", formatQty(*location.Used))
+	}
+
+	location.Code = code
+	location.Name = req.Name
+	if req.Type != "" {
+		location.Type = req.Type
+	}
+	location.Capacity = req.Capacity
+	if req.Status != 0 {
+		location.Status = req.Status
+	}
+	location.SortOrder = req.SortOrder
+	location.Used = nil
+
+	if err := s.repo.UpdateLocation(location, oldPath); err != nil {
+		return nil, err
+	}
+	return s.repo.GetLocationByID(id)
+}
+
+// DeleteLocation 删除库位, 仅限无下级库位且无库存的库位
+func (s *Service) DeleteLocation(id uint) error {
+	location, err := s.repo.GetLocationByID(id)
+	if err != nil {
+		return fmt.Errorf("库位不存在")
+	}
+	if count, err := s.repo.CountChildLocations(id); err == nil && count > 0 {
+		return fmt.Errorf("库位 %s 下还有 %d 个下级库位，请先删除", location.Path, count)
+	}
+	if count, err := s.repo.CountLocationStock(location.Path); err == nil && count > 0 {
+		return fmt.Errorf("库位 %s 中还有库存，请先移出", location.Path)
+	}
+	return s.repo.DeleteLocation(id)
+}
+
+// GetLocationStock 获取库位及其全部下级货位中的库存
+func (s *Service) GetLocationStock(id uint) ([]models.BinStock, error) {
+	location, err := s.repo.GetLocationByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("库位不存在")
+	}
+	return s.repo.ListLocationBinStocks(location.Path)
+}
+
+// GetProductBins 获取商品的货位库存分布
+func (s *Service) GetProductBins(productID uint) (*models.ProductBinStock, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	bins, err := s.repo.ListProductBinStocks(productID)
+	if err != nil {
+		return nil, err
+	}
+	var binned float64
+	for _, b := range bins {
+		binned += b.Quantity
+	}
+	return &models.ProductBinStock{
+		ProductID:    product.ID,
+		CurrentStock: product.CurrentStock,
+		Unallocated:  models.RoundQty(product.CurrentStock-binned, models.MaxQtyPrecision),
+		Bins:         bins,
+	}, nil
+}
+
+// MoveStock 货位间移库, 商品总库存不变; 来源或目标为空时表示未分配库位的库存
+func (s *Service) MoveStock(req *models.StockMoveRequest, operatorID uint, operatorName string) (*models.InventoryRecord, error) {
+	if req.FromLocationID == nil && req.ToLocationID == nil {
+		return nil, fmt.Errorf("来源货位与目标货位不能同时为空")
+	}
+	if req.FromLocationID != nil && req.ToLocationID != nil && *req.FromLocationID == *req.ToLocationID {
+		return nil, fmt.Errorf("来源货位与目标货位相同")
+	}
+
+	product, err := s.repo.GetProductByID(req.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	if err := checkStockable(product); err != nil {
+		return nil, err
+	}
+	if err := s.checkNotFrozen(req.ProductID); err != nil {
+		return nil, err
+	}
+	if err := checkQty(product, req.Quantity); err != nil {
+		return nil, err
+	}
+
+	if req.FromLocationID != nil {
+		if err := s.checkBin(*req.FromLocationID, req.ProductID, -req.Quantity, 0, 0); err != nil {
+			return nil, err
+		}
+	} else {
+		binned, err := s.repo.GetBinnedQty(req.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if unallocated := product.RoundQty(product.CurrentStock - binned); unallocated < req.Quantity {
+			return nil, fmt.Errorf("未分配库位的库存不足，当前: %s，请求移库: %s", formatQty(unallocated), formatQty(req.Quantity))
+		}
+	}
+	if req.ToLocationID != nil {
+		if err := s.checkBin(*req.ToLocationID, req.ProductID, req.Quantity, 0, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	record := &models.InventoryRecord{
+		ProductID:    req.ProductID,
+		Type:         models.StockMove,
+		Quantity:     req.Quantity,
+		LocationID:   req.FromLocationID,
+		ToLocationID: req.ToLocationID,
+		ReferenceNo:  req.ReferenceNo,
+		Notes:        req.Notes,
+		OperatorID:   operatorID,
+		OperatorName: operatorName,
+	}
+	if err := s.repo.MoveStock(record); err != nil {
+		return nil, fmt.Errorf("移库失败: %w", err)
+	}
+	return record, nil
+}
+
+// SuggestPutaway 为入库数量建议上架货位 (数量按录入单位换算为基本单位)
+// 已存放该商品且容量足够的货位优先, 其次为空货位; 同类货位中拣货位优先, 隔离位与停用货位不参与
+func (s *Service) SuggestPutaway(productID uint, quantity float64, unit string, limit int) ([]models.PutawaySuggestion, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	if err := checkStockable(product); err != nil {
+		return nil, err
+	}
+	qty, err := s.toBaseUnit(product, unit, quantity, 0)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultPutawayLimit
+	}
+
+	bins, err := s.repo.ListProductBinStocks(productID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(bins))
+	for i, b := range bins {
+		ids[i] = b.LocationID
+	}
+	used := map[uint]float64{}
+	if len(ids) > 0 {
+		if used, err = s.repo.GetLocationUsed(ids); err != nil {
+			return nil, err
+		}
+	}
+
+	suggestions := make([]models.PutawaySuggestion, 0, limit)
+	for _, b := range bins {
+		loc := b.Location
+		if loc == nil || loc.Status != 1 || loc.Type == models.LocationTypeQuarantine {
+			continue
+		}
+		suggestion := models.PutawaySuggestion{
+			LocationID: loc.ID,
+			Path:       loc.Path,
+			Type:       loc.Type,
+			Current:    b.Quantity,
+			Reason:     "已存放该商品",
+		}
+		if loc.Capacity > 0 {
+			available := models.RoundQty(loc.Capacity-used[loc.ID], models.MaxQtyPrecision)
+			if available < qty.base {
+				continue
+			}
+			suggestion.Available = &available
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Type == models.LocationTypePick && suggestions[j].Type != models.LocationTypePick
+	})
+	if len(suggestions) >= limit {
+		return suggestions[:limit], nil
+	}
+
+	empty, err := s.repo.GetEmptyBins(qty.base, limit-len(suggestions))
+	if err != nil {
+		return nil, err
+	}
+	for _, loc := range empty {
+		suggestion := models.PutawaySuggestion{
+			LocationID: loc.ID,
+			Path:       loc.Path,
+			Type:       loc.Type,
+			Reason:     "空货位",
+		}
+		if loc.Capacity > 0 {
+			available := loc.Capacity
+			suggestion.Available = &available
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions, nil
+}
+
+// checkBin 校验出入库或移库指定的货位: 上架须为启用的货位且不超过容量, 下架不超过货位中该商品的库存
+// delta 为本次数量变化 (正数上架、负数下架); pendingUsed / pendingQty 为同批次此前各行对该货位已用量与该商品数量的累计变化
+func (s *Service) checkBin(locationID, productID uint, delta, pendingUsed, pendingQty float64) error {
+	loc, err := s.repo.GetLocationByID(locationID)
+	if err != nil {
+		return fmt.Errorf("库位 %d 不存在", locationID)
+	}
+	if loc.Level != models.LocationBin {
+		return fmt.Errorf("库位 %s 不是货位，不能存放库存", loc.Path)
+	}
+	if delta > 0 {
+		if loc.Status != 1 {
+			return fmt.Errorf("货位 %s 已停用", loc.Path)
+		}
+		if used := models.RoundQty(*loc.Used+pendingUsed, models.MaxQtyPrecision); loc.Capacity > 0 && used+delta > loc.Capacity {
+			return fmt.Errorf("货位 %s 容量不足，容量: %s，已用: %s，本次上架: %s",
+				loc.Path, formatQty(loc.Capacity), formatQty(used), formatQty(delta))
+		}
+	}
+	if delta < 0 {
+		qty, err := s.repo.GetBinStockQty(locationID, productID)
+		if err != nil {
+			return err
+		}
+		if current := models.RoundQty(qty+pendingQty, models.MaxQtyPrecision); current < -delta {
+			return fmt.Errorf("货位 %s 中库存不足，当前: %s，请求: %s", loc.Path, formatQty(current), formatQty(-delta))
+		}
+	}
+	return nil
+}
+
+// locationCode 校验库位编码: 不能为空, 不能包含完整编码的分隔符 "-"
+func locationCode(code string) (string, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return "", fmt.Errorf("库位编码不能为空")
+	}
+	if strings.Contains(code, "-") {
+		return "", fmt.Errorf("库位编码不能包含 \"-\"")
+	}
+	return code, nil
+}
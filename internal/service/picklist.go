@@ -0,0 +1,292 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 拣货单 ====================
+
+// CreatePickList 由一个或多个出库批次生成拣货单 (不改动库存)
+// 各批次的需求按商品合并后分配到货位: 拣货位、存储位 (按完整编码), 最后为未分配库位的库存;
+// 隔离位不参与, 其他待拣货拣货单已占用的数量不再分配
+func (s *Service) CreatePickList(req *models.PickListRequest, operatorID uint, operatorName string) (*models.PickList, error) {
+	list := &models.PickList{
+		Status:        models.PickListOpen,
+		OrderCount:    len(req.Batches),
+		Notes:         req.Notes,
+		CreatedBy:     operatorID,
+		CreatedByName: operatorName,
+	}
+
+	products := make(map[uint]*models.Product)
+	demand := make(map[uint]float64)
+	var productIDs []uint
+	for _, batch := range req.Batches {
+		for _, line := range batch.Lines {
+			product, ok := products[line.ProductID]
+			if !ok {
+				p, err := s.repo.GetProductByID(line.ProductID)
+				if err != nil {
+					return nil, fmt.Errorf("商品 %d 不存在", line.ProductID)
+				}
+				if err := checkStockable(p); err != nil {
+					return nil, err
+				}
+				if err := s.checkNotFrozen(p.ID); err != nil {
+					return nil, err
+				}
+				product = p
+				products[p.ID] = p
+				productIDs = append(productIDs, p.ID)
+			}
+			qty, err := s.toBaseUnit(product, line.Unit, line.Quantity, 0)
+			if err != nil {
+				return nil, err
+			}
+			demand[product.ID] = product.RoundQty(demand[product.ID] + qty.base)
+			list.Orders = append(list.Orders, models.PickListOrder{
+				ReferenceNo: batch.ReferenceNo,
+				ProductID:   product.ID,
+				Quantity:    qty.base,
+				Notes:       line.Notes,
+			})
+		}
+	}
+
+	for _, id := range productIDs {
+		lines, err := s.allocatePick(products[id], demand[id])
+		if err != nil {
+			return nil, err
+		}
+		list.Lines = append(list.Lines, lines...)
+	}
+
+	// 按库位排序形成拣货路线, 无库位的明细排在最后
+	sort.SliceStable(list.Lines, func(i, j int) bool {
+		li, lj := list.Lines[i].LocationPath, list.Lines[j].LocationPath
+		if (li == "") != (lj == "") {
+			return lj == ""
+		}
+		if li != lj {
+			return li < lj
+		}
+		return products[list.Lines[i].ProductID].SKU < products[list.Lines[j].ProductID].SKU
+	})
+	for i := range list.Lines {
+		list.Lines[i].Sequence = i + 1
+	}
+
+	err := s.createWithDocumentNo(func() error {
+		list.PickNo = "PK-" + strings.Replace(time.Now().Format("20060102150405.000"), ".", "", 1)
+		return s.repo.CreatePickList(list)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建拣货单失败: %w", err)
+	}
+	return s.repo.GetPickListByID(list.ID)
+}
+
+// allocatePick 将商品的需求数量分配到货位, 可拣库存不足时返回错误
+func (s *Service) allocatePick(product *models.Product, quantity float64) ([]models.PickListLine, error) {
+	bins, err := s.repo.ListProductBinStocks(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	reserved, err := s.repo.GetPickReservations(product.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []models.PickListLine
+	need := quantity
+	take := func(locationID *uint, path string, available float64) {
+		if need <= 0 || available <= 0 {
+			return
+		}
+		qty := math.Min(need, available)
+		lines = append(lines, models.PickListLine{
+			ProductID:    product.ID,
+			LocationID:   locationID,
+			LocationPath: path,
+			Quantity:     qty,
+		})
+		need = product.RoundQty(need - qty)
+	}
+
+	var binned float64
+	for _, b := range bins {
+		binned += b.Quantity
+	}
+	for _, locType := range []string{models.LocationTypePick, models.LocationTypeBulk} {
+		for _, b := range bins {
+			if b.Location == nil || b.Location.Type != locType {
+				continue
+			}
+			locationID := b.LocationID
+			take(&locationID, b.Location.Path, product.RoundQty(b.Quantity-reserved[b.LocationID]))
+		}
+	}
+	unallocated := product.RoundQty(product.CurrentStock - binned)
+	take(nil, product.Location, product.RoundQty(unallocated-reserved[0]))
+
+	if need > 0 {
+		return nil, fmt.Errorf("商品 %s 可拣库存不足，需求: %s，可拣: %s",
+			product.SKU, formatQty(quantity), formatQty(product.RoundQty(quantity-need)))
+	}
+	return lines, nil
+}
+
+// ListPickLists 获取拣货单列表
+func (s *Service) ListPickLists(query *models.PaginationQuery, status string) ([]models.PickList, int64, error) {
+	return s.repo.ListPickLists(query, status)
+}
+
+// GetPickList 获取拣货单详情, 已确认的附带出库记录
+func (s *Service) GetPickList(id uint) (*models.PickList, error) {
+	list, err := s.repo.GetPickListByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if list.Status == models.PickListConfirmed {
+		if list.Records, err = s.repo.ListPickListRecords(id); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// ConfirmPickList 拣货确认: 按实拣数量写入出库记录, 记录短拣
+// 每条明细的实拣数量按批次顺序分配给需求该商品的批次, 每个批次、货位各写一条出库记录, 关联单号为批次单号
+func (s *Service) ConfirmPickList(id uint, req *models.PickConfirmRequest, operatorID uint, operatorName string) (*models.PickList, error) {
+	list, err := s.repo.GetPickListByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("拣货单不存在")
+	}
+	if list.Status != models.PickListOpen {
+		return nil, fmt.Errorf("拣货单状态为 %s，无法确认", list.Status)
+	}
+
+	picked := make(map[uint]float64, len(req.Lines))
+	for _, l := range req.Lines {
+		picked[l.LineID] = l.PickedQty
+	}
+	for lineID := range picked {
+		found := false
+		for _, line := range list.Lines {
+			if line.ID == lineID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("明细 %d 不属于该拣货单", lineID)
+		}
+	}
+
+	notes := "拣货单 " + list.PickNo
+	if req.Notes != "" {
+		notes += ": " + req.Notes
+	}
+
+	orders := make(map[uint][]*models.PickListOrder)
+	for i := range list.Orders {
+		order := &list.Orders[i]
+		order.PickedQty = 0
+		orders[order.ProductID] = append(orders[order.ProductID], order)
+	}
+
+	stock := make(map[uint]float64) // 商品ID -> 累计扣减后的库存
+	records := make([]*models.InventoryRecord, 0, len(list.Orders))
+	for i := range list.Lines {
+		line := &list.Lines[i]
+		product := line.Product
+		if product == nil {
+			return nil, fmt.Errorf("商品 %d 不存在", line.ProductID)
+		}
+		qty, ok := picked[line.ID]
+		if !ok {
+			qty = line.Quantity
+		}
+		if err := checkQty(product, qty); err != nil {
+			return nil, err
+		}
+		if qty > line.Quantity {
+			return nil, fmt.Errorf("明细 %d 实拣数量 %s 超过应拣数量 %s", line.ID, formatQty(qty), formatQty(line.Quantity))
+		}
+		line.PickedQty = &qty
+		line.ShortQty = product.RoundQty(line.Quantity - qty)
+
+		beforeQty, ok := stock[product.ID]
+		if !ok {
+			if err := s.checkNotFrozen(product.ID); err != nil {
+				return nil, err
+			}
+			beforeQty = product.CurrentStock
+		}
+		remaining := qty
+		for _, order := range orders[product.ID] {
+			if remaining <= 0 {
+				break
+			}
+			open := product.RoundQty(order.Quantity - order.PickedQty)
+			if open <= 0 {
+				continue
+			}
+			take := math.Min(open, remaining)
+			afterQty := product.RoundQty(beforeQty - take)
+			if afterQty < 0 {
+				return nil, fmt.Errorf("库存不足，商品 %s 当前库存: %s，请求出库: %s", product.SKU, formatQty(beforeQty), formatQty(take))
+			}
+			records = append(records, &models.InventoryRecord{
+				ProductID:    product.ID,
+				Type:         models.StockOut,
+				Quantity:     take,
+				BeforeQty:    beforeQty,
+				AfterQty:     afterQty,
+				ReferenceNo:  order.ReferenceNo,
+				LocationID:   line.LocationID,
+				Notes:        notes,
+				OperatorID:   operatorID,
+				OperatorName: operatorName,
+			})
+			beforeQty = afterQty
+			order.PickedQty = product.RoundQty(order.PickedQty + take)
+			remaining = product.RoundQty(remaining - take)
+		}
+		stock[product.ID] = beforeQty
+	}
+	for i := range list.Orders {
+		order := &list.Orders[i]
+		order.ShortQty = models.RoundQty(order.Quantity-order.PickedQty, models.MaxQtyPrecision)
+	}
+
+	now := time.Now()
+	list.Status = models.PickListConfirmed
+	list.ConfirmedBy = operatorID
+	list.ConfirmedByName = operatorName
+	list.ConfirmedAt = &now
+
+	if err := s.repo.ConfirmPickList(list, records); err != nil {
+		return nil, fmt.Errorf("拣货确认失败: %w", err)
+	}
+	return s.GetPickList(id)
+}
+
+// CancelPickList 取消待拣货的拣货单, 释放占用的货位库存
+func (s *Service) CancelPickList(id uint) error {
+	list, err := s.repo.GetPickListByID(id)
+	if err != nil {
+		return fmt.Errorf("拣货单不存在")
+	}
+	if list.Status != models.PickListOpen {
+		return fmt.Errorf("拣货单状态为 %s，无法取消", list.Status)
+	}
+	list.Status = models.PickListCancelled
+	return s.repo.UpdatePickList(list)
+}
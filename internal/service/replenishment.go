@@ -0,0 +1,217 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 补货建议 ====================
+
+// GetReorderSuggestions 计算补货建议, 按供应商分组
+//
+//	日均消耗   = 近 N 天出库总量 / N
+//	安全库存   = 商品设置值, 未设置时取 Z × 日消耗标准差 × √提前期
+//	再订货点   = 日均消耗 × 提前期 + 安全库存
+//	建议采购量 = 目标库存 - (当前库存 + 在途), 目标库存为最高库存,
+//	             未设置最高库存时取 再订货点 + 日均消耗 × 覆盖天数
+func (s *Service) GetReorderSuggestions(days int) ([]models.SupplierReorderGroup, error) {
+	if days <= 0 {
+		days = s.cfg.ReorderLookbackDays
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	daily, err := s.repo.GetDailyOutbound(since, 0)
+	if err != nil {
+		return nil, fmt.Errorf("统计出库历史失败: %w", err)
+	}
+	usage := make(map[uint][]float64)
+	for _, d := range daily {
+		usage[d.ProductID] = append(usage[d.ProductID], d.Quantity)
+	}
+
+	onOrder, err := s.repo.GetOnOrderQuantities()
+	if err != nil {
+		return nil, fmt.Errorf("统计在途数量失败: %w", err)
+	}
+
+	products, err := s.repo.GetActiveProductsWithSupplier()
+	if err != nil {
+		return nil, fmt.Errorf("查询商品失败: %w", err)
+	}
+
+	groups := make(map[uint]*models.SupplierReorderGroup)
+	for _, p := range products {
+		leadTime := s.cfg.ReorderLeadTimeDays
+		if p.Supplier != nil && p.Supplier.LeadTimeDays > 0 {
+			leadTime = p.Supplier.LeadTimeDays
+		}
+
+		mean, stddev := dailyUsageStats(usage[p.ID], days)
+		safety := p.SafetyStock
+		if safety <= 0 {
+			safety = models.CeilQty(s.cfg.ReorderServiceLevelZ*stddev*math.Sqrt(float64(leadTime)), p.QtyPrecision)
+		}
+		rop := p.RoundQty(models.CeilQty(mean*float64(leadTime), p.QtyPrecision) + safety)
+		if p.MinStock > rop {
+			rop = p.MinStock
+		}
+
+		position := p.RoundQty(p.CurrentStock + onOrder[p.ID])
+		if rop == 0 || position > rop {
+			continue
+		}
+
+		target := p.MaxStock
+		if target <= 0 {
+			target = p.RoundQty(rop + models.CeilQty(mean*float64(s.cfg.ReorderReviewDays), p.QtyPrecision))
+		}
+		qty := p.RoundQty(target - position)
+		if qty <= 0 {
+			continue
+		}
+
+		var supplierID uint
+		if p.SupplierID != nil {
+			supplierID = *p.SupplierID
+		}
+		group, ok := groups[supplierID]
+		if !ok {
+			group = &models.SupplierReorderGroup{SupplierID: supplierID, SupplierName: "未指定供应商", LeadTimeDays: leadTime}
+			if p.Supplier != nil {
+				group.SupplierName = p.Supplier.Name
+			}
+			groups[supplierID] = group
+		}
+
+		line := models.ReorderSuggestion{
+			ProductID:     p.ID,
+			SKU:           p.SKU,
+			Name:          p.Name,
+			Unit:          p.Unit,
+			CurrentStock:  p.CurrentStock,
+			OnOrder:       onOrder[p.ID],
+			AvgDailyUsage: math.Round(mean*100) / 100,
+			LeadTimeDays:  leadTime,
+			SafetyStock:   safety,
+			ReorderPoint:  rop,
+			MaxStock:      p.MaxStock,
+			SuggestedQty:  qty,
+			UnitCost:      p.CostPrice,
+			Amount:        qty * p.CostPrice,
+		}
+		group.Lines = append(group.Lines, line)
+		group.TotalAmount += line.Amount
+	}
+
+	result := make([]models.SupplierReorderGroup, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].SupplierID < result[j].SupplierID })
+	return result, nil
+}
+
+// dailyUsageStats 计算日均消耗及标准差, 无出库的日期按 0 计入
+func dailyUsageStats(quantities []float64, days int) (mean, stddev float64) {
+	if days <= 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, q := range quantities {
+		sum += q
+	}
+	mean = sum / float64(days)
+
+	var variance float64
+	for _, q := range quantities {
+		variance += (q - mean) * (q - mean)
+	}
+	variance += float64(days-len(quantities)) * mean * mean
+	return mean, math.Sqrt(variance / float64(days))
+}
+
+// ==================== 采购单 ====================
+
+// CreatePurchaseOrdersFromSuggestions 将补货建议转换为草稿采购单 (每个供应商一张)
+func (s *Service) CreatePurchaseOrdersFromSuggestions(req *models.CreatePurchaseOrdersRequest, operatorID uint, operatorName string) ([]*models.PurchaseOrder, error) {
+	groups, err := s.GetReorderSuggestions(req.Days)
+	if err != nil {
+		return nil, err
+	}
+
+	supplierFilter := make(map[uint]bool, len(req.SupplierIDs))
+	for _, id := range req.SupplierIDs {
+		supplierFilter[id] = true
+	}
+	productFilter := make(map[uint]bool, len(req.ProductIDs))
+	for _, id := range req.ProductIDs {
+		productFilter[id] = true
+	}
+
+	var orders []*models.PurchaseOrder
+	for _, g := range groups {
+		// 未指定供应商的商品无法下单
+		if g.SupplierID == 0 || (len(supplierFilter) > 0 && !supplierFilter[g.SupplierID]) {
+			continue
+		}
+		order := &models.PurchaseOrder{
+			SupplierID:    g.SupplierID,
+			Status:        models.PurchaseOrderDraft,
+			Notes:         req.Notes,
+			CreatedBy:     operatorID,
+			CreatedByName: operatorName,
+		}
+		for _, line := range g.Lines {
+			if len(productFilter) > 0 && !productFilter[line.ProductID] {
+				continue
+			}
+			order.Items = append(order.Items, models.PurchaseOrderItem{
+				ProductID: line.ProductID,
+				Quantity:  line.SuggestedQty,
+				UnitCost:  line.UnitCost,
+				Amount:    line.Amount,
+			})
+			order.TotalQuantity = models.RoundQty(order.TotalQuantity+line.SuggestedQty, models.MaxQtyPrecision)
+			order.TotalAmount += line.Amount
+		}
+		if len(order.Items) > 0 {
+			orders = append(orders, order)
+		}
+	}
+
+	if len(orders) == 0 {
+		return nil, fmt.Errorf("没有需要生成采购单的补货建议")
+	}
+	// 并发生成时当日序号可能冲突, 重试时重新分配单号
+	if err := s.createWithDocumentNo(func() error { return s.repo.CreatePurchaseOrders(orders) }); err != nil {
+		return nil, fmt.Errorf("生成采购单失败: %w", err)
+	}
+	return orders, nil
+}
+
+// ListPurchaseOrders 获取采购单列表
+func (s *Service) ListPurchaseOrders(query *models.PaginationQuery, supplierID *uint, status string) ([]models.PurchaseOrder, int64, error) {
+	return s.repo.ListPurchaseOrders(query, supplierID, status)
+}
+
+// GetPurchaseOrder 获取采购单详情
+func (s *Service) GetPurchaseOrder(id uint) (*models.PurchaseOrder, error) {
+	return s.repo.GetPurchaseOrderByID(id)
+}
+
+// CancelPurchaseOrder 取消草稿采购单
+func (s *Service) CancelPurchaseOrder(id uint) error {
+	order, err := s.repo.GetPurchaseOrderByID(id)
+	if err != nil {
+		return fmt.Errorf("采购单不存在")
+	}
+	if order.Status != models.PurchaseOrderDraft {
+		return fmt.Errorf("采购单状态为 %s，无法取消", order.Status)
+	}
+	order.Status = models.PurchaseOrderCancelled
+	return s.repo.UpdatePurchaseOrder(order)
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 库龄报表 ====================
+
+// GetStockAgingReport 库龄与呆滞库存报表
+// 库龄按距最近一次入库或出库的天数分段; 超过 deadDays 天无出库且有库存的商品视为呆滞
+func (s *Service) GetStockAgingReport(deadDays int) (*models.StockAgingReport, error) {
+	if deadDays <= 0 {
+		deadDays = 90
+	}
+
+	movements, err := s.repo.GetLastMovements()
+	if err != nil {
+		return nil, fmt.Errorf("查询出入库时间失败: %w", err)
+	}
+	products, err := s.repo.GetActiveProductsWithSupplier()
+	if err != nil {
+		return nil, fmt.Errorf("查询商品失败: %w", err)
+	}
+
+	report := &models.StockAgingReport{
+		DeadDays:  deadDays,
+		DeadStock: []models.StockAgingItem{},
+		Items:     make([]models.StockAgingItem, 0, len(products)),
+	}
+	bucketIdx := make(map[string]int, len(models.AgingBuckets))
+	for i, b := range models.AgingBuckets {
+		report.Buckets = append(report.Buckets, models.AgingBucketSummary{Bucket: b})
+		bucketIdx[b] = i
+	}
+	categories := make(map[uint]*models.AgingGroup)
+	suppliers := make(map[uint]*models.AgingGroup)
+
+	now := time.Now()
+	for _, p := range products {
+		if p.CurrentStock <= 0 {
+			continue
+		}
+		m := movements[p.ID]
+
+		// 最近一次出入库时间, 从未发生时按建档时间
+		last := p.CreatedAt
+		if m.LastIn != nil {
+			last = *m.LastIn
+		}
+		if m.LastOut != nil && m.LastOut.After(last) {
+			last = *m.LastOut
+		}
+		lastOut := p.CreatedAt
+		if m.LastOut != nil {
+			lastOut = *m.LastOut
+		}
+
+		item := models.StockAgingItem{
+			ProductID:    p.ID,
+			SKU:          p.SKU,
+			Name:         p.Name,
+			CurrentStock: p.CurrentStock,
+			Value:        round2(p.CurrentStock * p.CostPrice),
+			LastInAt:     m.LastIn,
+			LastOutAt:    m.LastOut,
+			AgeDays:      daysBetween(last, now),
+			IdleDays:     daysBetween(lastOut, now),
+		}
+		item.Bucket = agingBucket(item.AgeDays)
+		item.DeadStock = item.IdleDays >= deadDays
+
+		var categoryID, supplierID uint
+		categoryName, supplierName := "未分类", "未指定供应商"
+		if p.Category != nil {
+			categoryID, categoryName = p.Category.ID, p.Category.Name
+		}
+		if p.Supplier != nil {
+			supplierID, supplierName = p.Supplier.ID, p.Supplier.Name
+		}
+		item.CategoryName = categoryName
+		item.SupplierName = supplierName
+
+		summary := &report.Buckets[bucketIdx[item.Bucket]]
+		summary.ProductCount++
+		summary.Quantity = models.RoundQty(summary.Quantity+item.CurrentStock, models.MaxQtyPrecision)
+		summary.Value += item.Value
+		report.TotalValue += item.Value
+		addAgingGroup(categories, categoryID, categoryName, &item)
+		addAgingGroup(suppliers, supplierID, supplierName, &item)
+
+		if item.DeadStock {
+			report.DeadStockValue += item.Value
+			report.DeadStock = append(report.DeadStock, item)
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	for i := range report.Buckets {
+		report.Buckets[i].Value = round2(report.Buckets[i].Value)
+	}
+	report.TotalValue = round2(report.TotalValue)
+	report.DeadStockValue = round2(report.DeadStockValue)
+	report.ByCategory = sortedAgingGroups(categories)
+	report.BySupplier = sortedAgingGroups(suppliers)
+	sort.SliceStable(report.Items, func(i, j int) bool { return report.Items[i].AgeDays > report.Items[j].AgeDays })
+	sort.SliceStable(report.DeadStock, func(i, j int) bool { return report.DeadStock[i].Value > report.DeadStock[j].Value })
+	return report, nil
+}
+
+// agingBucket 库龄天数所属分段
+func agingBucket(days int) string {
+	switch {
+	case days <= 30:
+		return models.AgingBucket0To30
+	case days <= 90:
+		return models.AgingBucket31To90
+	case days <= 180:
+		return models.AgingBucket91To180
+	default:
+		return models.AgingBucketOver180
+	}
+}
+
+// addAgingGroup 将商品库龄金额累加到分组
+func addAgingGroup(groups map[uint]*models.AgingGroup, id uint, name string, item *models.StockAgingItem) {
+	g, ok := groups[id]
+	if !ok {
+		g = &models.AgingGroup{ID: id, Name: name, Buckets: make(map[string]float64)}
+		for _, b := range models.AgingBuckets {
+			g.Buckets[b] = 0
+		}
+		groups[id] = g
+	}
+	g.Buckets[item.Bucket] = round2(g.Buckets[item.Bucket] + item.Value)
+	g.Value = round2(g.Value + item.Value)
+	if item.DeadStock {
+		g.DeadValue = round2(g.DeadValue + item.Value)
+	}
+}
+
+// sortedAgingGroups 按库存金额降序输出分组
+func sortedAgingGroups(groups map[uint]*models.AgingGroup) []models.AgingGroup {
+	result := make([]models.AgingGroup, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Value > result[j].Value })
+	return result
+}
+
+// daysBetween 两个时间之间的整天数
+func daysBetween(from, to time.Time) int {
+	if to.Before(from) {
+		return 0
+	}
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// ==================== 周转报表 ====================
+
+// turnoverAcc 周转指标累加器 (按商品/分类/供应商汇总)
+type turnoverAcc struct {
+	row          models.TurnoverRow
+	openingValue float64
+	closingValue float64
+}
+
+// GetTurnoverReport 计算期间周转率、库存天数与售罄率
+//
+//	期初/期末数量 = 当前库存 - 该时点之后的库存净变化
+//	周转率 = 出库成本 / 平均库存金额, 库存天数 = 期间天数 / 周转率
+//	可供天数 = 期末数量 / 日均出库量, 售罄率 = 出库量 / (期初 + 入库量)
+func (s *Service) GetTurnoverReport(q *models.TurnoverQuery) (*models.TurnoverReport, error) {
+	from, to, err := parseDateRange(q.From, q.To, 30)
+	if err != nil {
+		return nil, err
+	}
+	if q.GroupBy == "" {
+		q.GroupBy = "product"
+	}
+	if q.GroupBy != "product" && q.GroupBy != "category" && q.GroupBy != "supplier" {
+		return nil, fmt.Errorf("group_by 仅支持 product / category / supplier")
+	}
+	days := int(to.Sub(from).Hours() / 24)
+
+	openingChange, err := s.repo.GetNetChangeSince(from)
+	if err != nil {
+		return nil, fmt.Errorf("统计期初库存失败: %w", err)
+	}
+	closingChange, err := s.repo.GetNetChangeSince(to)
+	if err != nil {
+		return nil, fmt.Errorf("统计期末库存失败: %w", err)
+	}
+	flows, err := s.repo.GetPeriodFlows(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("统计期间出入库失败: %w", err)
+	}
+	products, err := s.repo.GetActiveProductsWithSupplier()
+	if err != nil {
+		return nil, fmt.Errorf("查询商品失败: %w", err)
+	}
+
+	groups := make(map[uint]*turnoverAcc)
+	var order []uint
+	total := &turnoverAcc{row: models.TurnoverRow{Name: "合计"}}
+	for _, p := range products {
+		opening := p.RoundQty(p.CurrentStock - openingChange[p.ID])
+		closing := p.RoundQty(p.CurrentStock - closingChange[p.ID])
+		flow := flows[p.ID]
+
+		var id uint
+		name, sku := p.Name, ""
+		switch q.GroupBy {
+		case "product":
+			id, sku = p.ID, p.SKU
+		case "category":
+			name = "未分类"
+			if p.Category != nil {
+				id, name = p.Category.ID, p.Category.Name
+			}
+		case "supplier":
+			name = "未指定供应商"
+			if p.Supplier != nil {
+				id, name = p.Supplier.ID, p.Supplier.Name
+			}
+		}
+		acc, ok := groups[id]
+		if !ok {
+			acc = &turnoverAcc{row: models.TurnoverRow{ID: id, Name: name, SKU: sku}}
+			groups[id] = acc
+			order = append(order, id)
+		}
+		for _, a := range []*turnoverAcc{acc, total} {
+			a.row.OpeningQty += opening
+			a.row.ClosingQty += closing
+			a.row.ReceivedQty += flow.ReceivedQty
+			a.row.SoldQty += flow.SoldQty
+			a.row.COGS += flow.SoldValue
+			a.openingValue += opening * p.CostPrice
+			a.closingValue += closing * p.CostPrice
+		}
+	}
+
+	report := &models.TurnoverReport{
+		From:    from.Format("2006-01-02"),
+		To:      to.AddDate(0, 0, -1).Format("2006-01-02"),
+		Days:    days,
+		GroupBy: q.GroupBy,
+		Items:   make([]models.TurnoverRow, 0, len(order)),
+	}
+	for _, id := range order {
+		report.Items = append(report.Items, groups[id].finish(days))
+	}
+	report.Total = total.finish(days)
+	sort.SliceStable(report.Items, func(i, j int) bool { return report.Items[i].TurnoverRatio > report.Items[j].TurnoverRatio })
+	return report, nil
+}
+
+// finish 由累计值计算各比率
+func (a *turnoverAcc) finish(days int) models.TurnoverRow {
+	row := a.row
+	for _, qty := range []*float64{&row.OpeningQty, &row.ClosingQty, &row.ReceivedQty, &row.SoldQty} {
+		*qty = models.RoundQty(*qty, models.MaxQtyPrecision)
+	}
+	row.AvgInventoryValue = round2((a.openingValue + a.closingValue) / 2)
+	row.COGS = round2(row.COGS)
+	row.DaysOnHand = -1
+	row.DaysOfSupply = -1
+	if row.AvgInventoryValue > 0 {
+		row.TurnoverRatio = round2(row.COGS / row.AvgInventoryValue)
+	}
+	if row.TurnoverRatio > 0 {
+		row.DaysOnHand = round2(float64(days) / row.TurnoverRatio)
+	}
+	if row.SoldQty > 0 && days > 0 {
+		row.DaysOfSupply = round2(row.ClosingQty / (row.SoldQty / float64(days)))
+	}
+	if available := row.OpeningQty + row.ReceivedQty; available > 0 {
+		row.SellThroughRate = round2(row.SoldQty / available * 100)
+	}
+	return row
+}
+
+// parseDateRange 解析 [from, to] 日期 (含两端), 返回 [from 零点, to 次日零点)
+func parseDateRange(fromStr, toStr string, defaultDays int) (time.Time, time.Time, error) {
+	to := truncateDay(time.Now())
+	if toStr != "" {
+		t, err := time.ParseInLocation("2006-01-02", toStr, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("无效的截止日期: %s", toStr)
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -defaultDays+1)
+	if fromStr != "" {
+		t, err := time.ParseInLocation("2006-01-02", fromStr, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("无效的起始日期: %s", fromStr)
+		}
+		from = t
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("起始日期不能晚于截止日期")
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
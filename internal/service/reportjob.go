@@ -0,0 +1,435 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-cargo/internal/export"
+	"go-cargo/internal/mailer"
+	"go-cargo/internal/models"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ==================== 定时报表 ====================
+
+// reportSchedulerInterval 定时报表调度器检查间隔
+const reportSchedulerInterval = 30 * time.Second
+
+// ListReportJobs 获取报表任务列表
+func (s *Service) ListReportJobs(query *models.PaginationQuery) ([]models.ReportJob, int64, error) {
+	return s.repo.ListReportJobs(query)
+}
+
+// GetReportJob 获取报表任务详情
+func (s *Service) GetReportJob(id uint) (*models.ReportJob, error) {
+	job, err := s.repo.GetReportJobByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("报表任务不存在")
+	}
+	return job, nil
+}
+
+// CreateReportJob 创建报表任务
+func (s *Service) CreateReportJob(req *models.ReportJobRequest, operatorID uint, operatorName string) (*models.ReportJob, error) {
+	job := &models.ReportJob{
+		Enabled:       true,
+		CreatedBy:     operatorID,
+		CreatedByName: operatorName,
+	}
+	if err := s.applyReportJobRequest(job, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateReportJob(job); err != nil {
+		return nil, fmt.Errorf("创建报表任务失败: %w", err)
+	}
+	return job, nil
+}
+
+// UpdateReportJob 更新报表任务, 重新计算下次执行时间
+func (s *Service) UpdateReportJob(id uint, req *models.ReportJobRequest) (*models.ReportJob, error) {
+	job, err := s.repo.GetReportJobByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("报表任务不存在")
+	}
+	if err := s.applyReportJobRequest(job, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateReportJob(job); err != nil {
+		return nil, fmt.Errorf("更新报表任务失败: %w", err)
+	}
+	return job, nil
+}
+
+// DeleteReportJob 删除报表任务
+func (s *Service) DeleteReportJob(id uint) error {
+	return s.repo.DeleteReportJob(id)
+}
+
+// ListReportJobRuns 获取任务执行记录
+func (s *Service) ListReportJobRuns(jobID uint, query *models.PaginationQuery) ([]models.ReportJobRun, int64, error) {
+	return s.repo.ListReportJobRuns(jobID, query)
+}
+
+// applyReportJobRequest 校验请求并写入任务
+func (s *Service) applyReportJobRequest(job *models.ReportJob, req *models.ReportJobRequest) error {
+	switch req.ReportType {
+	case models.ReportLowStock, models.ReportValuation, models.ReportMovementSummary:
+	default:
+		return fmt.Errorf("报表类型仅支持 low_stock / valuation / movement_summary")
+	}
+	if !export.Format(req.Format).Valid() {
+		return fmt.Errorf("格式仅支持 csv / xlsx / pdf")
+	}
+	schedule, err := cron.ParseStandard(req.Schedule)
+	if err != nil {
+		return fmt.Errorf("无效的 cron 表达式: %v", err)
+	}
+	if req.PeriodDays != nil && (*req.PeriodDays < 1 || *req.PeriodDays > 366) {
+		return fmt.Errorf("统计天数需在 1~366 之间")
+	}
+
+	var recipients []string
+	switch req.Delivery {
+	case models.DeliveryEmail:
+		if recipients, err = parseRecipients(req.Recipients); err != nil {
+			return err
+		}
+	case models.DeliveryDirectory:
+		if s.cfg.ReportOutputDir == "" {
+			return fmt.Errorf("未配置报表输出目录 REPORT_OUTPUT_DIR")
+		}
+	default:
+		return fmt.Errorf("投递方式仅支持 email / directory")
+	}
+
+	job.Name = req.Name
+	job.ReportType = req.ReportType
+	job.Format = req.Format
+	job.Schedule = req.Schedule
+	if req.PeriodDays != nil {
+		job.PeriodDays = *req.PeriodDays
+	} else if job.PeriodDays == 0 {
+		job.PeriodDays = 7
+	}
+	job.Delivery = req.Delivery
+	job.Recipients = strings.Join(recipients, ",")
+	if req.Enabled != nil {
+		job.Enabled = *req.Enabled
+	}
+	job.NextRunAt = nil
+	if job.Enabled {
+		next := schedule.Next(time.Now())
+		job.NextRunAt = &next
+	}
+	return nil
+}
+
+// parseRecipients 解析逗号分隔的收件人列表
+func parseRecipients(s string) ([]string, error) {
+	var result []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		addr, err := mail.ParseAddress(part)
+		if err != nil {
+			return nil, fmt.Errorf("无效的收件人地址: %s", part)
+		}
+		result = append(result, addr.Address)
+	}
+	if len(result) == 0 {
+		return nil, fmt.Errorf("邮件投递需填写收件人")
+	}
+	return result, nil
+}
+
+// RunReportJob 立即执行一次报表任务 (不影响下次定时执行时间)
+func (s *Service) RunReportJob(id uint) (*models.ReportJobRun, error) {
+	job, err := s.repo.GetReportJobByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("报表任务不存在")
+	}
+	return s.executeReportJob(job, "manual")
+}
+
+// StartReportScheduler 定时检查并执行到期的报表任务, ctx 取消时退出
+func (s *Service) StartReportScheduler(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(reportSchedulerInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.runDueReportJobs(now)
+			}
+		}
+	}()
+}
+
+// runDueReportJobs 依次执行到期任务
+func (s *Service) runDueReportJobs(now time.Time) {
+	jobs, err := s.repo.GetDueReportJobs(now)
+	if err != nil {
+		log.Printf("[ReportJob] 查询到期任务失败: %v", err)
+		return
+	}
+	for i := range jobs {
+		run, err := s.executeReportJob(&jobs[i], "schedule")
+		if err != nil {
+			log.Printf("[ReportJob] 任务 #%d 保存执行记录失败: %v", jobs[i].ID, err)
+			continue
+		}
+		if run.Status == models.ReportRunSuccess {
+			log.Printf("[ReportJob] 任务 #%d %s 执行成功: %s", jobs[i].ID, jobs[i].Name, run.Target)
+		} else {
+			log.Printf("[ReportJob] 任务 #%d %s 执行失败: %s", jobs[i].ID, jobs[i].Name, run.Error)
+		}
+	}
+}
+
+// executeReportJob 生成并投递报表, 保存执行记录
+// 生成或投递失败记为失败的执行记录, 仅保存记录失败时返回 error
+func (s *Service) executeReportJob(job *models.ReportJob, trigger string) (*models.ReportJobRun, error) {
+	started := time.Now()
+	run := &models.ReportJobRun{
+		JobID:     job.ID,
+		Trigger:   trigger,
+		Delivery:  job.Delivery,
+		StartedAt: started,
+	}
+	if err := s.produceReport(job, run); err != nil {
+		run.Status = models.ReportRunFailed
+		run.Error = err.Error()
+	} else {
+		run.Status = models.ReportRunSuccess
+	}
+	run.FinishedAt = time.Now()
+
+	var next *time.Time
+	if trigger == "schedule" {
+		if schedule, err := cron.ParseStandard(job.Schedule); err == nil {
+			t := schedule.Next(started)
+			next = &t
+		}
+	}
+	if err := s.repo.CreateReportJobRun(run, next); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// produceReport 渲染报表并按任务配置投递
+func (s *Service) produceReport(job *models.ReportJob, run *models.ReportJobRun) error {
+	table, err := s.buildReportTable(job)
+	if err != nil {
+		return err
+	}
+	format := export.Format(job.Format)
+	data, err := s.renderer.Render(table, format)
+	if err != nil {
+		return fmt.Errorf("生成报表失败: %w", err)
+	}
+	run.RowCount = len(table.Rows)
+	run.FileName = fmt.Sprintf("%s_%s.%s", safeFileName(job.Name), run.StartedAt.Format("20060102_150405"), job.Format)
+	run.FileSize = len(data)
+
+	switch job.Delivery {
+	case models.DeliveryEmail:
+		run.Target = job.Recipients
+		smtpCfg := &mailer.Config{
+			Host:     s.cfg.SMTPHost,
+			Port:     s.cfg.SMTPPort,
+			Username: s.cfg.SMTPUsername,
+			Password: s.cfg.SMTPPassword,
+			From:     s.cfg.SMTPFrom,
+		}
+		msg := &mailer.Message{
+			To:      strings.Split(job.Recipients, ","),
+			Subject: fmt.Sprintf("%s - %s", job.Name, run.StartedAt.Format("2006-01-02")),
+			Body:    fmt.Sprintf("%s\n%s\n共 %d 行, 详见附件。\n", table.Title, table.Subtitle, len(table.Rows)),
+			Attachments: []mailer.Attachment{
+				{Name: run.FileName, ContentType: format.ContentType(), Data: data},
+			},
+		}
+		if err := mailer.Send(smtpCfg, msg); err != nil {
+			return fmt.Errorf("发送邮件失败: %w", err)
+		}
+	case models.DeliveryDirectory:
+		if err := os.MkdirAll(s.cfg.ReportOutputDir, 0o755); err != nil {
+			return fmt.Errorf("创建输出目录失败: %w", err)
+		}
+		path := filepath.Join(s.cfg.ReportOutputDir, run.FileName)
+		run.Target = path
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return fmt.Errorf("写入报表文件失败: %w", err)
+		}
+	default:
+		return fmt.Errorf("未知的投递方式: %s", job.Delivery)
+	}
+	return nil
+}
+
+// safeFileName 去除文件名中的路径分隔符等特殊字符
+func safeFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', ' ':
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == ".." {
+		return "report"
+	}
+	return name
+}
+
+// buildReportTable 按报表类型查询数据并生成表格
+func (s *Service) buildReportTable(job *models.ReportJob) (*export.Table, error) {
+	switch job.ReportType {
+	case models.ReportLowStock:
+		return s.lowStockTable()
+	case models.ReportValuation:
+		return s.valuationTable()
+	case models.ReportMovementSummary:
+		return s.movementSummaryTable(job.PeriodDays)
+	}
+	return nil, fmt.Errorf("未知的报表类型: %s", job.ReportType)
+}
+
+// lowStockTable 低库存清单, 按缺口从大到小排列
+func (s *Service) lowStockTable() (*export.Table, error) {
+	products, err := s.repo.GetActiveProductsWithSupplier()
+	if err != nil {
+		return nil, fmt.Errorf("查询商品失败: %w", err)
+	}
+	var low []models.Product
+	for _, p := range products {
+		if p.MinStock > 0 && p.CurrentStock <= p.MinStock {
+			low = append(low, p)
+		}
+	}
+	sort.SliceStable(low, func(i, j int) bool {
+		return low[i].MinStock-low[i].CurrentStock > low[j].MinStock-low[j].CurrentStock
+	})
+
+	t := &export.Table{
+		Title:    "低库存清单",
+		Subtitle: fmt.Sprintf("截至 %s, 共 %d 个商品低于最低库存", time.Now().Format("2006-01-02 15:04"), len(low)),
+		Headers:  []string{"SKU", "商品名称", "分类", "供应商", "当前库存", "最低库存", "缺口", "单位"},
+		Numeric:  []bool{false, false, false, false, true, true, true, false},
+	}
+	for _, p := range low {
+		t.Rows = append(t.Rows, []string{
+			p.SKU, p.Name, categoryName(&p), supplierName(&p),
+			formatQty(p.CurrentStock), formatQty(p.MinStock), formatQty(p.MinStock - p.CurrentStock), p.Unit,
+		})
+	}
+	return t, nil
+}
+
+// valuationTable 库存估值 (数量 × 成本价)
+func (s *Service) valuationTable() (*export.Table, error) {
+	products, err := s.repo.GetActiveProductsWithSupplier()
+	if err != nil {
+		return nil, fmt.Errorf("查询商品失败: %w", err)
+	}
+
+	t := &export.Table{
+		Title:    "库存估值",
+		Subtitle: fmt.Sprintf("截至 %s, 按成本价计算", time.Now().Format("2006-01-02 15:04")),
+		Headers:  []string{"SKU", "商品名称", "分类", "库存数量", "单位", "成本价", "库存金额"},
+		Numeric:  []bool{false, false, false, true, false, true, true},
+	}
+	var totalQty float64
+	var totalValue float64
+	for _, p := range products {
+		value := p.CurrentStock * p.CostPrice
+		totalQty += p.CurrentStock
+		totalValue += value
+		t.Rows = append(t.Rows, []string{
+			p.SKU, p.Name, categoryName(&p), formatQty(p.CurrentStock), p.Unit,
+			formatMoney(p.CostPrice), formatMoney(value),
+		})
+	}
+	t.Footer = []string{"合计", "", "", formatQty(totalQty), "", "", formatMoney(totalValue)}
+	return t, nil
+}
+
+// movementSummaryTable 最近 days 天出入库汇总 (不含已冲销记录)
+func (s *Service) movementSummaryTable(days int) (*export.Table, error) {
+	from, to, err := parseDateRange("", "", days)
+	if err != nil {
+		return nil, err
+	}
+	flows, err := s.repo.GetPeriodFlows(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("统计出入库失败: %w", err)
+	}
+	products, err := s.repo.GetActiveProductsWithSupplier()
+	if err != nil {
+		return nil, fmt.Errorf("查询商品失败: %w", err)
+	}
+
+	t := &export.Table{
+		Title:    "出入库汇总",
+		Subtitle: fmt.Sprintf("统计期间: %s ~ %s", from.Format("2006-01-02"), to.AddDate(0, 0, -1).Format("2006-01-02")),
+		Headers:  []string{"SKU", "商品名称", "分类", "入库数量", "出库数量", "出库成本", "当前库存"},
+		Numeric:  []bool{false, false, false, true, true, true, true},
+	}
+	var totalIn, totalOut float64
+	var totalCost float64
+	for _, p := range products {
+		flow, ok := flows[p.ID]
+		if !ok {
+			continue
+		}
+		totalIn += flow.ReceivedQty
+		totalOut += flow.SoldQty
+		totalCost += flow.SoldValue
+		t.Rows = append(t.Rows, []string{
+			p.SKU, p.Name, categoryName(&p), formatQty(flow.ReceivedQty), formatQty(flow.SoldQty),
+			formatMoney(flow.SoldValue), formatQty(p.CurrentStock),
+		})
+	}
+	t.Footer = []string{"合计", "", "", formatQty(totalIn), formatQty(totalOut), formatMoney(totalCost), ""}
+	return t, nil
+}
+
+// categoryName 商品分类名称
+func categoryName(p *models.Product) string {
+	if p.Category != nil {
+		return p.Category.Name
+	}
+	return ""
+}
+
+// supplierName 商品供应商名称
+func supplierName(p *models.Product) string {
+	if p.Supplier != nil {
+		return p.Supplier.Name
+	}
+	return ""
+}
+
+// formatMoney 金额保留两位小数
+func formatMoney(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// formatQty 格式化数量, 整数不带小数部分
+func formatQty(v float64) string {
+	return strconv.FormatFloat(models.RoundQty(v, models.MaxQtyPrecision), 'f', -1, 64)
+}
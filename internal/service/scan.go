@@ -0,0 +1,228 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"go-cargo/internal/gs1"
+	"go-cargo/internal/models"
+)
+
+// ==================== 扫码 ====================
+
+// ResolveScan 解析扫码内容
+// GS1-128 内容按其中的 GTIN 匹配; 普通条码依次按商品条码与备用条码、SKU、供应商料号、
+// 同一 GTIN 的其他长度表示精确匹配
+func (s *Service) ResolveScan(code string) (*models.ScanResult, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return nil, fmt.Errorf("扫码内容不能为空")
+	}
+	result := &models.ScanResult{Code: code}
+
+	data, isGS1, err := gs1.Parse(code)
+	if err != nil {
+		return nil, fmt.Errorf("GS1 条码解析失败: %w", err)
+	}
+	if isGS1 {
+		if data.GTIN == "" {
+			return nil, fmt.Errorf("GS1 条码未包含 GTIN (01)")
+		}
+		result.GS1 = data
+		code = data.GTIN
+	}
+
+	if ok, err := s.matchBarcodes(result, []string{code}, ""); ok || err != nil {
+		return result, err
+	}
+
+	if !isGS1 {
+		if product, err := s.repo.GetProductBySKU(code); err == nil {
+			if product, err = s.repo.GetProductByID(product.ID); err == nil {
+				setScanMatches(result, []models.Product{*product}, models.ScanMatchSKU)
+				return result, nil
+			}
+		}
+
+		identifiers, err := s.repo.FindIdentifiers(models.IdentifierSupplierPart, []string{code})
+		if err != nil {
+			return nil, fmt.Errorf("查询商品失败: %w", err)
+		}
+		if len(identifiers) > 0 {
+			setScanMatches(result, identifierProducts(identifiers), models.ScanMatchSupplierPart)
+			if result.Product != nil {
+				result.Identifier = &identifiers[0]
+			}
+			return result, nil
+		}
+	}
+
+	if variants := gs1.GTINVariants(code); len(variants) > 0 {
+		if _, err := s.matchBarcodes(result, variants, models.ScanMatchGTIN); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// matchBarcodes 按商品主条码与备用条码匹配; matchedBy 为空时按命中的条码类型标记
+func (s *Service) matchBarcodes(result *models.ScanResult, barcodes []string, matchedBy string) (bool, error) {
+	products, err := s.repo.GetProductsByBarcodes(barcodes)
+	if err != nil {
+		return false, fmt.Errorf("查询商品失败: %w", err)
+	}
+	identifiers, err := s.repo.FindIdentifiers(models.IdentifierBarcode, barcodes)
+	if err != nil {
+		return false, fmt.Errorf("查询商品失败: %w", err)
+	}
+	if len(products) == 0 && len(identifiers) == 0 {
+		return false, nil
+	}
+
+	if matchedBy == "" {
+		matchedBy = models.ScanMatchBarcode
+		if len(products) == 0 {
+			matchedBy = models.ScanMatchAltBarcode
+		}
+	}
+	setScanMatches(result, append(products, identifierProducts(identifiers)...), matchedBy)
+	if result.Product != nil && len(products) == 0 {
+		result.Identifier = &identifiers[0]
+	}
+	return true, nil
+}
+
+// identifierProducts 取标识所属商品
+func identifierProducts(identifiers []models.ProductIdentifier) []models.Product {
+	products := make([]models.Product, 0, len(identifiers))
+	for i := range identifiers {
+		if identifiers[i].Product != nil {
+			products = append(products, *identifiers[i].Product)
+			identifiers[i].Product = nil
+		}
+	}
+	return products
+}
+
+// setScanMatches 记录匹配结果 (按商品去重), 唯一匹配时设置 Product
+func setScanMatches(result *models.ScanResult, products []models.Product, matchedBy string) {
+	seen := make(map[uint]bool, len(products))
+	unique := products[:0]
+	for _, p := range products {
+		if !seen[p.ID] {
+			seen[p.ID] = true
+			unique = append(unique, p)
+		}
+	}
+	if len(unique) == 0 {
+		return
+	}
+	result.MatchedBy = matchedBy
+	if len(unique) == 1 {
+		result.Product = &unique[0]
+		return
+	}
+	result.Matches = unique
+}
+
+// ScanStockIn 扫码入库, GS1 批号与有效期写入库存记录
+func (s *Service) ScanStockIn(req *models.ScanStockInRequest, operatorID uint, operatorName string) (*models.ScanStockResult, error) {
+	scan, err := s.resolveScanProduct(req.Code)
+	if err != nil {
+		return nil, err
+	}
+	quantity := scanQuantity(req.Quantity, scan)
+
+	qty, err := s.toBaseUnit(scan.Product, req.Unit, quantity, req.UnitCost)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &models.StockInRequest{
+		ProductID:   scan.Product.ID,
+		Quantity:    quantity,
+		Unit:        req.Unit,
+		UnitCost:    req.UnitCost,
+		ReferenceNo: req.ReferenceNo,
+		Notes:       req.Notes,
+	}
+	if scan.GS1 != nil {
+		in.LotNo = scan.GS1.Lot
+		in.ExpiryDate = scan.GS1.Expiry
+		if in.ExpiryDate == "" {
+			in.ExpiryDate = scan.GS1.BestBefore
+		}
+	}
+	record, err := s.StockIn(in, operatorID, operatorName)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ScanStockResult{Scan: scan, Quantity: qty.base, BeforeQty: record.BeforeQty, AfterQty: record.AfterQty}, nil
+}
+
+// ScanStockOut 扫码出库
+func (s *Service) ScanStockOut(req *models.ScanStockOutRequest, operatorID uint, operatorName string) (*models.ScanStockResult, error) {
+	scan, err := s.resolveScanProduct(req.Code)
+	if err != nil {
+		return nil, err
+	}
+	quantity := scanQuantity(req.Quantity, scan)
+
+	qty, err := s.toBaseUnit(scan.Product, req.Unit, quantity, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &models.StockOutRequest{
+		ProductID:   scan.Product.ID,
+		Quantity:    quantity,
+		Unit:        req.Unit,
+		ReferenceNo: req.ReferenceNo,
+		Notes:       req.Notes,
+	}
+	if scan.GS1 != nil {
+		out.LotNo = scan.GS1.Lot
+	}
+	record, err := s.StockOut(out, operatorID, operatorName)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.ScanStockResult{Scan: scan, Quantity: qty.base}
+	if record != nil {
+		result.BeforeQty, result.AfterQty = record.BeforeQty, record.AfterQty
+	} else {
+		// 虚拟组合商品扣减的是组件库存, 自身库存不变
+		result.BeforeQty, result.AfterQty = scan.Product.CurrentStock, scan.Product.CurrentStock
+	}
+	return result, nil
+}
+
+// resolveScanProduct 解析扫码内容, 要求唯一匹配一个商品
+func (s *Service) resolveScanProduct(code string) (*models.ScanResult, error) {
+	scan, err := s.ResolveScan(code)
+	if err != nil {
+		return nil, err
+	}
+	if len(scan.Matches) > 1 {
+		skus := make([]string, len(scan.Matches))
+		for i, p := range scan.Matches {
+			skus[i] = p.SKU
+		}
+		return nil, fmt.Errorf("条码 %s 对应多个商品 (%s)，请按商品ID操作", scan.Code, strings.Join(skus, ", "))
+	}
+	if scan.Product == nil {
+		return nil, fmt.Errorf("未找到条码对应的商品: %s", scan.Code)
+	}
+	return scan, nil
+}
+
+// scanQuantity 扫码数量: 请求指定优先, 其次取 GS1 数量, 默认每次扫码计 1
+func scanQuantity(quantity float64, scan *models.ScanResult) float64 {
+	if quantity > 0 {
+		return quantity
+	}
+	if scan.GS1 != nil && scan.GS1.Count > 0 {
+		return float64(scan.GS1.Count)
+	}
+	return 1
+}
@@ -4,9 +4,12 @@ package service
 import (
 	"errors"
 	"fmt"
+	"log"
+	"strconv"
 	"time"
 
 	"go-cargo/internal/config"
+	"go-cargo/internal/export"
 	"go-cargo/internal/models"
 	"go-cargo/internal/repository"
 
@@ -17,13 +20,45 @@ import (
 
 // Service 业务逻辑层
 type Service struct {
-	repo *repository.Repository
-	cfg  *config.Config
+	repo     *repository.Repository
+	cfg      *config.Config
+	cache    *aggregateCache
+	renderer *export.Renderer
 }
 
 // New 创建 Service 实例
 func New(repo *repository.Repository, cfg *config.Config) *Service {
-	return &Service{repo: repo, cfg: cfg}
+	s := &Service{
+		repo:  repo,
+		cfg:   cfg,
+		cache: newAggregateCache(time.Duration(cfg.DashboardCacheTTLSeconds) * time.Second),
+		renderer: &export.Renderer{
+			Company: cfg.CompanyName,
+			Fonts:   export.NewFontLoader(cfg.PDFFontPath),
+		},
+	}
+	// 任何业务数据写入都使仪表盘聚合缓存失效
+	if err := repo.OnWrite(s.cache.invalidate); err != nil {
+		log.Printf("注册缓存失效回调失败, 仪表盘缓存已禁用: %v", err)
+		s.cache = newAggregateCache(0)
+	}
+	return s
+}
+
+// maxDocumentNoAttempts 单号冲突时的最大尝试次数
+const maxDocumentNoAttempts = 3
+
+// createWithDocumentNo 生成单号并创建单据, 单号与已有单据冲突 (唯一索引) 时重新生成后重试
+// create 每次调用都需重新生成单号
+func (s *Service) createWithDocumentNo(create func() error) error {
+	var err error
+	for i := 0; i < maxDocumentNoAttempts; i++ {
+		if err = create(); err == nil || !s.repo.IsDuplicateKey(err) {
+			return err
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return err
 }
 
 // ==================== 认证 ====================
@@ -215,6 +250,7 @@ func (s *Service) CreateSupplier(req *models.SupplierRequest) (*models.Supplier,
 		Email:         req.Email,
 		Address:       req.Address,
 		Remark:        req.Remark,
+		LeadTimeDays:  req.LeadTimeDays,
 		Status:        1,
 	}
 	if req.Status != 0 {
@@ -239,6 +275,7 @@ func (s *Service) UpdateSupplier(id uint, req *models.SupplierRequest) (*models.
 	sup.Email = req.Email
 	sup.Address = req.Address
 	sup.Remark = req.Remark
+	sup.LeadTimeDays = req.LeadTimeDays
 	if req.Status != 0 {
 		sup.Status = req.Status
 	}
@@ -256,8 +293,8 @@ func (s *Service) DeleteSupplier(id uint) error {
 // ==================== 商品 ====================
 
 // ListProducts 获取商品列表
-func (s *Service) ListProducts(query *models.PaginationQuery, categoryID, supplierID *uint) ([]models.Product, int64, error) {
-	return s.repo.ListProducts(query, categoryID, supplierID)
+func (s *Service) ListProducts(query *models.PaginationQuery, filter *models.ProductFilter) ([]models.Product, int64, error) {
+	return s.repo.ListProducts(query, filter)
 }
 
 // GetProduct 获取商品详情
@@ -271,6 +308,9 @@ func (s *Service) CreateProduct(req *models.ProductRequest) (*models.Product, er
 	if _, err := s.repo.GetProductBySKU(req.SKU); err == nil {
 		return nil, fmt.Errorf("SKU '%s' 已存在", req.SKU)
 	}
+	if err := s.checkBarcodeNotAlternate(req.Barcode, 0); err != nil {
+		return nil, err
+	}
 
 	product := &models.Product{
 		SKU:          req.SKU,
@@ -283,6 +323,8 @@ func (s *Service) CreateProduct(req *models.ProductRequest) (*models.Product, er
 		SellingPrice: req.SellingPrice,
 		MinStock:     req.MinStock,
 		MaxStock:     req.MaxStock,
+		SafetyStock:  req.SafetyStock,
+		QtyPrecision: req.QtyPrecision,
 		Barcode:      req.Barcode,
 		Location:     req.Location,
 		ImageURL:     req.ImageURL,
@@ -294,6 +336,9 @@ func (s *Service) CreateProduct(req *models.ProductRequest) (*models.Product, er
 	if req.Status != 0 {
 		product.Status = req.Status
 	}
+	if err := checkStockLevels(product); err != nil {
+		return nil, err
+	}
 
 	if err := s.repo.CreateProduct(product); err != nil {
 		return nil, fmt.Errorf("创建商品失败: %w", err)
@@ -314,6 +359,9 @@ func (s *Service) UpdateProduct(id uint, req *models.ProductRequest) (*models.Pr
 			return nil, fmt.Errorf("SKU '%s' 已存在", req.SKU)
 		}
 	}
+	if err := s.checkBarcodeNotAlternate(req.Barcode, id); err != nil {
+		return nil, err
+	}
 
 	product.SKU = req.SKU
 	product.Name = req.Name
@@ -325,97 +373,215 @@ func (s *Service) UpdateProduct(id uint, req *models.ProductRequest) (*models.Pr
 	product.SellingPrice = req.SellingPrice
 	product.MinStock = req.MinStock
 	product.MaxStock = req.MaxStock
+	product.SafetyStock = req.SafetyStock
+	product.QtyPrecision = req.QtyPrecision
 	product.Barcode = req.Barcode
 	product.Location = req.Location
 	product.ImageURL = req.ImageURL
 	if req.Status != 0 {
 		product.Status = req.Status
 	}
+	if err := checkStockLevels(product); err != nil {
+		return nil, err
+	}
+	if product.ParentID != nil {
+		parent, err := s.repo.GetProductByID(*product.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("款式不存在")
+		}
+		product.PriceOverride = product.CostPrice != parent.CostPrice || product.SellingPrice != parent.SellingPrice
+	}
+	// 降低精度时当前库存须仍能按新精度表示, 否则需先调整库存
+	if !product.ValidQty(product.CurrentStock) {
+		return nil, fmt.Errorf("当前库存 %s 不符合新的数量精度，请先调整库存", formatQty(product.CurrentStock))
+	}
 
 	if err := s.repo.UpdateProduct(product); err != nil {
 		return nil, fmt.Errorf("更新商品失败: %w", err)
 	}
+	if product.HasVariants {
+		if err := s.syncVariants(product); err != nil {
+			return nil, err
+		}
+	}
 	return product, nil
 }
 
-// DeleteProduct 删除商品
+// checkStockLevels 校验最低/最高/安全库存符合商品数量精度
+func checkStockLevels(product *models.Product) error {
+	for _, qty := range []float64{product.MinStock, product.MaxStock, product.SafetyStock} {
+		if err := checkQty(product, qty); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteProduct 删除商品 (款式须先删除全部规格, 组件须先从组合商品中移除)
 func (s *Service) DeleteProduct(id uint) error {
+	if product, err := s.repo.GetProductByID(id); err == nil && product.VariantCount > 0 {
+		return fmt.Errorf("款式 %s 下还有 %d 个规格，请先删除规格", product.SKU, product.VariantCount)
+	}
+	if count, err := s.repo.CountKitsUsingComponent(id); err == nil && count > 0 {
+		return fmt.Errorf("商品是 %d 个组合商品的组件，请先修改物料清单", count)
+	}
 	return s.repo.DeleteProduct(id)
 }
 
 // ==================== 库存操作 ====================
 
-// StockIn 入库操作
-func (s *Service) StockIn(req *models.StockInRequest, operatorID uint, operatorName string) error {
+// StockIn 入库操作, 返回已写入的库存记录
+func (s *Service) StockIn(req *models.StockInRequest, operatorID uint, operatorName string) (*models.InventoryRecord, error) {
 	product, err := s.repo.GetProductByID(req.ProductID)
 	if err != nil {
-		return fmt.Errorf("商品不存在")
+		return nil, fmt.Errorf("商品不存在")
+	}
+	if err := checkStockable(product); err != nil {
+		return nil, err
+	}
+	if err := s.checkNotFrozen(req.ProductID); err != nil {
+		return nil, err
+	}
+	var expiry *time.Time
+	if req.ExpiryDate != "" {
+		t, err := time.ParseInLocation("2006-01-02", req.ExpiryDate, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("有效期格式应为 YYYY-MM-DD")
+		}
+		expiry = &t
+	}
+	qty, err := s.toBaseUnit(product, req.Unit, req.Quantity, req.UnitCost)
+	if err != nil {
+		return nil, err
+	}
+	if req.LocationID != nil {
+		if err := s.checkBin(*req.LocationID, req.ProductID, qty.base, 0, 0); err != nil {
+			return nil, err
+		}
 	}
 
 	beforeQty := product.CurrentStock
-	afterQty := beforeQty + req.Quantity
-	totalCost := float64(req.Quantity) * req.UnitCost
+	afterQty := product.RoundQty(beforeQty + qty.base)
+	totalCost := req.Quantity * req.UnitCost
 
 	record := &models.InventoryRecord{
-		ProductID:    req.ProductID,
-		Type:         models.StockIn,
-		Quantity:     req.Quantity,
-		BeforeQty:    beforeQty,
-		AfterQty:     afterQty,
-		UnitCost:     req.UnitCost,
-		TotalCost:    totalCost,
-		ReferenceNo:  req.ReferenceNo,
-		Notes:        req.Notes,
-		OperatorID:   operatorID,
-		OperatorName: operatorName,
+		ProductID:     req.ProductID,
+		Type:          models.StockIn,
+		Quantity:      qty.base,
+		EntryQuantity: qty.entered,
+		EntryUnit:     qty.unit,
+		BeforeQty:     beforeQty,
+		AfterQty:      afterQty,
+		UnitCost:      qty.unitCost,
+		TotalCost:     totalCost,
+		ReferenceNo:   req.ReferenceNo,
+		LotNo:         req.LotNo,
+		ExpiryDate:    expiry,
+		LocationID:    req.LocationID,
+		Notes:         req.Notes,
+		OperatorID:    operatorID,
+		OperatorName:  operatorName,
 	}
 
-	return s.repo.StockOperation(record, afterQty)
+	if err := s.repo.StockOperation(record, afterQty); err != nil {
+		return nil, err
+	}
+	return record, nil
 }
 
-// StockOut 出库操作
-func (s *Service) StockOut(req *models.StockOutRequest, operatorID uint, operatorName string) error {
+// StockOut 出库操作, 返回已写入的库存记录; 虚拟组合商品按物料清单扣减组件, 不返回记录
+func (s *Service) StockOut(req *models.StockOutRequest, operatorID uint, operatorName string) (*models.InventoryRecord, error) {
 	product, err := s.repo.GetProductByID(req.ProductID)
 	if err != nil {
-		return fmt.Errorf("商品不存在")
+		return nil, fmt.Errorf("商品不存在")
+	}
+	if product.KitType == models.KitTypeVirtual {
+		if req.LocationID != nil {
+			return nil, fmt.Errorf("虚拟组合商品不能指定出库货位")
+		}
+		return nil, s.virtualKitStockOut(product, req, operatorID, operatorName)
+	}
+	if err := checkStockable(product); err != nil {
+		return nil, err
+	}
+	if err := s.checkNotFrozen(req.ProductID); err != nil {
+		return nil, err
 	}
 
-	if product.CurrentStock < req.Quantity {
-		return fmt.Errorf("库存不足，当前库存: %d，请求出库: %d", product.CurrentStock, req.Quantity)
+	qty, err := s.toBaseUnit(product, req.Unit, req.Quantity, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.CurrentStock < qty.base {
+		return nil, fmt.Errorf("库存不足，当前库存: %s，请求出库: %s", formatQty(product.CurrentStock), formatQty(qty.base))
+	}
+	if req.LocationID != nil {
+		if err := s.checkBin(*req.LocationID, req.ProductID, -qty.base, 0, 0); err != nil {
+			return nil, err
+		}
 	}
 
 	beforeQty := product.CurrentStock
-	afterQty := beforeQty - req.Quantity
+	afterQty := product.RoundQty(beforeQty - qty.base)
 
 	record := &models.InventoryRecord{
-		ProductID:    req.ProductID,
-		Type:         models.StockOut,
-		Quantity:     req.Quantity,
-		BeforeQty:    beforeQty,
-		AfterQty:     afterQty,
-		ReferenceNo:  req.ReferenceNo,
-		Notes:        req.Notes,
-		OperatorID:   operatorID,
-		OperatorName: operatorName,
+		ProductID:     req.ProductID,
+		Type:          models.StockOut,
+		Quantity:      qty.base,
+		EntryQuantity: qty.entered,
+		EntryUnit:     qty.unit,
+		BeforeQty:     beforeQty,
+		AfterQty:      afterQty,
+		ReferenceNo:   req.ReferenceNo,
+		LotNo:         req.LotNo,
+		LocationID:    req.LocationID,
+		Notes:         req.Notes,
+		OperatorID:    operatorID,
+		OperatorName:  operatorName,
 	}
 
-	return s.repo.StockOperation(record, afterQty)
+	if err := s.repo.StockOperation(record, afterQty); err != nil {
+		return nil, err
+	}
+	return record, nil
 }
 
 // StockAdjust 库存调整
-func (s *Service) StockAdjust(req *models.StockAdjustRequest, operatorID uint, operatorName string) error {
+// 超出审批阈值时不改动库存, 返回待审批的调整单
+func (s *Service) StockAdjust(req *models.StockAdjustRequest, operatorID uint, operatorName string) (*models.StockAdjustment, error) {
 	product, err := s.repo.GetProductByID(req.ProductID)
 	if err != nil {
-		return fmt.Errorf("商品不存在")
+		return nil, fmt.Errorf("商品不存在")
+	}
+	if err := checkStockable(product); err != nil {
+		return nil, err
+	}
+	if err := s.checkNotFrozen(req.ProductID); err != nil {
+		return nil, err
+	}
+	if err := s.validateReasonCode(req.ReasonCode); err != nil {
+		return nil, err
+	}
+	if err := checkQty(product, req.NewQuantity); err != nil {
+		return nil, err
 	}
 
 	beforeQty := product.CurrentStock
-	diff := req.NewQuantity - beforeQty
+	diff := product.RoundQty(req.NewQuantity - beforeQty)
 	quantity := diff
 	if quantity < 0 {
 		quantity = -quantity
 	}
 
+	if s.adjustNeedsApproval(quantity, product.CostPrice) {
+		adj := newPendingAdjustment(product, beforeQty, req.NewQuantity, diff, req.ReasonCode, req.Notes, operatorID, operatorName)
+		if err := s.repo.CreateStockAdjustment(adj); err != nil {
+			return nil, fmt.Errorf("提交调整审批失败: %w", err)
+		}
+		return adj, nil
+	}
+
 	record := &models.InventoryRecord{
 		ProductID:    req.ProductID,
 		Type:         models.StockAdjust,
@@ -423,31 +589,446 @@ func (s *Service) StockAdjust(req *models.StockAdjustRequest, operatorID uint, o
 		BeforeQty:    beforeQty,
 		AfterQty:     req.NewQuantity,
 		Notes:        req.Notes,
+		ReasonCode:   req.ReasonCode,
 		OperatorID:   operatorID,
 		OperatorName: operatorName,
 	}
 
-	return s.repo.StockOperation(record, req.NewQuantity)
+	return nil, s.repo.StockOperation(record, req.NewQuantity)
+}
+
+// batchLine 批量操作中的单行, 由各类批量请求统一转换而来
+type batchLine struct {
+	productID   uint
+	quantity    float64 // 入库/出库数量 (按录入单位)
+	unit        string  // 录入单位, 为空时为基本单位
+	newQuantity float64 // 调整后的目标数量
+	unitCost    float64
+	reasonCode  string
+	locationID  *uint // 入库/出库货位
+	notes       string
+}
+
+// BatchStockIn 批量入库 (全部成功或全部回滚)
+func (s *Service) BatchStockIn(req *models.BatchStockInRequest, operatorID uint, operatorName string) (*models.BatchStockResult, error) {
+	lines := make([]batchLine, len(req.Lines))
+	for i, l := range req.Lines {
+		lines[i] = batchLine{productID: l.ProductID, quantity: l.Quantity, unit: l.Unit, unitCost: l.UnitCost, locationID: l.LocationID, notes: l.Notes}
+	}
+	return s.runBatch(models.StockIn, req.ReferenceNo, req.Notes, lines, operatorID, operatorName)
+}
+
+// BatchStockOut 批量出库 (全部成功或全部回滚)
+func (s *Service) BatchStockOut(req *models.BatchStockOutRequest, operatorID uint, operatorName string) (*models.BatchStockResult, error) {
+	lines := make([]batchLine, len(req.Lines))
+	for i, l := range req.Lines {
+		lines[i] = batchLine{productID: l.ProductID, quantity: l.Quantity, unit: l.Unit, locationID: l.LocationID, notes: l.Notes}
+	}
+	return s.runBatch(models.StockOut, req.ReferenceNo, req.Notes, lines, operatorID, operatorName)
+}
+
+// BatchStockAdjust 批量库存调整 (全部成功或全部回滚)
+func (s *Service) BatchStockAdjust(req *models.BatchStockAdjustRequest, operatorID uint, operatorName string) (*models.BatchStockResult, error) {
+	lines := make([]batchLine, len(req.Lines))
+	for i, l := range req.Lines {
+		lines[i] = batchLine{productID: l.ProductID, newQuantity: l.NewQuantity, reasonCode: l.ReasonCode, notes: l.Notes}
+	}
+	return s.runBatch(models.StockAdjust, req.ReferenceNo, req.Notes, lines, operatorID, operatorName)
+}
+
+// runBatch 先校验全部明细行, 全部通过后在单一事务中入账
+// 同一商品出现在多行时按行顺序累计计算库存; 超出审批阈值的调整行在同一事务中生成待审批调整单
+func (s *Service) runBatch(recordType models.InventoryRecordType, referenceNo, notes string, lines []batchLine, operatorID uint, operatorName string) (*models.BatchStockResult, error) {
+	if referenceNo == "" {
+		referenceNo = "BATCH-" + time.Now().Format("20060102150405")
+	}
+
+	result := &models.BatchStockResult{
+		ReferenceNo: referenceNo,
+		Lines:       make([]models.BatchLineResult, len(lines)),
+	}
+	records := make([]*models.InventoryRecord, 0, len(lines))
+	recordLines := make([]int, 0, len(lines)) // 各记录对应的行下标
+	var adjustments []*models.StockAdjustment // 超出审批阈值的调整行, 生成待审批调整单
+	var adjustmentLines []int
+	stock := make(map[uint]float64)     // 商品ID -> 批内累计后的库存
+	binUsed := make(map[uint]float64)   // 货位ID -> 批内此前各行的已用量变化
+	binQty := make(map[[2]uint]float64) // [货位ID, 商品ID] -> 批内此前各行的数量变化
+	failed := 0
+
+	for i, line := range lines {
+		lr := &result.Lines[i]
+		lr.Line = i + 1
+		lr.ProductID = line.productID
+
+		product, err := s.repo.GetProductByID(line.productID)
+		if err != nil {
+			lr.Error = "商品不存在"
+			failed++
+			continue
+		}
+		if err := checkStockable(product); err != nil {
+			lr.Error = err.Error()
+			failed++
+			continue
+		}
+		beforeQty, ok := stock[line.productID]
+		if !ok {
+			beforeQty = product.CurrentStock
+			if err := s.checkNotFrozen(line.productID); err != nil {
+				lr.Error = err.Error()
+				failed++
+				continue
+			}
+		}
+
+		var afterQty, quantity, entryQuantity float64
+		var entryUnit string
+		unitCost := line.unitCost
+		if recordType == models.StockIn || recordType == models.StockOut {
+			qty, err := s.toBaseUnit(product, line.unit, line.quantity, line.unitCost)
+			if err != nil {
+				lr.Error = err.Error()
+				failed++
+				continue
+			}
+			quantity, entryQuantity, entryUnit, unitCost = qty.base, qty.entered, qty.unit, qty.unitCost
+		}
+
+		switch recordType {
+		case models.StockIn:
+			afterQty = product.RoundQty(beforeQty + quantity)
+		case models.StockOut:
+			if beforeQty < quantity {
+				lr.BeforeQty = beforeQty
+				lr.Error = fmt.Sprintf("库存不足，当前库存: %s，请求出库: %s", formatQty(beforeQty), formatQty(quantity))
+				failed++
+				continue
+			}
+			afterQty = product.RoundQty(beforeQty - quantity)
+		case models.StockAdjust:
+			if err := checkQty(product, line.newQuantity); err != nil {
+				lr.Error = err.Error()
+				failed++
+				continue
+			}
+			afterQty = line.newQuantity
+			quantity = product.RoundQty(afterQty - beforeQty)
+			if quantity < 0 {
+				quantity = -quantity
+			}
+			if err := s.validateReasonCode(line.reasonCode); err != nil {
+				lr.Error = err.Error()
+				failed++
+				continue
+			}
+			if s.adjustNeedsApproval(quantity, product.CostPrice) {
+				lineNotes := line.notes
+				if lineNotes == "" {
+					lineNotes = notes
+				}
+				// 待审批的调整不改动库存, 批内后续行仍以当前数量为准
+				stock[line.productID] = beforeQty
+				lr.BeforeQty = beforeQty
+				lr.AfterQty = beforeQty
+				lr.Pending = true
+				adjustments = append(adjustments, newPendingAdjustment(product, beforeQty, afterQty,
+					product.RoundQty(afterQty-beforeQty), line.reasonCode, lineNotes, operatorID, operatorName))
+				adjustmentLines = append(adjustmentLines, i)
+				continue
+			}
+		}
+
+		if line.locationID != nil {
+			delta := afterQty - beforeQty
+			key := [2]uint{*line.locationID, line.productID}
+			if err := s.checkBin(*line.locationID, line.productID, delta, binUsed[*line.locationID], binQty[key]); err != nil {
+				lr.BeforeQty = beforeQty
+				lr.Error = err.Error()
+				failed++
+				continue
+			}
+			binUsed[*line.locationID] += delta
+			binQty[key] += delta
+		}
+
+		lineNotes := line.notes
+		if lineNotes == "" {
+			lineNotes = notes
+		}
+
+		stock[line.productID] = afterQty
+		lr.BeforeQty = beforeQty
+		lr.AfterQty = afterQty
+		recordLines = append(recordLines, i)
+		records = append(records, &models.InventoryRecord{
+			ProductID:     line.productID,
+			Type:          recordType,
+			Quantity:      quantity,
+			EntryQuantity: entryQuantity,
+			EntryUnit:     entryUnit,
+			BeforeQty:     beforeQty,
+			AfterQty:      afterQty,
+			UnitCost:      unitCost,
+			TotalCost:     line.quantity * line.unitCost,
+			ReferenceNo:   referenceNo,
+			LocationID:    line.locationID,
+			Notes:         lineNotes,
+			ReasonCode:    line.reasonCode,
+			OperatorID:    operatorID,
+			OperatorName:  operatorName,
+		})
+	}
+
+	if failed > 0 {
+		return result, fmt.Errorf("批量操作校验失败: %d 行有误，未做任何变更", failed)
+	}
+
+	if err := s.repo.BatchStockOperation(records, adjustments); err != nil {
+		return result, fmt.Errorf("批量操作失败，已全部回滚: %w", err)
+	}
+
+	for i, record := range records {
+		result.Lines[recordLines[i]].Success = true
+		result.Lines[recordLines[i]].RecordID = record.ID
+	}
+	for i, adj := range adjustments {
+		result.Lines[adjustmentLines[i]].Success = true
+		result.Lines[adjustmentLines[i]].AdjustmentID = adj.ID
+	}
+	result.Applied = true
+	return result, nil
 }
 
 // ListInventoryRecords 查询库存记录
-func (s *Service) ListInventoryRecords(query *models.PaginationQuery, productID *uint, recordType, startDate, endDate string) ([]models.InventoryRecord, int64, error) {
-	return s.repo.ListInventoryRecords(query, productID, recordType, startDate, endDate)
+func (s *Service) ListInventoryRecords(query *models.PaginationQuery, productID *uint, recordType, startDate, endDate string, excludeVoided bool) ([]models.InventoryRecord, int64, error) {
+	return s.repo.ListInventoryRecords(query, productID, recordType, startDate, endDate, excludeVoided)
+}
+
+// VoidInventoryRecord 冲销库存记录: 生成数量相等、方向相反的冲销记录并标记原记录
+// 冲销同样改动库存, 与调整一样受审批阈值约束: 超出阈值时不改动库存, 返回待审批的调整单
+func (s *Service) VoidInventoryRecord(id uint, req *models.VoidRecordRequest, operatorID uint, operatorName string) (*models.InventoryRecord, *models.StockAdjustment, error) {
+	original, product, reversal, err := s.prepareVoid(id, req.Reason, operatorID, operatorName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if s.adjustNeedsApproval(original.Quantity, product.CostPrice) {
+		count, err := s.repo.CountPendingVoidAdjustments(original.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if count > 0 {
+			return nil, nil, fmt.Errorf("该记录已有待审批的冲销申请")
+		}
+		adj := newPendingAdjustment(product, reversal.BeforeQty, reversal.AfterQty,
+			product.RoundQty(reversal.AfterQty-reversal.BeforeQty), original.ReasonCode, req.Reason, operatorID, operatorName)
+		adj.VoidRecordID = &original.ID
+		if err := s.repo.CreateStockAdjustment(adj); err != nil {
+			return nil, nil, fmt.Errorf("提交冲销审批失败: %w", err)
+		}
+		return nil, adj, nil
+	}
+
+	if err := s.repo.VoidInventoryRecord(original, reversal); err != nil {
+		return nil, nil, fmt.Errorf("冲销失败: %w", err)
+	}
+	return reversal, nil, nil
+}
+
+// prepareVoid 校验记录可否冲销, 按当前库存构造冲销记录
+func (s *Service) prepareVoid(id uint, reason string, operatorID uint, operatorName string) (*models.InventoryRecord, *models.Product, *models.InventoryRecord, error) {
+	original, err := s.repo.GetInventoryRecordByID(id)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("库存记录不存在")
+	}
+	if original.Voided {
+		return nil, nil, nil, fmt.Errorf("记录已被冲销")
+	}
+	if original.Type == models.StockVoid {
+		return nil, nil, nil, fmt.Errorf("冲销记录不能再次冲销")
+	}
+	if original.Type == models.StockAssemble || original.Type == models.StockDisassemble {
+		return nil, nil, nil, fmt.Errorf("组装/拆卸记录不能单独冲销，请做反向的拆卸/组装")
+	}
+	if original.Type == models.StockMove {
+		return nil, nil, nil, fmt.Errorf("移库记录不能冲销，请做反向移库")
+	}
+	if original.Type == models.StockReturn {
+		return nil, nil, nil, fmt.Errorf("退供应商记录不能单独冲销")
+	}
+	if err := s.checkNotFrozen(original.ProductID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	product, err := s.repo.GetProductByID(original.ProductID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("商品不存在")
+	}
+	if err := checkStockable(product); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// 原记录对库存的净影响, 冲销时反向抵消
+	delta := original.AfterQty - original.BeforeQty
+	afterQty := product.RoundQty(product.CurrentStock - delta)
+	if afterQty < 0 {
+		return nil, nil, nil, fmt.Errorf("冲销后库存为负 (当前 %s，需扣减 %s)，无法冲销", formatQty(product.CurrentStock), formatQty(delta))
+	}
+
+	reversal := &models.InventoryRecord{
+		ProductID:    original.ProductID,
+		Type:         models.StockVoid,
+		Quantity:     original.Quantity,
+		BeforeQty:    product.CurrentStock,
+		AfterQty:     afterQty,
+		UnitCost:     original.UnitCost,
+		TotalCost:    original.TotalCost,
+		ReferenceNo:  original.ReferenceNo,
+		Notes:        reason,
+		ReasonCode:   original.ReasonCode,
+		OperatorID:   operatorID,
+		OperatorName: operatorName,
+		ReversalOfID: &original.ID,
+		LocationID:   original.LocationID,
+		CreatedAt:    time.Now(),
+	}
+	return original, product, reversal, nil
 }
 
 // ==================== 仪表盘 ====================
 
 // GetDashboardStats 获取仪表盘统计
 func (s *Service) GetDashboardStats() (*models.DashboardStats, error) {
-	return s.repo.GetDashboardStats()
+	return cached(s.cache, "dashboard:stats", s.repo.GetDashboardStats)
 }
 
+// maxChartPeriods 图表最多返回的期数
+const maxChartPeriods = 1000
+
 // GetChartData 获取图表数据
-func (s *Service) GetChartData() (*models.ChartData, error) {
-	return s.repo.GetChartData()
+// 出入库趋势按日分组查询后在内存中归并到周/月, 缺失的期补 0
+func (s *Service) GetChartData(q *models.ChartQuery) (*models.ChartData, error) {
+	from, to, _, err := normalizeChartQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	// 以解析后的日期范围为键, 不同写法的同一范围共用缓存, 默认范围随日期切换
+	key := fmt.Sprintf("dashboard:charts:%s:%s:%s:%s:%s", from.Format("2006-01-02"), to.Format("2006-01-02"),
+		q.Granularity, optionalID(q.CategoryID), optionalID(q.SupplierID))
+	return cached(s.cache, key, func() (*models.ChartData, error) { return s.loadChartData(q) })
+}
+
+// ValidateChartQuery 校验图表参数 (粒度、日期范围与期数)
+func (s *Service) ValidateChartQuery(q *models.ChartQuery) error {
+	_, _, _, err := normalizeChartQuery(q)
+	return err
+}
+
+// normalizeChartQuery 填充默认粒度, 解析日期范围 [from, to) 并划分各期起始日
+func normalizeChartQuery(q *models.ChartQuery) (from, to time.Time, buckets []time.Time, err error) {
+	if q.Granularity == "" {
+		q.Granularity = "day"
+	}
+	if q.Granularity != "day" && q.Granularity != "week" && q.Granularity != "month" {
+		return from, to, nil, fmt.Errorf("granularity 仅支持 day / week / month")
+	}
+	if from, to, err = parseDateRange(q.From, q.To, 30); err != nil {
+		return from, to, nil, err
+	}
+	for t := chartBucket(from, q.Granularity); t.Before(to); t = nextChartBucket(t, q.Granularity) {
+		buckets = append(buckets, t)
+		if len(buckets) > maxChartPeriods {
+			return from, to, nil, fmt.Errorf("时间范围过大, 最多 %d 期, 请缩小范围或使用更大的粒度", maxChartPeriods)
+		}
+	}
+	return from, to, buckets, nil
+}
+
+// loadChartData 查询图表数据
+func (s *Service) loadChartData(q *models.ChartQuery) (*models.ChartData, error) {
+	from, to, buckets, err := normalizeChartQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	daily, err := s.repo.GetDailyMovements(from, to, q)
+	if err != nil {
+		return nil, fmt.Errorf("统计出入库趋势失败: %w", err)
+	}
+	data := &models.ChartData{
+		From:          from.Format("2006-01-02"),
+		To:            to.AddDate(0, 0, -1).Format("2006-01-02"),
+		Granularity:   q.Granularity,
+		StockMovement: make([]models.DailyMovement, len(buckets)),
+	}
+	index := make(map[time.Time]int, len(buckets))
+	for i, b := range buckets {
+		index[b] = i
+		data.StockMovement[i].Date = chartLabel(b, q.Granularity)
+	}
+	for _, d := range daily {
+		day, err := time.ParseInLocation("2006-01-02", d.Date, time.Local)
+		if err != nil {
+			continue
+		}
+		if i, ok := index[chartBucket(day, q.Granularity)]; ok {
+			data.StockMovement[i].StockIn = models.RoundQty(data.StockMovement[i].StockIn+d.StockIn, models.MaxQtyPrecision)
+			data.StockMovement[i].StockOut = models.RoundQty(data.StockMovement[i].StockOut+d.StockOut, models.MaxQtyPrecision)
+		}
+	}
+
+	if data.TopProducts, err = s.repo.GetTopStockValueProducts(q, 10); err != nil {
+		return nil, fmt.Errorf("统计库存价值排行失败: %w", err)
+	}
+	if data.CategoryStats, err = s.repo.GetCategoryProductStats(q); err != nil {
+		return nil, fmt.Errorf("统计分类商品数量失败: %w", err)
+	}
+	return data, nil
+}
+
+// optionalID 将可选 ID 格式化为缓存键片段
+func optionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// chartBucket 返回日期所在期的起始日
+func chartBucket(day time.Time, granularity string) time.Time {
+	switch granularity {
+	case "week":
+		offset := (int(day.Weekday()) + 6) % 7 // 周一为 0
+		return day.AddDate(0, 0, -offset)
+	case "month":
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	}
+	return day
+}
+
+// nextChartBucket 返回下一期的起始日
+func nextChartBucket(t time.Time, granularity string) time.Time {
+	switch granularity {
+	case "week":
+		return t.AddDate(0, 0, 7)
+	case "month":
+		return t.AddDate(0, 1, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// chartLabel 返回期的显示标签
+func chartLabel(t time.Time, granularity string) string {
+	if granularity == "month" {
+		return t.Format("2006-01")
+	}
+	return t.Format("2006-01-02")
 }
 
 // GetLowStockProducts 获取低库存商品
 func (s *Service) GetLowStockProducts() ([]models.Product, error) {
-	return s.repo.GetLowStockProducts(20)
+	return cached(s.cache, "dashboard:low-stock", func() ([]models.Product, error) {
+		return s.repo.GetLowStockProducts(20)
+	})
 }
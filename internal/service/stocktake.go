@@ -0,0 +1,253 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 盘点 ====================
+
+// CreateStocktake 创建盘点单, 按范围快照账面数量与成本
+func (s *Service) CreateStocktake(req *models.StocktakeRequest, operatorID uint, operatorName string) (*models.StocktakeSession, error) {
+	switch req.ScopeType {
+	case models.StocktakeScopeCategory:
+		if req.CategoryID == nil || *req.CategoryID == 0 {
+			return nil, fmt.Errorf("按分类盘点需指定分类")
+		}
+	case models.StocktakeScopeLocation:
+		if req.LocationPrefix == "" {
+			return nil, fmt.Errorf("按库位盘点需指定库位前缀")
+		}
+	}
+
+	products, err := s.repo.GetProductsForStocktakeScope(req.ScopeType, req.CategoryID, req.LocationPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("查询盘点商品失败: %w", err)
+	}
+	if len(products) == 0 {
+		return nil, fmt.Errorf("盘点范围内没有商品")
+	}
+
+	session := &models.StocktakeSession{
+		Name:           req.Name,
+		ScopeType:      req.ScopeType,
+		CategoryID:     req.CategoryID,
+		LocationPrefix: req.LocationPrefix,
+		Freeze:         req.Freeze,
+		Status:         models.StocktakeCounting,
+		Notes:          req.Notes,
+		CreatedBy:      operatorID,
+		CreatedByName:  operatorName,
+	}
+	for _, p := range products {
+		session.Items = append(session.Items, models.StocktakeItem{
+			ProductID:   p.ID,
+			ExpectedQty: p.CurrentStock,
+			UnitCost:    p.CostPrice,
+		})
+	}
+
+	err = s.createWithDocumentNo(func() error {
+		session.SessionNo = "ST-" + strings.Replace(time.Now().Format("20060102150405.000"), ".", "", 1)
+		return s.repo.CreateStocktake(session)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建盘点单失败: %w", err)
+	}
+	return session, nil
+}
+
+// ListStocktakes 获取盘点单列表
+func (s *Service) ListStocktakes(query *models.PaginationQuery, status string) ([]models.StocktakeSession, int64, error) {
+	return s.repo.ListStocktakes(query, status)
+}
+
+// GetStocktake 获取盘点单详情
+func (s *Service) GetStocktake(id uint) (*models.StocktakeSession, error) {
+	return s.repo.GetStocktakeByID(id)
+}
+
+// SubmitStocktakeCounts 提交盘点计数
+func (s *Service) SubmitStocktakeCounts(id uint, req *models.StocktakeCountRequest, counterID uint, counterName string) error {
+	session, err := s.repo.GetStocktakeByID(id)
+	if err != nil {
+		return fmt.Errorf("盘点单不存在")
+	}
+	if session.Status != models.StocktakeCounting {
+		return fmt.Errorf("盘点单已结束，无法提交计数")
+	}
+
+	counts := make([]*models.StocktakeCount, 0, len(req.Lines))
+	for _, line := range req.Lines {
+		item, err := s.repo.GetStocktakeItem(id, line.ProductID)
+		if err != nil {
+			return fmt.Errorf("商品 %d 不在盘点范围内", line.ProductID)
+		}
+		product, err := s.repo.GetProductByID(line.ProductID)
+		if err != nil {
+			return fmt.Errorf("商品 %d 不存在", line.ProductID)
+		}
+		if err := checkQty(product, line.Quantity); err != nil {
+			return err
+		}
+		counts = append(counts, &models.StocktakeCount{
+			SessionID:   id,
+			ItemID:      item.ID,
+			ProductID:   line.ProductID,
+			CounterID:   counterID,
+			CounterName: counterName,
+			Quantity:    line.Quantity,
+		})
+	}
+
+	if err := s.repo.SaveStocktakeCounts(counts); err != nil {
+		return fmt.Errorf("保存计数失败: %w", err)
+	}
+	return nil
+}
+
+// ListStocktakeCounts 获取盘点单的计数明细
+func (s *Service) ListStocktakeCounts(id uint) ([]models.StocktakeCount, error) {
+	return s.repo.ListStocktakeCounts(id)
+}
+
+// GetStocktakeVariances 获取盘点差异及金额影响
+func (s *Service) GetStocktakeVariances(id uint) (*models.StocktakeVarianceReport, error) {
+	session, err := s.repo.GetStocktakeByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("盘点单不存在")
+	}
+
+	report := &models.StocktakeVarianceReport{
+		Session:    session,
+		TotalItems: len(session.Items),
+		Items:      []models.StocktakeItem{},
+	}
+	for _, item := range session.Items {
+		if item.CountedQty == nil {
+			continue
+		}
+		report.CountedItems++
+		if item.VarianceQty == 0 {
+			continue
+		}
+		report.VarianceItems++
+		if item.VarianceQty > 0 {
+			report.GainQty += item.VarianceQty
+			report.GainValue += item.VarianceValue
+		} else {
+			report.LossQty -= item.VarianceQty
+			report.LossValue -= item.VarianceValue
+		}
+		report.Items = append(report.Items, item)
+	}
+	report.GainQty = models.RoundQty(report.GainQty, models.MaxQtyPrecision)
+	report.LossQty = models.RoundQty(report.LossQty, models.MaxQtyPrecision)
+	report.NetValue = report.GainValue - report.LossValue
+	return report, nil
+}
+
+// ApproveStocktake 审核盘点单, 将差异过账为调整记录
+// 调整量为 实盘 - 快照, 叠加到当前库存上, 以兼容未冻结期间发生的出入库
+func (s *Service) ApproveStocktake(id uint, req *models.StocktakeApproveRequest, approverID uint, approverName string) (*models.StocktakeSession, error) {
+	session, err := s.repo.GetStocktakeByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("盘点单不存在")
+	}
+	if session.Status != models.StocktakeCounting {
+		return nil, fmt.Errorf("盘点单状态为 %s，无法审核", session.Status)
+	}
+
+	notes := "盘点过账 " + session.SessionNo
+	if req.Notes != "" {
+		notes += ": " + req.Notes
+	}
+
+	items := make([]*models.StocktakeItem, 0, len(session.Items))
+	records := make([]*models.InventoryRecord, 0, len(session.Items))
+	for i := range session.Items {
+		item := &session.Items[i]
+		if item.CountedQty == nil {
+			if !req.ZeroUncounted {
+				continue
+			}
+			zero := 0.0
+			item.CountedQty = &zero
+			item.VarianceQty = -item.ExpectedQty
+			item.VarianceValue = item.VarianceQty * item.UnitCost
+		}
+		items = append(items, item)
+		if item.VarianceQty == 0 {
+			records = append(records, nil)
+			continue
+		}
+
+		product, err := s.repo.GetProductByID(item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("商品 %d 不存在", item.ProductID)
+		}
+		afterQty := product.RoundQty(product.CurrentStock + item.VarianceQty)
+		if afterQty < 0 {
+			return nil, fmt.Errorf("商品 %s 过账后库存为负 (当前 %s，差异 %s)", product.SKU, formatQty(product.CurrentStock), formatQty(item.VarianceQty))
+		}
+		quantity := item.VarianceQty
+		if quantity < 0 {
+			quantity = -quantity
+		}
+		records = append(records, &models.InventoryRecord{
+			ProductID:    item.ProductID,
+			Type:         models.StockAdjust,
+			Quantity:     quantity,
+			BeforeQty:    product.CurrentStock,
+			AfterQty:     afterQty,
+			UnitCost:     item.UnitCost,
+			TotalCost:    quantity * item.UnitCost,
+			ReferenceNo:  session.SessionNo,
+			Notes:        notes,
+			ReasonCode:   models.ReasonCountCorrection,
+			OperatorID:   approverID,
+			OperatorName: approverName,
+			SourceType:   models.SourceStocktake,
+			SourceID:     session.ID,
+		})
+	}
+
+	now := time.Now()
+	session.Status = models.StocktakeApproved
+	session.ApprovedBy = approverID
+	session.ApprovedByName = approverName
+	session.ApprovedAt = &now
+
+	if err := s.repo.ApproveStocktake(session, items, records); err != nil {
+		return nil, fmt.Errorf("盘点过账失败: %w", err)
+	}
+	return session, nil
+}
+
+// CancelStocktake 取消盘点单 (解除冻结, 不过账)
+func (s *Service) CancelStocktake(id uint) error {
+	session, err := s.repo.GetStocktakeByID(id)
+	if err != nil {
+		return fmt.Errorf("盘点单不存在")
+	}
+	if session.Status != models.StocktakeCounting {
+		return fmt.Errorf("盘点单状态为 %s，无法取消", session.Status)
+	}
+	session.Status = models.StocktakeCancelled
+	return s.repo.UpdateStocktake(session)
+}
+
+// checkNotFrozen 检查商品未被进行中的盘点冻结
+func (s *Service) checkNotFrozen(productID uint) error {
+	frozen, err := s.repo.IsProductFrozen(productID)
+	if err != nil {
+		return fmt.Errorf("检查盘点冻结状态失败: %w", err)
+	}
+	if frozen {
+		return fmt.Errorf("商品 %d 正在盘点中，已冻结出入库", productID)
+	}
+	return nil
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 退供应商 ====================
+
+// CreateSupplierReturn 创建退供应商单 (草稿, 不改动库存)
+// 各商品须属于该供应商 (与退货率报表按商品供应商归集入库一致)
+// 关联原入库单时, 各商品须在该入库单中, 且累计退货数量不超过入库数量; 未指定退货单价时取原入库成本, 其次为商品成本价
+func (s *Service) CreateSupplierReturn(req *models.SupplierReturnRequest, operatorID uint, operatorName string) (*models.SupplierReturn, error) {
+	supplier, err := s.repo.GetSupplierByID(req.SupplierID)
+	if err != nil {
+		return nil, fmt.Errorf("供应商不存在")
+	}
+	receiptNo := strings.TrimSpace(req.ReceiptNo)
+	if receiptNo != "" {
+		if count, err := s.repo.CountReceiptRecords(receiptNo); err != nil || count == 0 {
+			return nil, fmt.Errorf("原入库单 %s 不存在", receiptNo)
+		}
+	}
+
+	ret := &models.SupplierReturn{
+		SupplierID:    supplier.ID,
+		ReceiptNo:     receiptNo,
+		Status:        models.SupplierReturnDraft,
+		Reason:        req.Reason,
+		Notes:         req.Notes,
+		CreatedBy:     operatorID,
+		CreatedByName: operatorName,
+	}
+	pending := make(map[uint]float64) // 商品ID -> 本单此前各行的退货数量
+	for _, line := range req.Items {
+		product, err := s.repo.GetProductByID(line.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("商品 %d 不存在", line.ProductID)
+		}
+		if err := checkStockable(product); err != nil {
+			return nil, err
+		}
+		if product.SupplierID == nil || *product.SupplierID != supplier.ID {
+			return nil, fmt.Errorf("商品 %s 不属于供应商 %s", product.SKU, supplier.Name)
+		}
+		var unitCost float64
+		if line.UnitCost != nil {
+			unitCost = *line.UnitCost
+		}
+		qty, err := s.toBaseUnit(product, line.Unit, line.Quantity, unitCost)
+		if err != nil {
+			return nil, err
+		}
+		if line.UnitCost == nil {
+			qty.unitCost = product.CostPrice
+		}
+
+		if receiptNo != "" {
+			received, receiptCost, err := s.repo.GetReceiptQty(receiptNo, product.ID)
+			if err != nil {
+				return nil, err
+			}
+			if received == 0 {
+				return nil, fmt.Errorf("商品 %s 不在原入库单 %s 中", product.SKU, receiptNo)
+			}
+			returned, err := s.repo.GetReturnedQtyForReceipt(receiptNo, product.ID)
+			if err != nil {
+				return nil, err
+			}
+			returned = product.RoundQty(returned + pending[product.ID])
+			if product.RoundQty(returned+qty.base) > received {
+				return nil, fmt.Errorf("商品 %s 退货数量超过原入库数量，入库: %s，已退: %s，本次: %s",
+					product.SKU, formatQty(received), formatQty(returned), formatQty(qty.base))
+			}
+			if line.UnitCost == nil && receiptCost > 0 {
+				qty.unitCost = receiptCost
+			}
+		}
+		pending[product.ID] += qty.base
+
+		credit := round2(qty.base * qty.unitCost)
+		ret.Items = append(ret.Items, models.SupplierReturnItem{
+			ProductID:     product.ID,
+			LocationID:    line.LocationID,
+			Quantity:      qty.base,
+			EntryQuantity: qty.entered,
+			EntryUnit:     qty.unit,
+			UnitCost:      round2(qty.unitCost),
+			CreditAmount:  credit,
+			Reason:        line.Reason,
+		})
+		ret.TotalQuantity = models.RoundQty(ret.TotalQuantity+qty.base, models.MaxQtyPrecision)
+		ret.CreditAmount = round2(ret.CreditAmount + credit)
+	}
+
+	err = s.createWithDocumentNo(func() error {
+		ret.ReturnNo = "RTV-" + strings.Replace(time.Now().Format("20060102150405.000"), ".", "", 1)
+		return s.repo.CreateSupplierReturn(ret)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建退供应商单失败: %w", err)
+	}
+	return s.repo.GetSupplierReturnByID(ret.ID)
+}
+
+// ListSupplierReturns 获取退供应商单列表
+func (s *Service) ListSupplierReturns(query *models.PaginationQuery, supplierID *uint, status string) ([]models.SupplierReturn, int64, error) {
+	return s.repo.ListSupplierReturns(query, supplierID, status)
+}
+
+// GetSupplierReturn 获取退供应商单详情
+func (s *Service) GetSupplierReturn(id uint) (*models.SupplierReturn, error) {
+	return s.repo.GetSupplierReturnByID(id)
+}
+
+// ShipSupplierReturn 退货出库: 按明细写入退供应商记录并扣减库存, 单据进入待确认贷项状态
+func (s *Service) ShipSupplierReturn(id uint, operatorID uint, operatorName string) (*models.SupplierReturn, error) {
+	ret, err := s.repo.GetSupplierReturnByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("退供应商单不存在")
+	}
+	if ret.Status != models.SupplierReturnDraft {
+		return nil, fmt.Errorf("退供应商单状态为 %s，无法退货出库", ret.Status)
+	}
+
+	notes := "退供应商"
+	if ret.Supplier != nil {
+		notes += " " + ret.Supplier.Name
+	}
+	if ret.Reason != "" {
+		notes += ": " + ret.Reason
+	}
+
+	stock := make(map[uint]float64)     // 商品ID -> 本单累计扣减后的库存
+	binQty := make(map[[2]uint]float64) // [货位ID, 商品ID] -> 本单此前各行的数量变化
+	records := make([]*models.InventoryRecord, 0, len(ret.Items))
+	for _, item := range ret.Items {
+		product, err := s.repo.GetProductByID(item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("商品 %d 不存在", item.ProductID)
+		}
+		if err := checkStockable(product); err != nil {
+			return nil, err
+		}
+		beforeQty, ok := stock[product.ID]
+		if !ok {
+			if err := s.checkNotFrozen(product.ID); err != nil {
+				return nil, err
+			}
+			beforeQty = product.CurrentStock
+		}
+		if beforeQty < item.Quantity {
+			return nil, fmt.Errorf("商品 %s 库存不足，当前库存: %s，退货数量: %s", product.SKU, formatQty(beforeQty), formatQty(item.Quantity))
+		}
+		if item.LocationID != nil {
+			key := [2]uint{*item.LocationID, product.ID}
+			if err := s.checkBin(*item.LocationID, product.ID, -item.Quantity, 0, binQty[key]); err != nil {
+				return nil, err
+			}
+			binQty[key] -= item.Quantity
+		}
+		afterQty := product.RoundQty(beforeQty - item.Quantity)
+		stock[product.ID] = afterQty
+
+		itemNotes := notes
+		if item.Reason != "" {
+			itemNotes += " (" + item.Reason + ")"
+		}
+		records = append(records, &models.InventoryRecord{
+			ProductID:     product.ID,
+			Type:          models.StockReturn,
+			Quantity:      item.Quantity,
+			EntryQuantity: item.EntryQuantity,
+			EntryUnit:     item.EntryUnit,
+			BeforeQty:     beforeQty,
+			AfterQty:      afterQty,
+			UnitCost:      item.UnitCost,
+			TotalCost:     item.CreditAmount,
+			ReferenceNo:   ret.ReturnNo,
+			LocationID:    item.LocationID,
+			Notes:         itemNotes,
+			OperatorID:    operatorID,
+			OperatorName:  operatorName,
+		})
+	}
+
+	now := time.Now()
+	ret.Status = models.SupplierReturnShipped
+	ret.ShippedByName = operatorName
+	ret.ShippedAt = &now
+
+	if err := s.repo.ShipSupplierReturn(ret, records); err != nil {
+		return nil, fmt.Errorf("退货出库失败: %w", err)
+	}
+	return s.repo.GetSupplierReturnByID(id)
+}
+
+// ConfirmSupplierCredit 登记供应商确认的贷项, 未指定金额时按应退金额
+func (s *Service) ConfirmSupplierCredit(id uint, req *models.SupplierCreditRequest, operatorName string) (*models.SupplierReturn, error) {
+	ret, err := s.repo.GetSupplierReturnByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("退供应商单不存在")
+	}
+	if ret.Status != models.SupplierReturnShipped {
+		return nil, fmt.Errorf("退供应商单状态为 %s，无法确认贷项", ret.Status)
+	}
+
+	now := time.Now()
+	ret.Status = models.SupplierReturnCredited
+	ret.CreditedAmount = ret.CreditAmount
+	if req.CreditedAmount != nil {
+		ret.CreditedAmount = round2(*req.CreditedAmount)
+	}
+	ret.CreditNoteNo = req.CreditNoteNo
+	if req.Notes != "" {
+		if ret.Notes != "" {
+			ret.Notes += "; "
+		}
+		ret.Notes += req.Notes
+	}
+	ret.CreditedByName = operatorName
+	ret.CreditedAt = &now
+
+	if err := s.repo.UpdateSupplierReturn(ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// CancelSupplierReturn 取消草稿状态的退供应商单
+func (s *Service) CancelSupplierReturn(id uint) error {
+	ret, err := s.repo.GetSupplierReturnByID(id)
+	if err != nil {
+		return fmt.Errorf("退供应商单不存在")
+	}
+	if ret.Status != models.SupplierReturnDraft {
+		return fmt.Errorf("退供应商单状态为 %s，无法取消", ret.Status)
+	}
+	ret.Status = models.SupplierReturnCancelled
+	return s.repo.UpdateSupplierReturn(ret)
+}
+
+// GetSupplierReturnRateReport 供应商退货率报表
+// 入库按商品当前所属供应商归集, 退货按期间内退货出库的退供应商单归集; 按金额退货率从高到低排序
+func (s *Service) GetSupplierReturnRateReport(q *models.SupplierReturnRateQuery) (*models.SupplierReturnRateReport, error) {
+	from, to, err := parseDateRange(q.From, q.To, 90)
+	if err != nil {
+		return nil, err
+	}
+	receipts, err := s.repo.GetSupplierReceipts(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("统计入库失败: %w", err)
+	}
+	returns, err := s.repo.GetSupplierReturnTotals(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("统计退货失败: %w", err)
+	}
+	pending, err := s.repo.GetPendingSupplierCredits()
+	if err != nil {
+		return nil, fmt.Errorf("统计待确认贷项失败: %w", err)
+	}
+	suppliers, err := s.repo.GetAllSuppliers()
+	if err != nil {
+		return nil, fmt.Errorf("查询供应商失败: %w", err)
+	}
+
+	report := &models.SupplierReturnRateReport{
+		From:  from.Format("2006-01-02"),
+		To:    to.AddDate(0, 0, -1).Format("2006-01-02"),
+		Total: models.SupplierReturnRateRow{SupplierName: "合计"},
+		Items: []models.SupplierReturnRateRow{},
+	}
+	for _, sup := range suppliers {
+		rc, rt := receipts[sup.ID], returns[sup.ID]
+		row := models.SupplierReturnRateRow{
+			SupplierID:     sup.ID,
+			SupplierName:   sup.Name,
+			ReceivedQty:    models.RoundQty(rc.ReceivedQty, models.MaxQtyPrecision),
+			ReceivedValue:  round2(rc.ReceivedValue),
+			ReturnCount:    rt.ReturnCount,
+			ReturnedQty:    models.RoundQty(rt.ReturnedQty, models.MaxQtyPrecision),
+			ReturnedValue:  round2(rt.ReturnedValue),
+			PendingCredit:  round2(pending[sup.ID]),
+			CreditedAmount: round2(rt.CreditedAmount),
+		}
+		fillReturnRates(&row)
+		report.Items = append(report.Items, row)
+
+		t := &report.Total
+		t.ReceivedQty = models.RoundQty(t.ReceivedQty+row.ReceivedQty, models.MaxQtyPrecision)
+		t.ReceivedValue = round2(t.ReceivedValue + row.ReceivedValue)
+		t.ReturnCount += row.ReturnCount
+		t.ReturnedQty = models.RoundQty(t.ReturnedQty+row.ReturnedQty, models.MaxQtyPrecision)
+		t.ReturnedValue = round2(t.ReturnedValue + row.ReturnedValue)
+		t.PendingCredit = round2(t.PendingCredit + row.PendingCredit)
+		t.CreditedAmount = round2(t.CreditedAmount + row.CreditedAmount)
+	}
+	fillReturnRates(&report.Total)
+
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		return report.Items[i].ValueReturnRate > report.Items[j].ValueReturnRate
+	})
+	return report, nil
+}
+
+// fillReturnRates 计算退货率 (百分比), 无入库时为 0
+func fillReturnRates(row *models.SupplierReturnRateRow) {
+	if row.ReceivedQty > 0 {
+		row.QtyReturnRate = round2(row.ReturnedQty / row.ReceivedQty * 100)
+	}
+	if row.ReceivedValue > 0 {
+		row.ValueReturnRate = round2(row.ReturnedValue / row.ReceivedValue * 100)
+	}
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 计量单位 ====================
+
+// ListProductUnits 获取商品的基本单位与包装单位
+func (s *Service) ListProductUnits(productID uint) (*models.ProductUnitList, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	units, err := s.repo.ListProductUnits(productID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ProductUnitList{BaseUnit: product.Unit, Units: units}, nil
+}
+
+// CreateProductUnit 为商品添加包装单位
+func (s *Service) CreateProductUnit(productID uint, req *models.ProductUnitRequest) (*models.ProductUnit, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+
+	unit := &models.ProductUnit{ProductID: productID}
+	if err := s.applyProductUnit(unit, product, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateProductUnit(unit); err != nil {
+		return nil, fmt.Errorf("创建包装单位失败: %w", err)
+	}
+	return unit, nil
+}
+
+// UpdateProductUnit 更新包装单位 (已有库存记录保留录入时的换算结果)
+func (s *Service) UpdateProductUnit(productID, id uint, req *models.ProductUnitRequest) (*models.ProductUnit, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	unit, err := s.repo.GetProductUnit(productID, id)
+	if err != nil {
+		return nil, fmt.Errorf("包装单位不存在")
+	}
+
+	if err := s.applyProductUnit(unit, product, req); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateProductUnit(unit); err != nil {
+		return nil, fmt.Errorf("更新包装单位失败: %w", err)
+	}
+	return unit, nil
+}
+
+// DeleteProductUnit 删除包装单位
+func (s *Service) DeleteProductUnit(productID, id uint) error {
+	if _, err := s.repo.GetProductUnit(productID, id); err != nil {
+		return fmt.Errorf("包装单位不存在")
+	}
+	return s.repo.DeleteProductUnit(id)
+}
+
+// applyProductUnit 校验包装单位: 名称不能与基本单位或其他包装单位重复
+func (s *Service) applyProductUnit(unit *models.ProductUnit, product *models.Product, req *models.ProductUnitRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return fmt.Errorf("单位名称不能为空")
+	}
+	if name == product.Unit {
+		return fmt.Errorf("%s 是商品的基本单位", name)
+	}
+	if existing, err := s.repo.GetProductUnitByName(product.ID, name); err == nil && existing.ID != unit.ID {
+		return fmt.Errorf("单位 %s 已存在", name)
+	}
+
+	unit.Name = name
+	unit.Level = req.Level
+	unit.Factor = req.Factor
+	return nil
+}
+
+// unitQuantity 按录入单位换算后的数量
+type unitQuantity struct {
+	unit     string  // 录入单位
+	entered  float64 // 录入数量
+	base     float64 // 基本单位数量
+	unitCost float64 // 每基本单位成本
+}
+
+// toBaseUnit 将录入单位的数量和单位成本换算为基本单位; unit 为空时为基本单位
+// 换算后的基本单位数量须符合商品的数量精度
+func (s *Service) toBaseUnit(product *models.Product, unit string, quantity float64, unitCost float64) (*unitQuantity, error) {
+	q := &unitQuantity{unit: product.Unit, entered: quantity, base: quantity, unitCost: unitCost}
+	if unit != "" && unit != product.Unit {
+		pu, err := s.repo.GetProductUnitByName(product.ID, unit)
+		if err != nil {
+			return nil, fmt.Errorf("商品 %s 未定义单位: %s", product.SKU, unit)
+		}
+		q.unit = pu.Name
+		q.base = quantity * float64(pu.Factor)
+		q.unitCost = unitCost / float64(pu.Factor)
+	}
+	if err := checkQty(product, q.base); err != nil {
+		return nil, err
+	}
+	q.base = product.RoundQty(q.base)
+	return q, nil
+}
+
+// checkQty 校验数量符合商品的数量精度
+func checkQty(product *models.Product, quantity float64) error {
+	if quantity < 0 {
+		return fmt.Errorf("数量不能为负数")
+	}
+	if !product.ValidQty(quantity) {
+		if product.QtyPrecision == 0 {
+			return fmt.Errorf("商品 %s 的数量须为整数: %s", product.SKU, formatQty(quantity))
+		}
+		return fmt.Errorf("商品 %s 的数量最多 %d 位小数: %s", product.SKU, product.QtyPrecision, formatQty(quantity))
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"go-cargo/internal/models"
+)
+
+// ==================== 多规格商品 ====================
+
+// maxVariants 单个款式最多的规格数
+const maxVariants = 500
+
+// ListProductAttributes 获取款式的规格属性
+func (s *Service) ListProductAttributes(productID uint) ([]models.ProductAttribute, error) {
+	if _, err := s.repo.GetProductByID(productID); err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	return s.repo.ListProductAttributes(productID)
+}
+
+// SetProductAttributes 设置款式的规格属性, 商品随之成为 (或不再是) 多规格款式
+// 已有规格时属性名称不能增删, 且已使用的取值不能移除
+func (s *Service) SetProductAttributes(productID uint, req *models.ProductAttributesRequest) ([]models.ProductAttribute, error) {
+	product, err := s.repo.GetProductByID(productID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	if product.ParentID != nil {
+		return nil, fmt.Errorf("规格商品不能再定义规格属性")
+	}
+	if product.KitType != "" {
+		return nil, fmt.Errorf("组合商品不能设为多规格款式")
+	}
+	if !product.HasVariants && product.CurrentStock != 0 {
+		return nil, fmt.Errorf("商品 %s 有库存 %s，请先清零再设为多规格款式", product.SKU, formatQty(product.CurrentStock))
+	}
+
+	attrs := make([]models.ProductAttribute, 0, len(req.Attributes))
+	options := make(map[string]map[string]bool, len(req.Attributes))
+	for i, a := range req.Attributes {
+		name := strings.TrimSpace(a.Name)
+		if name == "" {
+			return nil, fmt.Errorf("属性名称不能为空")
+		}
+		if options[name] != nil {
+			return nil, fmt.Errorf("属性 %s 重复", name)
+		}
+		options[name] = make(map[string]bool, len(a.Options))
+		values := make([]string, 0, len(a.Options))
+		for _, o := range a.Options {
+			o = strings.TrimSpace(o)
+			if o == "" || strings.Contains(o, ",") {
+				return nil, fmt.Errorf("属性 %s 的取值不能为空或包含逗号", name)
+			}
+			if options[name][o] {
+				return nil, fmt.Errorf("属性 %s 的取值 %s 重复", name, o)
+			}
+			options[name][o] = true
+			values = append(values, o)
+		}
+		attrs = append(attrs, models.ProductAttribute{
+			ProductID: productID,
+			Name:      name,
+			Options:   strings.Join(values, ","),
+			SortOrder: i,
+		})
+	}
+
+	variants, err := s.repo.ListVariants(productID)
+	if err != nil {
+		return nil, fmt.Errorf("查询规格失败: %w", err)
+	}
+	for _, v := range variants {
+		if len(v.Attributes) != len(options) {
+			return nil, fmt.Errorf("款式已有规格，不能增删属性")
+		}
+		for _, av := range v.Attributes {
+			if options[av.Name] == nil {
+				return nil, fmt.Errorf("款式已有规格，不能增删属性")
+			}
+			if !options[av.Name][av.Value] {
+				return nil, fmt.Errorf("规格 %s 使用了属性 %s 的取值 %s，不能移除", v.SKU, av.Name, av.Value)
+			}
+		}
+	}
+
+	product.HasVariants = len(attrs) > 0
+	if err := s.repo.SaveProductAttributes(product, attrs); err != nil {
+		return nil, fmt.Errorf("保存规格属性失败: %w", err)
+	}
+	return attrs, nil
+}
+
+// GenerateVariants 按规格属性的组合生成规格商品, 已存在的组合跳过
+// 规格 SKU 为 款式SKU-取值1-取值2, 名称、分类、供应商、单位与价格继承款式
+func (s *Service) GenerateVariants(parentID uint, req *models.GenerateVariantsRequest) (*models.GenerateVariantsResult, error) {
+	parent, err := s.repo.GetProductByID(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	attrs, err := s.repo.ListProductAttributes(parentID)
+	if err != nil {
+		return nil, err
+	}
+	if !parent.HasVariants || len(attrs) == 0 {
+		return nil, fmt.Errorf("商品 %s 未定义规格属性", parent.SKU)
+	}
+
+	// 每个属性参与组合的取值
+	names := make([]string, len(attrs))
+	choices := make([][]string, len(attrs))
+	for i, a := range attrs {
+		names[i] = a.Name
+		choices[i] = strings.Split(a.Options, ",")
+	}
+	for name, selected := range req.Options {
+		i := indexOf(names, name)
+		if i < 0 {
+			return nil, fmt.Errorf("款式未定义属性 %s", name)
+		}
+		var filtered []string
+		for _, v := range selected {
+			if indexOf(choices[i], v) < 0 {
+				return nil, fmt.Errorf("属性 %s 没有取值 %s", name, v)
+			}
+			if indexOf(filtered, v) < 0 {
+				filtered = append(filtered, v)
+			}
+		}
+		// 保持属性定义中的顺序
+		ordered := make([]string, 0, len(filtered))
+		for _, v := range choices[i] {
+			if indexOf(filtered, v) >= 0 {
+				ordered = append(ordered, v)
+			}
+		}
+		choices[i] = ordered
+	}
+
+	total := 1
+	for _, c := range choices {
+		total *= len(c)
+		if total > maxVariants {
+			return nil, fmt.Errorf("单个款式最多 %d 个规格", maxVariants)
+		}
+	}
+
+	existing, err := s.repo.ListVariants(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("查询规格失败: %w", err)
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, v := range existing {
+		seen[variantKey(names, v.Attributes)] = true
+	}
+
+	result := &models.GenerateVariantsResult{Created: []models.Product{}}
+	var created []*models.Product
+	for _, combo := range combinations(choices) {
+		values := make([]models.ProductVariantValue, len(combo))
+		for i, v := range combo {
+			values[i] = models.ProductVariantValue{Name: names[i], Value: v}
+		}
+		if seen[variantKey(names, values)] {
+			result.Skipped++
+			continue
+		}
+		if len(existing)+len(created) >= maxVariants {
+			return nil, fmt.Errorf("单个款式最多 %d 个规格", maxVariants)
+		}
+
+		sku := parent.SKU
+		for _, v := range combo {
+			sku += "-" + strings.ToUpper(strings.Join(strings.Fields(v), ""))
+		}
+		if len([]rune(sku)) > 50 {
+			return nil, fmt.Errorf("规格 SKU %s 超过 50 个字符", sku)
+		}
+		if _, err := s.repo.GetProductBySKU(sku); err == nil {
+			return nil, fmt.Errorf("SKU '%s' 已存在", sku)
+		}
+
+		pid := parent.ID
+		variant := &models.Product{
+			SKU:         sku,
+			Description: parent.Description,
+			ParentID:    &pid,
+			VariantName: strings.Join(combo, " / "),
+			Attributes:  values,
+			Status:      1,
+		}
+		inheritFromParent(variant, parent)
+		created = append(created, variant)
+	}
+
+	if len(created) > 0 {
+		if err := s.repo.CreateVariants(created); err != nil {
+			return nil, fmt.Errorf("生成规格失败: %w", err)
+		}
+	}
+	for _, v := range created {
+		result.Created = append(result.Created, *v)
+	}
+	return result, nil
+}
+
+// ListVariants 获取款式下的规格
+func (s *Service) ListVariants(parentID uint) ([]models.Product, error) {
+	if _, err := s.repo.GetProductByID(parentID); err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	return s.repo.ListVariants(parentID)
+}
+
+// UpdateVariant 更新规格的条码、库位、库存预警与价格
+// 价格均未提供时恢复跟随款式价格
+func (s *Service) UpdateVariant(parentID, id uint, req *models.VariantRequest) (*models.Product, error) {
+	parent, err := s.repo.GetProductByID(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("商品不存在")
+	}
+	variant, err := s.repo.GetVariant(parentID, id)
+	if err != nil {
+		return nil, fmt.Errorf("规格不存在")
+	}
+	if err := s.checkBarcodeNotAlternate(req.Barcode, id); err != nil {
+		return nil, err
+	}
+
+	variant.Barcode = req.Barcode
+	variant.Location = req.Location
+	variant.MinStock = req.MinStock
+	variant.MaxStock = req.MaxStock
+	variant.SafetyStock = req.SafetyStock
+	if req.Status != 0 {
+		variant.Status = req.Status
+	}
+	variant.PriceOverride = req.CostPrice != nil || req.SellingPrice != nil
+	variant.CostPrice, variant.SellingPrice = parent.CostPrice, parent.SellingPrice
+	if req.CostPrice != nil {
+		variant.CostPrice = *req.CostPrice
+	}
+	if req.SellingPrice != nil {
+		variant.SellingPrice = *req.SellingPrice
+	}
+	if err := checkStockLevels(variant); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveProducts([]*models.Product{variant}); err != nil {
+		return nil, fmt.Errorf("更新规格失败: %w", err)
+	}
+	return variant, nil
+}
+
+// DeleteVariant 删除规格 (须无库存)
+func (s *Service) DeleteVariant(parentID, id uint) error {
+	variant, err := s.repo.GetVariant(parentID, id)
+	if err != nil {
+		return fmt.Errorf("规格不存在")
+	}
+	if variant.CurrentStock != 0 {
+		return fmt.Errorf("规格 %s 仍有库存 %s，无法删除", variant.SKU, formatQty(variant.CurrentStock))
+	}
+	return s.repo.DeleteProduct(id)
+}
+
+// syncVariants 款式更新后同步规格的名称、分类、供应商、单位与未单独定价的价格
+func (s *Service) syncVariants(parent *models.Product) error {
+	variants, err := s.repo.ListVariants(parent.ID)
+	if err != nil {
+		return fmt.Errorf("查询规格失败: %w", err)
+	}
+	updated := make([]*models.Product, 0, len(variants))
+	for i := range variants {
+		v := &variants[i]
+		inheritFromParent(v, parent)
+		if !v.ValidQty(v.CurrentStock) {
+			return fmt.Errorf("规格 %s 的库存 %s 不符合新的数量精度，请先调整库存", v.SKU, formatQty(v.CurrentStock))
+		}
+		updated = append(updated, v)
+	}
+	return s.repo.SaveProducts(updated)
+}
+
+// inheritFromParent 规格继承款式的公共属性
+func inheritFromParent(variant, parent *models.Product) {
+	variant.Name = parent.Name + " " + variant.VariantName
+	variant.CategoryID = parent.CategoryID
+	variant.SupplierID = parent.SupplierID
+	variant.Unit = parent.Unit
+	variant.QtyPrecision = parent.QtyPrecision
+	if !variant.PriceOverride {
+		variant.CostPrice = parent.CostPrice
+		variant.SellingPrice = parent.SellingPrice
+	}
+}
+
+// checkStockable 款式与虚拟组合商品本身不持有库存
+func checkStockable(product *models.Product) error {
+	if product.HasVariants {
+		return fmt.Errorf("商品 %s 为多规格款式，请选择具体规格出入库", product.SKU)
+	}
+	if product.KitType == models.KitTypeVirtual {
+		return fmt.Errorf("商品 %s 为虚拟组合商品，库存由组件决定，仅支持单笔出库", product.SKU)
+	}
+	return nil
+}
+
+// variantKey 按属性顺序拼接规格取值, 用于判断组合是否已存在
+func variantKey(names []string, values []models.ProductVariantValue) string {
+	parts := make([]string, len(names))
+	for _, v := range values {
+		if i := indexOf(names, v.Name); i >= 0 {
+			parts[i] = v.Value
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// combinations 按顺序生成各组取值的笛卡尔积
+func combinations(choices [][]string) [][]string {
+	result := [][]string{{}}
+	for _, values := range choices {
+		next := make([][]string, 0, len(result)*len(values))
+		for _, prefix := range result {
+			for _, v := range values {
+				combo := append(append([]string{}, prefix...), v)
+				next = append(next, combo)
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+// indexOf 返回字符串在切片中的位置, 不存在时返回 -1
+func indexOf(values []string, v string) int {
+	for i, s := range values {
+		if s == v {
+			return i
+		}
+	}
+	return -1
+}